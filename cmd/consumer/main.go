@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bank-api/internal/pkg/components"
+	"bank-api/internal/pkg/logging"
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	container, err := components.NewConsumerContainer()
+	if err != nil {
+		log.Fatalf("Failed to initialize consumer: %v", err)
+	}
+
+	logging.Info("Bank consumer initialized successfully", map[string]interface{}{
+		"version":     "1.0.0",
+		"environment": container.GetConfig().Environment,
+	})
+
+	// Consume until SIGINT/SIGTERM, then drain and shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := container.RunConsumers(ctx); err != nil {
+		log.Fatalf("Failed to run consumers: %v", err)
+	}
+}
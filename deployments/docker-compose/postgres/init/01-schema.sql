@@ -10,14 +10,28 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE TABLE accounts (
     id SERIAL PRIMARY KEY,
     owner VARCHAR(255) NOT NULL,
-    balance DECIMAL(15,2) NOT NULL DEFAULT 0,
+    balance DECIMAL(16,3) NOT NULL DEFAULT 0, -- major unit of the account currency (up to 3 decimals, e.g. KWD)
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
     version INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(10) NOT NULL DEFAULT 'active',
+    overdraft_limit BIGINT NOT NULL DEFAULT 0, -- minor units of the account currency
+    currency CHAR(3) NOT NULL DEFAULT 'BRL', -- ISO 4217
+    frozen BOOLEAN NOT NULL DEFAULT FALSE, -- fraud hold: blocks money movements
+    account_type VARCHAR(10) NOT NULL DEFAULT 'checking', -- checking or savings
+    client_request_id VARCHAR(128), -- makes account creation idempotent when set
+    deleted_at TIMESTAMP, -- soft delete: hidden from reads while set
 
     -- Constraints
-    CONSTRAINT positive_balance CHECK (balance >= 0),
-    CONSTRAINT valid_owner CHECK (length(owner) > 0)
+    CONSTRAINT balance_within_overdraft CHECK (
+        balance >= -(overdraft_limit / CASE currency WHEN 'JPY' THEN 1.0 WHEN 'KWD' THEN 1000.0 ELSE 100.0 END)
+    ),
+    CONSTRAINT non_negative_overdraft_limit CHECK (overdraft_limit >= 0),
+    CONSTRAINT valid_owner CHECK (length(owner) > 0),
+    CONSTRAINT valid_status CHECK (status IN ('active', 'closed')),
+    CONSTRAINT valid_currency CHECK (currency ~ '^[A-Z]{3}$'),
+    CONSTRAINT valid_account_type CHECK (account_type IN ('checking', 'savings')),
+    CONSTRAINT unique_client_request_id UNIQUE (client_request_id)
 );
 
 -- Transactions Table
@@ -26,17 +40,21 @@ CREATE TABLE transactions (
     id SERIAL PRIMARY KEY,
     account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
     transaction_type VARCHAR(20) NOT NULL,
-    amount DECIMAL(15,2) NOT NULL,
-    balance_after DECIMAL(15,2) NOT NULL,
+    amount DECIMAL(16,3) NOT NULL,
+    balance_after DECIMAL(16,3) NOT NULL,
     reference_id UUID,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     metadata JSONB,
 
     -- Constraints
     CONSTRAINT valid_transaction_type CHECK (
-        transaction_type IN ('deposit', 'withdraw', 'transfer_in', 'transfer_out')
+        transaction_type IN ('deposit', 'withdraw', 'transfer_in', 'transfer_out', 'interest')
     ),
-    CONSTRAINT positive_amount CHECK (amount > 0)
+    -- Signed amounts: credits positive, debits negative
+    CONSTRAINT signed_amount CHECK (
+        (transaction_type IN ('deposit', 'transfer_in', 'interest') AND amount > 0) OR
+        (transaction_type IN ('withdraw', 'transfer_out') AND amount < 0)
+    )
 );
 
 -- Performance Indexes
@@ -47,6 +65,39 @@ CREATE INDEX idx_transactions_account ON transactions(account_id, created_at DES
 CREATE INDEX idx_transactions_reference ON transactions(reference_id)
     WHERE reference_id IS NOT NULL;
 
+-- Account Holds Table
+-- Reserved funds; available balance = balance - sum of active holds
+CREATE TABLE account_holds (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(16,3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, released or captured
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP,
+
+    -- Constraints
+    CONSTRAINT valid_hold_status CHECK (status IN ('active', 'released', 'captured')),
+    CONSTRAINT positive_hold_amount CHECK (amount > 0)
+);
+
+CREATE INDEX idx_account_holds_active ON account_holds(account_id) WHERE status = 'active';
+
+-- Operation Status Table
+-- Outcome of asynchronous operations (deposits) by operation_id
+CREATE TABLE operation_status (
+    operation_id VARCHAR(64) PRIMARY KEY,
+    operation_type VARCHAR(20) NOT NULL,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(16,3) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending', -- pending, completed, failed or duplicate
+    reason VARCHAR(50), -- why a failed operation failed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Constraints
+    CONSTRAINT valid_operation_status CHECK (status IN ('pending', 'completed', 'failed', 'duplicate'))
+);
+
 -- Index for account lookups by owner
 CREATE INDEX idx_accounts_owner ON accounts(owner);
 
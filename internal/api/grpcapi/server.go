@@ -0,0 +1,301 @@
+// Package grpcapi exposes the banking operations over gRPC, alongside the REST handlers.
+//
+// Stubs in bankingpb are generated from proto/banking/v1/banking.proto:
+//
+//go:generate sh -c "cd proto && buf generate"
+package grpcapi
+
+import (
+	"bank-api/internal/api/grpcapi/bankingpb"
+	"bank-api/internal/api/handlers"
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/idempotency"
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/internal/pkg/validation"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BankingService implements bankingpb.BankingServiceServer using the same repository
+// and event publisher as the REST handlers
+type BankingService struct {
+	bankingpb.UnimplementedBankingServiceServer
+
+	db        database.Repository
+	publisher messaging.EventPublisher
+}
+
+// NewBankingService creates the gRPC service from the container dependencies
+func NewBankingService(container handlers.HandlerDependencies) *BankingService {
+	return &BankingService{
+		db:        container.GetDatabase(),
+		publisher: container.GetEventPublisher(),
+	}
+}
+
+// NewServer creates a gRPC server with the banking service registered
+func NewServer(container handlers.HandlerDependencies) *grpc.Server {
+	server := grpc.NewServer()
+	bankingpb.RegisterBankingServiceServer(server, NewBankingService(container))
+	return server
+}
+
+// CreateAccount creates a new account
+func (s *BankingService) CreateAccount(ctx context.Context, req *bankingpb.CreateAccountRequest) (*bankingpb.CreateAccountResponse, error) {
+	if err := validation.ValidateOwnerName(req.GetOwner()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id := s.db.CreateAccount(ctx, req.GetOwner())
+	if id == 0 {
+		return nil, status.Error(codes.Internal, "failed to create account")
+	}
+
+	metrics.RecordAccountCreation()
+
+	event := messaging.AccountCreatedEvent{
+		AccountID: id,
+		Owner:     req.GetOwner(),
+		Timestamp: time.Now(),
+	}
+	if err := s.publisher.PublishAccountCreated(event); err != nil {
+		logging.Error("Failed to publish account created event", err, map[string]interface{}{
+			"account_id": id,
+			"transport":  "grpc",
+		})
+		// Don't fail the request if event publishing fails (graceful degradation)
+	}
+
+	return &bankingpb.CreateAccountResponse{Id: int64(id), Owner: req.GetOwner()}, nil
+}
+
+// GetBalance returns the current balance of an account
+func (s *BankingService) GetBalance(ctx context.Context, req *bankingpb.GetBalanceRequest) (*bankingpb.GetBalanceResponse, error) {
+	id := int(req.GetAccountId())
+	if err := validation.ValidateAccountID(id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	account, ok := s.db.GetAccount(ctx, id)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+
+	metrics.RecordAccountBalance(float64(account.Balance))
+
+	return &bankingpb.GetBalanceResponse{
+		Id:      int64(account.Id),
+		Owner:   account.Owner,
+		Balance: int64(account.Balance),
+	}, nil
+}
+
+// Deposit publishes a deposit request and returns its operation ID (async, like the REST 202)
+func (s *BankingService) Deposit(ctx context.Context, req *bankingpb.DepositRequest) (*bankingpb.OperationAccepted, error) {
+	id, amount, err := s.validateAsyncOperation(ctx, req.GetAccountId(), req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	operationID := uuid.New().String()
+	event := messaging.DepositRequestedEvent{
+		OperationID:    operationID,
+		IdempotencyKey: idempotency.GenerateKey("deposit", id, amount),
+		AccountID:      id,
+		Amount:         amount,
+		Timestamp:      time.Now(),
+	}
+
+	// Pending before publishing, so the operation can be looked up via GET /operations/:id
+	if err := s.db.CreatePendingOperation(ctx, operationID, "deposit", id, amount); err != nil {
+		logging.Error("Failed to record deposit operation", err, map[string]interface{}{
+			"operation_id": operationID,
+			"account_id":   id,
+			"transport":    "grpc",
+		})
+		metrics.RecordBankingOperation("deposit", "error")
+		return nil, status.Error(codes.Internal, "failed to process deposit request")
+	}
+
+	if err := s.publisher.PublishDepositRequested(event); err != nil {
+		logging.Error("Failed to publish deposit request event", err, map[string]interface{}{
+			"operation_id": operationID,
+			"account_id":   id,
+			"transport":    "grpc",
+		})
+		if err := s.db.SetOperationStatus(ctx, operationID, models.OperationStatusFailed, messaging.FailureReasonPublishFailed); err != nil {
+			logging.Error("Failed to record deposit operation status", err, map[string]interface{}{
+				"operation_id": operationID,
+				"transport":    "grpc",
+			})
+		}
+		metrics.RecordBankingOperation("deposit", "error")
+		return nil, status.Error(codes.Internal, "failed to process deposit request")
+	}
+
+	metrics.RecordBankingOperation("deposit", "accepted")
+
+	return &bankingpb.OperationAccepted{
+		OperationId: operationID,
+		Status:      "accepted",
+		Message:     "Deposit request accepted and will be processed asynchronously",
+	}, nil
+}
+
+// Withdraw publishes a withdrawal request and returns its operation ID
+func (s *BankingService) Withdraw(ctx context.Context, req *bankingpb.WithdrawRequest) (*bankingpb.OperationAccepted, error) {
+	id, amount, err := s.validateAsyncOperation(ctx, req.GetAccountId(), req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	operationID := uuid.New().String()
+	event := messaging.WithdrawalRequestedEvent{
+		OperationID:    operationID,
+		IdempotencyKey: idempotency.GenerateKey("withdraw", id, amount),
+		AccountID:      id,
+		Amount:         amount,
+		Timestamp:      time.Now(),
+	}
+
+	// Pending before publishing, so the operation can be looked up via GET /operations/:id
+	if err := s.db.CreatePendingOperation(ctx, operationID, "withdraw", id, amount); err != nil {
+		logging.Error("Failed to record withdrawal operation", err, map[string]interface{}{
+			"operation_id": operationID,
+			"account_id":   id,
+			"transport":    "grpc",
+		})
+		metrics.RecordBankingOperation("withdraw", "error")
+		return nil, status.Error(codes.Internal, "failed to process withdrawal request")
+	}
+
+	if err := s.publisher.PublishWithdrawalRequested(event); err != nil {
+		logging.Error("Failed to publish withdrawal request event", err, map[string]interface{}{
+			"operation_id": operationID,
+			"account_id":   id,
+			"transport":    "grpc",
+		})
+		if err := s.db.SetOperationStatus(ctx, operationID, models.OperationStatusFailed, messaging.FailureReasonPublishFailed); err != nil {
+			logging.Error("Failed to record withdrawal operation status", err, map[string]interface{}{
+				"operation_id": operationID,
+				"transport":    "grpc",
+			})
+		}
+		metrics.RecordBankingOperation("withdraw", "error")
+		return nil, status.Error(codes.Internal, "failed to process withdrawal request")
+	}
+
+	metrics.RecordBankingOperation("withdraw", "accepted")
+
+	return &bankingpb.OperationAccepted{
+		OperationId: operationID,
+		Status:      "accepted",
+		Message:     "Withdrawal request accepted and will be processed asynchronously",
+	}, nil
+}
+
+// Transfer moves money between two accounts synchronously
+func (s *BankingService) Transfer(ctx context.Context, req *bankingpb.TransferRequest) (*bankingpb.TransferResponse, error) {
+	fromID, toID, amount := int(req.GetFromId()), int(req.GetToId()), int(req.GetAmount())
+
+	if err := validation.ValidateAccountID(fromID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid from account ID: "+err.Error())
+	}
+	if err := validation.ValidateAccountID(toID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid to account ID: "+err.Error())
+	}
+	if fromID == toID {
+		return nil, status.Error(codes.InvalidArgument, "cannot transfer to the same account")
+	}
+
+	// Limits depend on the source account's currency
+	source, ok := s.db.GetAccount(ctx, fromID)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	if err := validation.ValidateAmount(amount, source.Currency); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	from, to, err := s.db.AtomicTransfer(ctx, fromID, toID, amount)
+	if err != nil {
+		metrics.RecordBankingOperation("transfer", "error")
+
+		switch {
+		case errors.Is(err, postgres.ErrAccountClosed):
+			return nil, status.Error(codes.FailedPrecondition, "account is closed")
+		case errors.Is(err, postgres.ErrAccountFrozen):
+			return nil, status.Error(codes.FailedPrecondition, "account is frozen")
+		case errors.Is(err, postgres.ErrCurrencyMismatch):
+			return nil, status.Error(codes.FailedPrecondition, "accounts hold different currencies")
+		case errors.Is(err, postgres.ErrBalanceLimitExceeded):
+			return nil, status.Error(codes.FailedPrecondition, "transfer would exceed the maximum account balance")
+		case strings.Contains(err.Error(), "insufficient balance"):
+			return nil, status.Error(codes.FailedPrecondition, "insufficient funds")
+		default:
+			return nil, status.Error(codes.NotFound, "account not found")
+		}
+	}
+
+	metrics.RecordBankingOperation("transfer", "success")
+	metrics.RecordTransferAmount(float64(amount))
+
+	event := messaging.TransferCompletedEvent{
+		FromAccountID:    from.Id,
+		ToAccountID:      to.Id,
+		Amount:           amount,
+		FromBalanceAfter: from.Balance,
+		ToBalanceAfter:   to.Balance,
+		Timestamp:        time.Now(),
+	}
+	if err := s.publisher.PublishTransferCompleted(event); err != nil {
+		logging.Error("Failed to publish transfer completed event", err, map[string]interface{}{
+			"from_account_id": from.Id,
+			"to_account_id":   to.Id,
+			"transport":       "grpc",
+		})
+	}
+
+	return &bankingpb.TransferResponse{
+		FromId:      int64(from.Id),
+		ToId:        int64(to.Id),
+		FromBalance: int64(from.Balance),
+		ToBalance:   int64(to.Balance),
+		Transferred: int64(amount),
+	}, nil
+}
+
+// validateAsyncOperation performs the fail-fast checks shared by Deposit and Withdraw
+func (s *BankingService) validateAsyncOperation(ctx context.Context, accountID, amount int64) (int, int, error) {
+	id := int(accountID)
+	if err := validation.ValidateAccountID(id); err != nil {
+		return 0, 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	account, ok := s.db.GetAccount(ctx, id)
+	if !ok {
+		return 0, 0, status.Error(codes.NotFound, "account not found")
+	}
+	if account.Status == models.AccountStatusClosed {
+		return 0, 0, status.Error(codes.FailedPrecondition, "account is closed")
+	}
+	if account.Frozen {
+		return 0, 0, status.Error(codes.FailedPrecondition, "account is frozen")
+	}
+	if err := validation.ValidateAmount(int(amount), account.Currency); err != nil {
+		return 0, 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return id, int(amount), nil
+}
@@ -2,13 +2,21 @@ package handlers
 
 import (
 	"bank-api/internal/domain/account"
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
 	"bank-api/internal/infrastructure/messaging"
 	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/idempotency"
 	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/money"
 	"bank-api/internal/pkg/telemetry"
 	"bank-api/internal/pkg/validation"
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,10 +29,15 @@ func MakeCreateAccountHandler(container HandlerDependencies) gin.HandlerFunc {
 
 	return func(ctx *gin.Context) {
 		var req struct {
-			Owner string `json:"owner"`
+			Owner    string `json:"owner"`
+			Currency string `json:"currency"` // Optional ISO 4217 code, defaults to BRL
+			Type     string `json:"type"`     // Optional checking or savings, defaults to checking
+
+			// Optional; replaying the same ID returns the account it created instead of a new one
+			ClientRequestID string `json:"client_request_id"`
 		}
 
-		if err := ctx.ShouldBindJSON(&req); err != nil {
+		if err := bindJSON(ctx, &req); err != nil {
 			apiErr := errors.NewValidationError("Invalid request format")
 			logging.Warn("Invalid JSON in create account request", map[string]interface{}{
 				"error": err.Error(),
@@ -45,7 +58,63 @@ func MakeCreateAccountHandler(container HandlerDependencies) gin.HandlerFunc {
 			return
 		}
 
-		id := db.CreateAccount(req.Owner)
+		if req.Currency == "" {
+			req.Currency = models.DefaultCurrency
+		}
+		if err := validation.ValidateCurrency(req.Currency); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			ctx.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if req.Type == "" {
+			req.Type = models.AccountTypeChecking
+		}
+		if err := validation.ValidateAccountType(req.Type); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			ctx.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		req.ClientRequestID = strings.TrimSpace(req.ClientRequestID)
+		if len(req.ClientRequestID) > idempotency.MaxClientKeyLength {
+			apiErr := errors.NewValidationError(fmt.Sprintf("client_request_id must be at most %d characters", idempotency.MaxClientKeyLength))
+			ctx.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		var id int
+		if req.ClientRequestID != "" {
+			acc, created, err := db.CreateAccountIdempotent(ctx.Request.Context(), req.Owner, req.Currency, req.Type, req.ClientRequestID)
+			if err != nil {
+				logging.Error("Failed to create account", err, map[string]interface{}{
+					"owner":             req.Owner,
+					"client_request_id": req.ClientRequestID,
+				})
+				apiErr := errors.NewInternalServerError("failed to create account")
+				ctx.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			if !created {
+				// Replayed request - the account already exists, so no metrics or event
+				logging.Info("Account creation replayed", map[string]interface{}{
+					"account_id":        acc.Id,
+					"client_request_id": req.ClientRequestID,
+					"ip":                ctx.ClientIP(),
+				})
+				ctx.JSON(http.StatusOK, gin.H{"id": acc.Id, "owner": acc.Owner, "currency": acc.Currency, "minor_units": money.MinorUnits(acc.Currency), "account_type": acc.AccountType})
+				return
+			}
+			id = acc.Id
+		} else {
+			id = db.CreateAccountWithType(ctx.Request.Context(), req.Owner, req.Currency, req.Type)
+			if id == 0 {
+				apiErr := errors.NewInternalServerError("failed to create account")
+				ctx.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
 
 		// Record metrics
 		metrics.RecordAccountCreation()
@@ -67,10 +136,149 @@ func MakeCreateAccountHandler(container HandlerDependencies) gin.HandlerFunc {
 		logging.Info("Account created successfully", map[string]interface{}{
 			"account_id": id,
 			"owner":      req.Owner,
+			"type":       req.Type,
 			"ip":         ctx.ClientIP(),
 		})
 
-		ctx.JSON(http.StatusCreated, gin.H{"id": id, "owner": req.Owner})
+		// Amounts on this account are in its currency's minor units: cents for BRL, yen for JPY
+		ctx.JSON(http.StatusCreated, gin.H{"id": id, "owner": req.Owner, "currency": req.Currency, "minor_units": money.MinorUnits(req.Currency), "account_type": req.Type})
+	}
+}
+
+// maxBulkAccounts bounds a single bulk creation so one request can't hold a transaction open too long
+const maxBulkAccounts = 1000
+
+// MakeBulkCreateAccountsHandler opens many accounts in one database transaction for test and
+// load-test setup. Owners are named "<owner_prefix>-1".."<owner_prefix>-N"; no per-account
+// events are published.
+func MakeBulkCreateAccountsHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		var req struct {
+			Count          int    `json:"count"`
+			InitialBalance int    `json:"initial_balance"` // Optional opening credit per account, in cents
+			OwnerPrefix    string `json:"owner_prefix"`
+		}
+
+		if err := bindJSON(c, &req); err != nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if req.Count < 1 || req.Count > maxBulkAccounts {
+			apiErr := errors.NewValidationError("count must be between 1 and " + strconv.Itoa(maxBulkAccounts))
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		req.OwnerPrefix = strings.TrimSpace(req.OwnerPrefix)
+		// The longest generated name must still be a valid owner
+		if err := validation.ValidateOwnerName(req.OwnerPrefix + "-" + strconv.Itoa(req.Count)); err != nil || req.OwnerPrefix == "" {
+			apiErr := errors.NewValidationError("owner_prefix must produce valid owner names")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if req.InitialBalance != 0 {
+			// Bulk accounts are opened in the default currency
+			if err := validation.ValidateAmount(req.InitialBalance, models.DefaultCurrency); err != nil {
+				apiErr := errors.NewInvalidAmountError(err.Error())
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		ids, err := db.CreateAccountsBulk(c.Request.Context(), req.OwnerPrefix, req.Count, req.InitialBalance)
+		if err != nil {
+			var apiErr errors.APIError
+			if stderrors.Is(err, postgres.ErrBalanceLimitExceeded) {
+				apiErr = errors.NewInvalidAmountError("initial_balance exceeds the maximum account balance")
+			} else {
+				apiErr = errors.NewInternalServerError("failed to create accounts")
+				logging.Error("Failed to create accounts in bulk", err, map[string]interface{}{
+					"count":        req.Count,
+					"owner_prefix": req.OwnerPrefix,
+				})
+			}
+			metrics.RecordBankingOperation("bulk_create_accounts", "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation("bulk_create_accounts", "success")
+		for range ids {
+			metrics.RecordAccountCreation()
+		}
+
+		logging.Info("Accounts created in bulk", map[string]interface{}{
+			"count":           len(ids),
+			"owner_prefix":    req.OwnerPrefix,
+			"initial_balance": req.InitialBalance,
+			"ip":              c.ClientIP(),
+		})
+
+		c.JSON(http.StatusCreated, gin.H{"ids": ids, "count": len(ids)})
+	}
+}
+
+const (
+	defaultAccountListLimit = 20
+	maxAccountListLimit     = 100
+)
+
+// MakeListAccountsHandler pages through all accounts by ID for admin tooling.
+// Limits above maxAccountListLimit are capped rather than rejected.
+func MakeListAccountsHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		var err error
+
+		limit := defaultAccountListLimit
+		if limitStr, ok := c.GetQuery("limit"); ok {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit < 1 {
+				apiErr := errors.NewValidationError("limit must be a positive integer")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+			if limit > maxAccountListLimit {
+				limit = maxAccountListLimit
+			}
+		}
+
+		offset := 0
+		if offsetStr, ok := c.GetQuery("offset"); ok {
+			offset, err = strconv.Atoi(offsetStr)
+			if err != nil || offset < 0 {
+				apiErr := errors.NewValidationError("offset must be a non-negative integer")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		accounts, total, err := db.ListAccounts(c.Request.Context(), limit, offset)
+		if err != nil {
+			apiErr := errors.NewInternalServerError(err.Error())
+			logging.Error("Failed to list accounts", err, map[string]interface{}{
+				"limit":  limit,
+				"offset": offset,
+				"ip":     c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"accounts": accounts,
+			"total":    total,
+			"limit":    limit,
+			"offset":   offset,
+		})
 	}
 }
 
@@ -98,7 +306,7 @@ func MakeGetBalanceHandler(container HandlerDependencies) gin.HandlerFunc {
 			return
 		}
 
-		account, ok := db.GetAccount(id)
+		account, ok := db.GetAccount(c.Request.Context(), id)
 		if !ok {
 			apiErr := errors.NewAccountNotFoundError()
 			logging.Warn("Account not found", map[string]interface{}{
@@ -109,6 +317,37 @@ func MakeGetBalanceHandler(container HandlerDependencies) gin.HandlerFunc {
 			return
 		}
 
+		// Point-in-time balance for reconciliation, rebuilt from the transaction log
+		if asOfStr, ok := c.GetQuery("as_of"); ok {
+			asOf, err := time.Parse(time.RFC3339Nano, asOfStr)
+			if err != nil {
+				apiErr := errors.NewValidationError("as_of must be an RFC 3339 timestamp")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			balance, err := db.GetBalanceAsOf(c.Request.Context(), id, asOf)
+			if err != nil {
+				apiErr := errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to get balance as of timestamp", err, map[string]interface{}{
+					"account_id": id,
+					"as_of":      asOfStr,
+				})
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"id":           account.Id,
+				"owner":        account.Owner,
+				"balance":      balance,
+				"currency":     account.Currency,
+				"account_type": account.AccountType,
+				"as_of":        asOf,
+			})
+			return
+		}
+
 		balance := domain.GetBalance(account)
 
 		// Record balance for distribution metrics
@@ -121,9 +360,328 @@ func MakeGetBalanceHandler(container HandlerDependencies) gin.HandlerFunc {
 		})
 
 		c.JSON(http.StatusOK, gin.H{
-			"id":      account.Id,
-			"owner":   account.Owner,
-			"balance": balance,
+			"id":           account.Id,
+			"owner":        account.Owner,
+			"balance":      balance,
+			"currency":     account.Currency,
+			"account_type": account.AccountType,
 		})
 	}
 }
+
+// MakeCloseAccountHandler closes a zero-balance account, or soft-deletes it with ?soft=true
+func MakeCloseAccountHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+	publisher := container.GetEventPublisher()
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			logging.Warn("Invalid account ID format", map[string]interface{}{
+				"id_param": idStr,
+				"error":    err.Error(),
+				"ip":       c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		soft := false
+		if softStr, ok := c.GetQuery("soft"); ok {
+			soft, err = strconv.ParseBool(softStr)
+			if err != nil {
+				apiErr := errors.NewValidationError("soft must be true or false")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		account, ok := db.GetAccount(c.Request.Context(), id)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if soft {
+			softDeleteAccount(c, db, id)
+			return
+		}
+
+		if err := db.CloseAccount(c.Request.Context(), id); err != nil {
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrAccountNotFound):
+				apiErr = errors.NewAccountNotFoundError()
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			case stderrors.Is(err, postgres.ErrAccountHasBalance):
+				apiErr = errors.NewAccountHasBalanceError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to close account", err, map[string]interface{}{
+					"account_id": id,
+				})
+			}
+			metrics.RecordBankingOperation("close_account", "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation("close_account", "success")
+
+		// Publish account closed event
+		event := messaging.AccountClosedEvent{
+			AccountID: id,
+			Owner:     account.Owner,
+			Timestamp: time.Now(),
+		}
+		if err := publisher.PublishAccountClosed(event); err != nil {
+			logging.Error("Failed to publish account closed event", err, map[string]interface{}{
+				"account_id": id,
+			})
+			// Don't fail the request if event publishing fails (graceful degradation)
+		}
+
+		logging.Info("Account closed successfully", map[string]interface{}{
+			"account_id": id,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusOK, gin.H{"id": id, "status": models.AccountStatusClosed})
+	}
+}
+
+func MakeSetOverdraftLimitHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			logging.Warn("Invalid account ID format", map[string]interface{}{
+				"id_param": idStr,
+				"error":    err.Error(),
+				"ip":       c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		var req struct {
+			Limit *int `json:"limit"`
+		}
+		if err := bindJSON(c, &req); err != nil || req.Limit == nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// The maximum limit depends on the account's currency
+		acc, ok := db.GetAccount(c.Request.Context(), id)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		if err := validation.ValidateOverdraftLimit(*req.Limit, acc.Currency); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := db.SetOverdraftLimit(c.Request.Context(), id, *req.Limit); err != nil {
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrAccountNotFound):
+				apiErr = errors.NewAccountNotFoundError()
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			case stderrors.Is(err, postgres.ErrOverdraftInUse):
+				apiErr = errors.NewOverdraftInUseError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to set overdraft limit", err, map[string]interface{}{
+					"account_id": id,
+				})
+			}
+			metrics.RecordBankingOperation("set_overdraft", "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation("set_overdraft", "success")
+
+		logging.Info("Overdraft limit updated", map[string]interface{}{
+			"account_id": id,
+			"limit":      *req.Limit,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusOK, gin.H{"id": id, "overdraft_limit": *req.Limit})
+	}
+}
+
+// MakeFreezeAccountHandler places a hold on an account: deposits, withdrawals and
+// transfers are rejected until it is unfrozen, but the account stays open
+func MakeFreezeAccountHandler(container HandlerDependencies) gin.HandlerFunc {
+	return makeSetFrozenHandler(container, true)
+}
+
+// MakeUnfreezeAccountHandler lifts a hold placed by MakeFreezeAccountHandler
+func MakeUnfreezeAccountHandler(container HandlerDependencies) gin.HandlerFunc {
+	return makeSetFrozenHandler(container, false)
+}
+
+func makeSetFrozenHandler(container HandlerDependencies, frozen bool) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	operation := "unfreeze"
+	if frozen {
+		operation = "freeze"
+	}
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			logging.Warn("Invalid account ID format", map[string]interface{}{
+				"id_param": idStr,
+				"error":    err.Error(),
+				"ip":       c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := db.SetFrozen(c.Request.Context(), id, frozen); err != nil {
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrAccountNotFound):
+				apiErr = errors.NewAccountNotFoundError()
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to "+operation+" account", err, map[string]interface{}{
+					"account_id": id,
+				})
+			}
+			metrics.RecordBankingOperation(operation, "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation(operation, "success")
+
+		logging.Info("Account frozen flag updated", map[string]interface{}{
+			"account_id": id,
+			"frozen":     frozen,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusOK, gin.H{"id": id, "frozen": frozen})
+	}
+}
+
+// softDeleteAccount handles DELETE /accounts/:id?soft=true: the account disappears from reads
+// and operations but keeps its transactions, and can be brought back with the restore endpoint
+func softDeleteAccount(c *gin.Context, db database.Repository, id int) {
+	if err := db.SoftDeleteAccount(c.Request.Context(), id); err != nil {
+		var apiErr errors.APIError
+		switch {
+		case stderrors.Is(err, postgres.ErrAccountNotFound):
+			apiErr = errors.NewAccountNotFoundError()
+		case stderrors.Is(err, postgres.ErrAccountHasBalance):
+			apiErr = errors.NewAccountHasBalanceError()
+		default:
+			apiErr = errors.NewInternalServerError(err.Error())
+			logging.Error("Failed to soft-delete account", err, map[string]interface{}{
+				"account_id": id,
+			})
+		}
+		metrics.RecordBankingOperation("soft_delete_account", "error")
+		c.JSON(apiErr.Status, apiErr)
+		return
+	}
+
+	metrics.RecordBankingOperation("soft_delete_account", "success")
+
+	logging.Info("Account soft-deleted", map[string]interface{}{
+		"account_id": id,
+		"ip":         c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "deleted": true})
+}
+
+// MakeRestoreAccountHandler brings back a soft-deleted account with its balance and history
+func MakeRestoreAccountHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := db.RestoreAccount(c.Request.Context(), id); err != nil {
+			var apiErr errors.APIError
+			if stderrors.Is(err, postgres.ErrAccountNotFound) {
+				apiErr = errors.NewAccountNotFoundError()
+			} else {
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to restore account", err, map[string]interface{}{
+					"account_id": id,
+				})
+			}
+			metrics.RecordBankingOperation("restore_account", "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation("restore_account", "success")
+
+		logging.Info("Account restored", map[string]interface{}{
+			"account_id": id,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusOK, gin.H{"id": id, "deleted": false})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"bank-api/internal/pkg/money"
+	"bank-api/internal/pkg/validation"
+	"encoding/json"
+	stderrors "errors"
+)
+
+// parseAmount reads a request amount given either as integer minor units of currency (1050) or
+// as a decimal string in its major unit ("10.50" BRL, "10.500" KWD, "1050" JPY). A missing
+// amount reads as 0, which ValidateAmount rejects.
+func parseAmount(raw json.RawMessage, currency string) (int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	if raw[0] == '"' {
+		var decimal string
+		if err := json.Unmarshal(raw, &decimal); err != nil {
+			return 0, stderrors.New("amount must be integer minor units or a decimal string")
+		}
+		return validation.ParseMoney(decimal, money.MinorUnits(currency))
+	}
+
+	var minorUnits int
+	if err := json.Unmarshal(raw, &minorUnits); err != nil {
+		return 0, stderrors.New("amount must be integer minor units or a decimal string such as \"10.50\"")
+	}
+	return minorUnits, nil
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindJSON decodes the request body into obj like ShouldBindJSON, but rejects fields obj
+// doesn't declare, so misspelled or unsupported fields fail loudly instead of being ignored
+func bindJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil {
+		return stderrors.New("missing request body")
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		if stderrors.Is(err, io.EOF) {
+			return stderrors.New("missing request body")
+		}
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
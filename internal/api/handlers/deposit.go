@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"bank-api/internal/domain/models"
 	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/errors"
 	"bank-api/internal/pkg/idempotency"
 	"bank-api/internal/pkg/logging"
 	"bank-api/internal/pkg/telemetry"
+	"bank-api/internal/pkg/tracing"
+	"bank-api/internal/pkg/validation"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,60 +34,133 @@ func MakeDepositHandler(container HandlerDependencies) gin.HandlerFunc {
 		idStr := c.Param("id")
 		id, err := strconv.Atoi(idStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identifier (id)"})
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
 		var req struct {
-			Amount int `json:"amount"`
+			Amount      json.RawMessage `json:"amount"`
+			Nonce       string          `json:"nonce"`
+			CallbackURL string          `json:"callback_url"` // Optional; receives a POST when the deposit completes
 		}
-		if err := c.ShouldBindJSON(&req); err != nil || req.Amount <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value"})
+		if err := bindJSON(c, &req); err != nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
+		if req.CallbackURL != "" {
+			if err := validation.ValidateCallbackURL(req.CallbackURL); err != nil {
+				apiErr := errors.NewValidationError(err.Error())
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
 
 		// Fail fast - validate account exists before publishing event
-		_, ok := db.GetAccount(id)
+		acc, ok := db.GetAccount(c.Request.Context(), id)
 		if !ok {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		if acc.Status == models.AccountStatusClosed {
+			apiErr := errors.NewAccountClosedError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		if acc.Frozen {
+			apiErr := errors.NewAccountFrozenError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// Decimal amounts and limits depend on the account's currency
+		amount, err := parseAmount(req.Amount, acc.Currency)
+		if err == nil {
+			err = validation.ValidateAmount(amount, acc.Currency)
+		}
+		if err != nil {
+			apiErr := errors.NewInvalidAmountError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
 		// Generate unique operation ID for tracking (legacy)
 		operationID := uuid.New().String()
 
-		// Generate deterministic idempotency key (no DB query!)
+		// Generate idempotency key (no DB query!)
 		// Same request → same key → consumer deduplicates
-		idempotencyKey := idempotency.GenerateKey("deposit", id, req.Amount)
+		var idempotencyKey string
+		if clientKeys := c.Request.Header.Values("Idempotency-Key"); len(clientKeys) > 0 {
+			// Client controls retries explicitly via the Idempotency-Key header
+			clientKey := strings.TrimSpace(clientKeys[0])
+			if clientKey == "" || len(clientKey) > idempotency.MaxClientKeyLength {
+				apiErr := errors.NewInvalidIdempotencyKeyError()
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+			idempotencyKey = idempotency.GenerateClientKey("deposit", id, clientKey)
+		} else {
+			// Deterministic fallback; a nonce lets clients make distinct deposits of the same amount
+			idempotencyKey = idempotency.GenerateKeyWithNonce("deposit", id, amount, req.Nonce)
+		}
+
+		// Record the operation as pending before publishing so GET /operations/:id works immediately
+		// and the consumer always finds a row to complete
+		if err := db.CreatePendingOperation(c.Request.Context(), operationID, "deposit", id, amount); err != nil {
+			logging.Error("Failed to record deposit operation", err, map[string]interface{}{
+				"operation_id": operationID,
+				"account_id":   id,
+			})
+			metrics.RecordBankingOperation("deposit", "error")
+			apiErr := errors.NewInternalServerError("Failed to record deposit operation")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// Carry the request's trace ID so the consumer's logs and events can be correlated with it
+		traceID := tracing.FromContext(c.Request.Context())
 
 		// Publish deposit request event to Kafka (fire-and-forget)
 		event := messaging.DepositRequestedEvent{
 			OperationID:    operationID,
 			IdempotencyKey: idempotencyKey,
 			AccountID:      id,
-			Amount:         req.Amount,
+			Amount:         amount,
+			TraceID:        traceID,
+			CallbackURL:    req.CallbackURL, // The consumer POSTs the completion here
 			Timestamp:      time.Now(),
 		}
 
 		if err := publisher.PublishDepositRequested(event); err != nil {
 			logging.Error("Failed to publish deposit request event", err, map[string]interface{}{
 				"operation_id": operationID,
+				"trace_id":     traceID,
 				"account_id":   id,
-				"amount":       req.Amount,
+				"amount":       amount,
 			})
+			if err := db.SetOperationStatus(c.Request.Context(), operationID, models.OperationStatusFailed, messaging.FailureReasonPublishFailed); err != nil {
+				logging.Error("Failed to record deposit operation status", err, map[string]interface{}{
+					"operation_id": operationID,
+				})
+			}
 			metrics.RecordBankingOperation("deposit", "error")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process deposit request"})
+			apiErr := errors.NewPublishFailedError("deposit")
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
 		// Record successful request acceptance
 		metrics.RecordBankingOperation("deposit", "accepted")
 
-		// Return 202 Accepted with operation ID for tracking
+		// Return 202 Accepted with operation ID for tracking; the idempotency key lets clients
+		// see that a retry was recognised as the same deposit
 		c.JSON(http.StatusAccepted, gin.H{
-			"operation_id": operationID,
-			"status":       "accepted",
-			"message":      "Deposit request accepted and will be processed asynchronously",
+			"operation_id":    operationID,
+			"idempotency_key": idempotencyKey,
+			"status":          "accepted",
+			"message":         "Deposit request accepted and will be processed asynchronously",
 		})
 	}
 }
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout bounds each dependency check so a hung database can't hang the probe
+const healthCheckTimeout = 2 * time.Second
+
+var (
+	errNotConfigured     = stderrors.New("not configured")
+	errProducerUnhealthy = stderrors.New("producer unhealthy")
+)
+
+// componentHealth is the status of a single dependency in the /healthz response
+type componentHealth struct {
+	Status    string  `json:"status"` // "up" or "down"
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// MakeHealthHandler reports whether the service can reach its dependencies.
+// Unlike /readyz it pings PostgreSQL and checks the Kafka producer, returning
+// 503 if either is unavailable.
+func MakeHealthHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+	publisher := container.GetEventPublisher()
+
+	return func(c *gin.Context) {
+		database := checkComponent(func() error {
+			if db == nil {
+				return errNotConfigured
+			}
+			ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+			defer cancel()
+			return db.Ping(ctx)
+		})
+
+		kafka := checkComponent(func() error {
+			if publisher == nil {
+				return errNotConfigured
+			}
+			if !publisher.IsHealthy() {
+				return errProducerUnhealthy
+			}
+			return nil
+		})
+
+		status := http.StatusOK
+		state := "healthy"
+		if database.Status != "up" || kafka.Status != "up" {
+			status = http.StatusServiceUnavailable
+			state = "unhealthy"
+		}
+
+		c.JSON(status, gin.H{
+			"status": state,
+			"components": gin.H{
+				"database": database,
+				"kafka":    kafka,
+			},
+		})
+	}
+}
+
+// checkComponent runs check and records its outcome and latency
+func checkComponent(check func() error) componentHealth {
+	start := time.Now()
+	err := check()
+
+	health := componentHealth{
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		health.Status = "down"
+		health.Error = err.Error()
+	}
+	return health
+}
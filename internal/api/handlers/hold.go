@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/internal/pkg/validation"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MakePlaceHoldHandler reserves funds on an account (e.g. a card authorization).
+// The hold reduces the available balance for withdrawals and transfers without debiting it.
+func MakePlaceHoldHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		var req struct {
+			Amount json.RawMessage `json:"amount"`
+		}
+		if err := bindJSON(c, &req); err != nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		// Decimal amounts and limits depend on the account's currency
+		acc, ok := db.GetAccount(c.Request.Context(), id)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		amount, err := parseAmount(req.Amount, acc.Currency)
+		if err == nil {
+			err = validation.ValidateAmount(amount, acc.Currency)
+		}
+		if err != nil {
+			apiErr := errors.NewInvalidAmountError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		holdID, err := db.PlaceHold(c.Request.Context(), id, amount)
+		if err != nil {
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrAccountNotFound):
+				apiErr = errors.NewAccountNotFoundError()
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			case stderrors.Is(err, postgres.ErrAccountFrozen):
+				apiErr = errors.NewAccountFrozenError()
+			case stderrors.Is(err, postgres.ErrInsufficientFunds):
+				apiErr = errors.NewInsufficientFundsError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to place hold", err, map[string]interface{}{
+					"account_id": id,
+				})
+			}
+			metrics.RecordBankingOperation("place_hold", "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation("place_hold", "success")
+
+		logging.Info("Hold placed", map[string]interface{}{
+			"hold_id":    holdID,
+			"account_id": id,
+			"amount":     amount,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusCreated, gin.H{
+			"hold_id":    holdID,
+			"account_id": id,
+			"amount":     amount,
+			"status":     models.HoldStatusActive,
+		})
+	}
+}
+
+// MakeCaptureHoldHandler debits the funds reserved by an active hold
+func MakeCaptureHoldHandler(container HandlerDependencies) gin.HandlerFunc {
+	return makeResolveHoldHandler(container, true)
+}
+
+// MakeReleaseHoldHandler frees the funds reserved by an active hold without debiting them
+func MakeReleaseHoldHandler(container HandlerDependencies) gin.HandlerFunc {
+	return makeResolveHoldHandler(container, false)
+}
+
+func makeResolveHoldHandler(container HandlerDependencies, capture bool) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	operation := "release_hold"
+	if capture {
+		operation = "capture_hold"
+	}
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		holdID, err := strconv.Atoi(idStr)
+		if err != nil || holdID <= 0 {
+			apiErr := errors.NewValidationError("Invalid hold ID format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		var hold *models.Hold
+		if capture {
+			hold, err = db.CaptureHold(c.Request.Context(), holdID)
+		} else {
+			hold, err = db.ReleaseHold(c.Request.Context(), holdID)
+		}
+		if err != nil {
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrHoldNotFound):
+				apiErr = errors.NewHoldNotFoundError()
+			case stderrors.Is(err, postgres.ErrHoldNotActive):
+				apiErr = errors.NewHoldNotActiveError()
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			case stderrors.Is(err, postgres.ErrAccountFrozen):
+				apiErr = errors.NewAccountFrozenError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to resolve hold", err, map[string]interface{}{
+					"hold_id":   holdID,
+					"operation": operation,
+				})
+			}
+			metrics.RecordBankingOperation(operation, "error")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.RecordBankingOperation(operation, "success")
+
+		logging.Info("Hold resolved", map[string]interface{}{
+			"hold_id":    hold.Id,
+			"account_id": hold.AccountID,
+			"status":     hold.Status,
+			"ip":         c.ClientIP(),
+		})
+
+		c.JSON(http.StatusOK, hold)
+	}
+}
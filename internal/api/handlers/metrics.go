@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/logging"
 	"bank-api/internal/pkg/telemetry"
 	"net/http"
 
@@ -11,3 +13,25 @@ import (
 func GetMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metrics.List())
 }
+
+// MakeBusinessMetricsHandler returns live account aggregates as JSON and refreshes the
+// matching Prometheus gauges
+func MakeBusinessMetricsHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		aggregates, err := db.GetAggregates(c.Request.Context())
+		if err != nil {
+			apiErr := errors.NewInternalServerError("Failed to compute business metrics")
+			logging.Error("Failed to compute business metrics", err, nil)
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		metrics.UpdateActiveAccounts(float64(aggregates.ActiveAccounts))
+		metrics.UpdateTotalBalances(aggregates.TotalBalance)
+
+		c.JSON(http.StatusOK, aggregates)
+	}
+}
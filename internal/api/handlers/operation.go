@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/logging"
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOperationIDLength matches the operation_status.operation_id column
+const maxOperationIDLength = 64
+
+// MakeGetOperationHandler reports the status of an asynchronous operation by the operation_id
+// returned with its 202 response: pending until the consumer has processed it, then completed or failed
+func MakeGetOperationHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		operationID := strings.TrimSpace(c.Param("id"))
+		if operationID == "" || len(operationID) > maxOperationIDLength {
+			apiErr := errors.NewValidationError("Invalid operation ID format")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		operation, err := db.GetOperation(c.Request.Context(), operationID)
+		if err != nil {
+			var apiErr errors.APIError
+			if stderrors.Is(err, postgres.ErrOperationNotFound) {
+				apiErr = errors.NewOperationNotFoundError()
+			} else {
+				apiErr = errors.NewInternalServerError(err.Error())
+				logging.Error("Failed to get operation", err, map[string]interface{}{
+					"operation_id": operationID,
+				})
+			}
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, operation)
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"bank-api/internal/pkg/telemetry"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shutdownAware is implemented by containers that track graceful shutdown
+type shutdownAware interface {
+	IsShuttingDown() bool
+}
+
+// MakeReadinessHandler reports whether the instance should receive traffic.
+// Returns 503 once graceful shutdown has started so Kubernetes stops routing
+// requests while in-flight ones drain.
+func MakeReadinessHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	publisher := container.GetEventPublisher()
+	lifecycle, _ := container.(shutdownAware)
+
+	return func(c *gin.Context) {
+		shuttingDown := lifecycle != nil && lifecycle.IsShuttingDown()
+
+		kafkaHealthy := false
+		if publisher != nil {
+			kafkaHealthy = publisher.IsHealthy()
+		}
+
+		status := http.StatusOK
+		state := "ready"
+		if shuttingDown {
+			status = http.StatusServiceUnavailable
+			state = "shutting_down"
+		}
+
+		c.JSON(status, gin.H{
+			"status":             state,
+			"in_flight_requests": int(metrics.GetInFlightRequests()),
+			"kafka_healthy":      kafkaHealthy,
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTransactionHistoryLimit = 50
+	maxTransactionHistoryLimit     = 500
+)
+
+// transactionTypes are the values accepted by the type filter
+var transactionTypes = map[string]bool{
+	"deposit":      true,
+	"withdraw":     true,
+	"transfer_in":  true,
+	"transfer_out": true,
+	"interest":     true,
+}
+
+func MakeTransactionHistoryHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			logging.Warn("Invalid account ID format", map[string]interface{}{
+				"id_param": idStr,
+				"error":    err.Error(),
+				"ip":       c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(id); err != nil {
+			apiErr := errors.NewValidationError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		limit := defaultTransactionHistoryLimit
+		if limitStr, ok := c.GetQuery("limit"); ok {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit < 1 || limit > maxTransactionHistoryLimit {
+				apiErr := errors.NewValidationError("limit must be an integer between 1 and " + strconv.Itoa(maxTransactionHistoryLimit))
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		filter := postgres.HistoryFilter{Limit: limit}
+
+		// Cursor from a previous page's next_cursor
+		if beforeStr, ok := c.GetQuery("before"); ok {
+			filter.Before, err = time.Parse(time.RFC3339Nano, beforeStr)
+			if err != nil {
+				apiErr := errors.NewValidationError("before must be an RFC 3339 timestamp")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		if beforeIDStr, ok := c.GetQuery("before_id"); ok {
+			filter.BeforeID, err = strconv.Atoi(beforeIDStr)
+			if err != nil || filter.BeforeID < 1 || filter.Before.IsZero() {
+				apiErr := errors.NewValidationError("before_id must be a positive integer and requires before")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		if txType, ok := c.GetQuery("type"); ok {
+			if !transactionTypes[txType] {
+				apiErr := errors.NewValidationError("type must be one of deposit, withdraw, transfer_in, transfer_out, interest")
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+			filter.Type = txType
+		}
+
+		if _, ok := db.GetAccount(c.Request.Context(), id); !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			logging.Warn("Account not found", map[string]interface{}{
+				"account_id": id,
+				"ip":         c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		page, err := db.GetTransactionHistoryFiltered(c.Request.Context(), id, filter)
+		if err != nil {
+			apiErr := errors.NewInternalServerError(err.Error())
+			logging.Error("Failed to retrieve transaction history", err, map[string]interface{}{
+				"account_id": id,
+				"limit":      limit,
+				"type":       filter.Type,
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"account_id":   id,
+			"transactions": page.Transactions,
+			"next_cursor":  page.Next, // null on the last page
+		})
+	}
+}
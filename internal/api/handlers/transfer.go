@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
 	"bank-api/internal/infrastructure/messaging"
 	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/idempotency"
 	"bank-api/internal/pkg/logging"
 	"bank-api/internal/pkg/telemetry"
 	"bank-api/internal/pkg/validation"
+	"encoding/json"
+	stderrors "errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,12 +26,12 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		var req struct {
-			FromID int `json:"from"`
-			ToID   int `json:"to"`
-			Amount int `json:"amount"`
+			FromID int             `json:"from"`
+			ToID   int             `json:"to"`
+			Amount json.RawMessage `json:"amount"`
 		}
 
-		if err := c.ShouldBindJSON(&req); err != nil {
+		if err := bindJSON(c, &req); err != nil {
 			apiErr := errors.NewValidationError("Invalid request format")
 			logging.Warn("Invalid JSON in transfer request", map[string]interface{}{
 				"error": err.Error(),
@@ -35,12 +41,6 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 			return
 		}
 
-		if err := validation.ValidateAmount(req.Amount); err != nil {
-			apiErr := errors.NewInvalidAmountError(err.Error())
-			c.JSON(apiErr.Status, apiErr)
-			return
-		}
-
 		if err := validation.ValidateAccountID(req.FromID); err != nil {
 			apiErr := errors.NewValidationError("Invalid from account ID: " + err.Error())
 			c.JSON(apiErr.Status, apiErr)
@@ -57,27 +57,141 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 			apiErr := errors.NewSelfTransferError()
 			logging.Warn("Attempted self-transfer", map[string]interface{}{
 				"account_id": req.FromID,
-				"amount":     req.Amount,
+				"amount":     string(req.Amount),
 				"ip":         c.ClientIP(),
 			})
 			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
-		// Use atomic transfer operation to prevent race conditions
-		from, to, err := db.AtomicTransfer(req.FromID, req.ToID, req.Amount)
+		// Decimal amounts and limits depend on the source account's currency; a target in
+		// another currency is rejected by the transfer itself
+		source, ok := db.GetAccount(c.Request.Context(), req.FromID)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		amount, err := parseAmount(req.Amount, source.Currency)
+		if err == nil {
+			err = validation.ValidateAmount(amount, source.Currency)
+		}
+		if err != nil {
+			apiErr := errors.NewInvalidAmountError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		clientKeys := c.Request.Header.Values("Idempotency-Key")
+
+		// Fail fast, before taking any row lock, when a plain read already shows the source
+		// can't cover the amount. Replays with an Idempotency-Key skip this, since they must be
+		// answered even after the balance has moved on; AtomicTransfer makes the authoritative check.
+		if len(clientKeys) == 0 {
+			if clearlyInsufficient(source, amount) {
+				metrics.RecordBankingOperation("transfer", "error")
+				apiErr := errors.NewInsufficientFundsError()
+				logging.Warn("Transfer rejected before locking: insufficient funds", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+					"ip":              c.ClientIP(),
+				})
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+		}
+
+		// With an Idempotency-Key header, a retried transfer is applied at most once
+		var from, to *models.Account
+		start := time.Now()
+		if len(clientKeys) > 0 {
+			clientKey := strings.TrimSpace(clientKeys[0])
+			if clientKey == "" || len(clientKey) > idempotency.MaxClientKeyLength {
+				apiErr := errors.NewInvalidIdempotencyKeyError()
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			idempotencyKey := idempotency.GenerateClientTransferKey(req.FromID, req.ToID, amount, clientKey)
+			from, to, err = db.AtomicTransferWithIdempotency(c.Request.Context(), req.FromID, req.ToID, amount, idempotencyKey)
+			if stderrors.Is(err, postgres.ErrDuplicateOperation) {
+				// Already applied - answer the retry without moving money or re-publishing the event
+				metrics.RecordBankingOperation("transfer", "duplicate")
+				logging.Info("Duplicate transfer request skipped", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+				})
+
+				response := gin.H{
+					"message":     "Transferência já processada",
+					"from_id":     req.FromID,
+					"to_id":       req.ToID,
+					"transferred": amount,
+					"duplicate":   true,
+				}
+				if from != nil {
+					response["from_balance"] = from.Balance
+				}
+				c.JSON(http.StatusOK, response)
+				return
+			}
+		} else {
+			// Use atomic transfer operation to prevent race conditions
+			from, to, err = db.AtomicTransfer(c.Request.Context(), req.FromID, req.ToID, amount)
+		}
+
+		// Includes time spent waiting on row locks, so contention shows up per amount bucket
+		metrics.RecordTransferDuration(amount, time.Since(start))
 
 		if err != nil {
 			// Record failed operation
 			metrics.RecordBankingOperation("transfer", "error")
 
 			// Check error type
-			if strings.Contains(err.Error(), "insufficient balance") {
+			if stderrors.Is(err, postgres.ErrAccountClosed) {
+				apiErr := errors.NewAccountClosedError()
+				logging.Warn("Transfer failed: account closed", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+					"ip":              c.ClientIP(),
+				})
+				c.JSON(apiErr.Status, apiErr)
+			} else if stderrors.Is(err, postgres.ErrAccountFrozen) {
+				apiErr := errors.NewAccountFrozenError()
+				logging.Warn("Transfer failed: account frozen", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+					"ip":              c.ClientIP(),
+				})
+				c.JSON(apiErr.Status, apiErr)
+			} else if stderrors.Is(err, postgres.ErrCurrencyMismatch) {
+				apiErr := errors.NewCurrencyMismatchError()
+				logging.Warn("Transfer failed: currency mismatch", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+					"ip":              c.ClientIP(),
+				})
+				c.JSON(apiErr.Status, apiErr)
+			} else if stderrors.Is(err, postgres.ErrBalanceLimitExceeded) {
+				apiErr := errors.NewBalanceLimitExceededError()
+				logging.Warn("Transfer failed: balance limit exceeded", map[string]interface{}{
+					"from_account_id": req.FromID,
+					"to_account_id":   req.ToID,
+					"amount":          amount,
+					"ip":              c.ClientIP(),
+				})
+				c.JSON(apiErr.Status, apiErr)
+			} else if strings.Contains(err.Error(), "insufficient balance") {
 				apiErr := errors.NewInsufficientFundsError()
 				logging.Warn("Transfer failed: insufficient funds", map[string]interface{}{
 					"from_account_id": req.FromID,
 					"to_account_id":   req.ToID,
-					"amount":          req.Amount,
+					"amount":          amount,
 					"ip":              c.ClientIP(),
 				})
 				c.JSON(apiErr.Status, apiErr)
@@ -86,7 +200,7 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 				logging.Warn("Transfer failed: account not found", map[string]interface{}{
 					"from_account_id": req.FromID,
 					"to_account_id":   req.ToID,
-					"amount":          req.Amount,
+					"amount":          amount,
 					"error":           err.Error(),
 					"ip":              c.ClientIP(),
 				})
@@ -97,7 +211,7 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 
 		// Record successful operation and metrics
 		metrics.RecordBankingOperation("transfer", "success")
-		metrics.RecordTransferAmount(float64(req.Amount))
+		metrics.RecordTransferAmount(float64(amount))
 		metrics.RecordAccountBalance(float64(from.Balance))
 		metrics.RecordAccountBalance(float64(to.Balance))
 
@@ -105,7 +219,7 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 		event := messaging.TransferCompletedEvent{
 			FromAccountID:    from.Id,
 			ToAccountID:      to.Id,
-			Amount:           req.Amount,
+			Amount:           amount,
 			FromBalanceAfter: from.Balance,
 			ToBalanceAfter:   to.Balance,
 			Timestamp:        time.Now(),
@@ -114,7 +228,7 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 			logging.Error("Failed to publish transfer completed event", err, map[string]interface{}{
 				"from_account_id": from.Id,
 				"to_account_id":   to.Id,
-				"amount":          req.Amount,
+				"amount":          amount,
 			})
 		}
 
@@ -124,7 +238,184 @@ func MakeTransferHandler(container HandlerDependencies) gin.HandlerFunc {
 			"to_balance":   to.Balance,
 			"from_id":      from.Id,
 			"to_id":        to.Id,
-			"transferred":  req.Amount,
+			"transferred":  amount,
+		})
+	}
+}
+
+// clearlyInsufficient reports whether account can't cover amount even before holds are taken
+// into account. Holds only lower the available balance, so this never rejects a transfer the
+// locked check would allow. Closed and frozen accounts are left to the locked check so they
+// keep their own error codes.
+func clearlyInsufficient(account *models.Account, amount int) bool {
+	if account.Status == models.AccountStatusClosed || account.Frozen {
+		return false
+	}
+	return account.Balance-amount < -account.OverdraftLimit
+}
+
+// maxBatchTransferLegs caps how many targets a single batch transfer may include
+const maxBatchTransferLegs = 100
+
+func MakeBatchTransferHandler(container HandlerDependencies) gin.HandlerFunc {
+	// Extract dependencies once at handler creation time
+	db := container.GetDatabase()
+	publisher := container.GetEventPublisher()
+
+	return func(c *gin.Context) {
+		var req struct {
+			FromID    int `json:"from"`
+			Transfers []struct {
+				ToID   int             `json:"to"`
+				Amount json.RawMessage `json:"amount"`
+			} `json:"transfers"`
+		}
+
+		if err := bindJSON(c, &req); err != nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			logging.Warn("Invalid JSON in batch transfer request", map[string]interface{}{
+				"error": err.Error(),
+				"ip":    c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if err := validation.ValidateAccountID(req.FromID); err != nil {
+			apiErr := errors.NewValidationError("Invalid from account ID: " + err.Error())
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		if len(req.Transfers) == 0 || len(req.Transfers) > maxBatchTransferLegs {
+			apiErr := errors.NewValidationError("transfers must contain between 1 and " + strconv.Itoa(maxBatchTransferLegs) + " entries")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// Limits depend on the source account's currency
+		source, ok := db.GetAccount(c.Request.Context(), req.FromID)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// Each leg's amount is parsed in the source currency, as for a single transfer
+		transfers := make([]postgres.Transfer, 0, len(req.Transfers))
+		seen := make(map[int]bool, len(req.Transfers))
+		for _, leg := range req.Transfers {
+			amount, err := parseAmount(leg.Amount, source.Currency)
+			if err == nil {
+				err = validation.ValidateAmount(amount, source.Currency)
+			}
+			if err != nil {
+				apiErr := errors.NewInvalidAmountError(err.Error())
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			if err := validation.ValidateAccountID(leg.ToID); err != nil {
+				apiErr := errors.NewValidationError("Invalid to account ID: " + err.Error())
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			if leg.ToID == req.FromID {
+				apiErr := errors.NewSelfTransferError()
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+
+			if seen[leg.ToID] {
+				apiErr := errors.NewValidationError("Duplicate target account ID: " + strconv.Itoa(leg.ToID))
+				c.JSON(apiErr.Status, apiErr)
+				return
+			}
+			seen[leg.ToID] = true
+			transfers = append(transfers, postgres.Transfer{ToID: leg.ToID, Amount: amount})
+		}
+
+		// All legs are applied in one database transaction, or none are
+		accounts, err := db.AtomicBatchTransfer(c.Request.Context(), req.FromID, transfers)
+		if err != nil {
+			metrics.RecordBankingOperation("batch_transfer", "error")
+
+			var apiErr errors.APIError
+			switch {
+			case stderrors.Is(err, postgres.ErrAccountClosed):
+				apiErr = errors.NewAccountClosedError()
+			case stderrors.Is(err, postgres.ErrAccountFrozen):
+				apiErr = errors.NewAccountFrozenError()
+			case stderrors.Is(err, postgres.ErrCurrencyMismatch):
+				apiErr = errors.NewCurrencyMismatchError()
+			case stderrors.Is(err, postgres.ErrInsufficientFunds):
+				apiErr = errors.NewInsufficientFundsError()
+			case stderrors.Is(err, postgres.ErrBalanceLimitExceeded):
+				apiErr = errors.NewBalanceLimitExceededError()
+			case stderrors.Is(err, postgres.ErrAccountNotFound):
+				apiErr = errors.NewAccountNotFoundError()
+			default:
+				apiErr = errors.NewInternalServerError(err.Error())
+			}
+
+			logging.Warn("Batch transfer failed", map[string]interface{}{
+				"from_account_id": req.FromID,
+				"legs":            len(transfers),
+				"error":           err.Error(),
+				"ip":              c.ClientIP(),
+			})
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		from := accounts[0]
+		total := 0
+		for _, leg := range transfers {
+			total += leg.Amount
+		}
+
+		metrics.RecordBankingOperation("batch_transfer", "success")
+		metrics.RecordAccountBalance(float64(from.Balance))
+
+		// Publish one event per leg, with the source balance as it stood after that leg
+		fromBalance := from.Balance + total
+		legs := make([]gin.H, 0, len(transfers))
+		for i, leg := range transfers {
+			to := accounts[i+1]
+			fromBalance -= leg.Amount
+
+			metrics.RecordTransferAmount(float64(leg.Amount))
+
+			event := messaging.TransferCompletedEvent{
+				FromAccountID:    from.Id,
+				ToAccountID:      to.Id,
+				Amount:           leg.Amount,
+				FromBalanceAfter: fromBalance,
+				ToBalanceAfter:   to.Balance,
+				Timestamp:        time.Now(),
+			}
+			if err := publisher.PublishTransferCompleted(event); err != nil {
+				logging.Error("Failed to publish transfer completed event", err, map[string]interface{}{
+					"from_account_id": from.Id,
+					"to_account_id":   to.Id,
+					"amount":          leg.Amount,
+				})
+			}
+
+			legs = append(legs, gin.H{
+				"to_id":       to.Id,
+				"to_balance":  to.Balance,
+				"transferred": leg.Amount,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":           "Transferências realizadas com sucesso",
+			"from_id":           from.Id,
+			"from_balance":      from.Balance,
+			"total_transferred": total,
+			"transfers":         legs,
 		})
 	}
 }
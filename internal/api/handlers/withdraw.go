@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"bank-api/internal/domain/models"
 	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/idempotency"
 	"bank-api/internal/pkg/logging"
 	"bank-api/internal/pkg/telemetry"
+	"bank-api/internal/pkg/tracing"
+	"bank-api/internal/pkg/validation"
+	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func MakeWithdrawHandler(container HandlerDependencies) gin.HandlerFunc {
@@ -17,62 +23,118 @@ func MakeWithdrawHandler(container HandlerDependencies) gin.HandlerFunc {
 	db := container.GetDatabase()
 	publisher := container.GetEventPublisher()
 
+	// Event-driven fire-and-forget pattern (same as deposits):
+	// 1. Validate account exists (fail fast)
+	// 2. Publish WithdrawalRequestedEvent to Kafka
+	// 3. Return 202 Accepted with operation_id for tracking
+	// 4. Consumer checks funds, updates DB, publishes WithdrawalCompletedEvent
+	//    or TransactionFailedEvent on insufficient funds
+
 	return func(c *gin.Context) {
 		idStr := c.Param("id")
 		id, err := strconv.Atoi(idStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			apiErr := errors.NewValidationError("Invalid account ID format")
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
 		var req struct {
-			Amount int `json:"amount"`
+			Amount json.RawMessage `json:"amount"`
 		}
-		if err := c.ShouldBindJSON(&req); err != nil || req.Amount <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Valor inválido"})
+		if err := bindJSON(c, &req); err != nil {
+			apiErr := errors.NewValidationError("Invalid request format")
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
-		// Use atomic withdraw operation to prevent race conditions
-		account, err := db.AtomicWithdraw(id, req.Amount)
+		// Fail fast - validate account exists before publishing event
+		acc, ok := db.GetAccount(c.Request.Context(), id)
+		if !ok {
+			apiErr := errors.NewAccountNotFoundError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		if acc.Status == models.AccountStatusClosed {
+			apiErr := errors.NewAccountClosedError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+		if acc.Frozen {
+			apiErr := errors.NewAccountFrozenError()
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
 
+		// Decimal amounts and limits depend on the account's currency
+		amount, err := parseAmount(req.Amount, acc.Currency)
+		if err == nil {
+			err = validation.ValidateAmount(amount, acc.Currency)
+		}
 		if err != nil {
-			// Record failed operation
-			metrics.RecordBankingOperation("withdraw", "error")
-
-			// Check if account not found or insufficient balance
-			if strings.Contains(err.Error(), "account not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Conta não encontrada"})
-			} else {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente"})
-			}
+			apiErr := errors.NewInvalidAmountError(err.Error())
+			c.JSON(apiErr.Status, apiErr)
 			return
 		}
 
-		balance := account.Balance
+		// Generate unique operation ID for tracking
+		operationID := uuid.New().String()
 
-		// Record successful operation and metrics
-		metrics.RecordBankingOperation("withdraw", "success")
-		metrics.RecordAccountBalance(float64(balance))
+		// Generate deterministic idempotency key (same scheme as deposits)
+		idempotencyKey := idempotency.GenerateKey("withdraw", id, amount)
 
-		// Publish withdrawal completed event to Kafka
-		event := messaging.WithdrawalCompletedEvent{
-			AccountID:    account.Id,
-			Amount:       req.Amount,
-			BalanceAfter: balance,
-			Timestamp:    time.Now(),
+		// Record the operation as pending before publishing so GET /operations/:id works immediately
+		// and the consumer always finds a row to complete
+		if err := db.CreatePendingOperation(c.Request.Context(), operationID, "withdraw", id, amount); err != nil {
+			logging.Error("Failed to record withdrawal operation", err, map[string]interface{}{
+				"operation_id": operationID,
+				"account_id":   id,
+			})
+			metrics.RecordBankingOperation("withdraw", "error")
+			apiErr := errors.NewInternalServerError("Failed to record withdrawal operation")
+			c.JSON(apiErr.Status, apiErr)
+			return
+		}
+
+		// Carry the request's trace ID so the consumer's logs and events can be correlated with it
+		traceID := tracing.FromContext(c.Request.Context())
+
+		// Publish withdrawal request event to Kafka (fire-and-forget)
+		event := messaging.WithdrawalRequestedEvent{
+			OperationID:    operationID,
+			IdempotencyKey: idempotencyKey,
+			AccountID:      id,
+			Amount:         amount,
+			TraceID:        traceID,
+			Timestamp:      time.Now(),
 		}
-		if err := publisher.PublishWithdrawalCompleted(event); err != nil {
-			logging.Error("Failed to publish withdrawal completed event", err, map[string]interface{}{
-				"account_id": account.Id,
-				"amount":     req.Amount,
+
+		if err := publisher.PublishWithdrawalRequested(event); err != nil {
+			logging.Error("Failed to publish withdrawal request event", err, map[string]interface{}{
+				"operation_id": operationID,
+				"trace_id":     traceID,
+				"account_id":   id,
+				"amount":       amount,
 			})
+			if err := db.SetOperationStatus(c.Request.Context(), operationID, models.OperationStatusFailed, messaging.FailureReasonPublishFailed); err != nil {
+				logging.Error("Failed to record withdrawal operation status", err, map[string]interface{}{
+					"operation_id": operationID,
+				})
+			}
+			metrics.RecordBankingOperation("withdraw", "error")
+			apiErr := errors.NewPublishFailedError("withdrawal")
+			c.JSON(apiErr.Status, apiErr)
+			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Saque realizado com sucesso",
-			"id":      account.Id,
-			"balance": balance,
+		// Record successful request acceptance
+		metrics.RecordBankingOperation("withdraw", "accepted")
+
+		// Return 202 Accepted with operation ID for tracking
+		c.JSON(http.StatusAccepted, gin.H{
+			"operation_id": operationID,
+			"status":       "accepted",
+			"message":      "Withdrawal request accepted and will be processed asynchronously",
 		})
 	}
 }
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog writes one structured log entry per request and observes its duration in the
+// HTTPDuration histogram. The endpoint label is the route pattern (e.g. /accounts/:id/deposit),
+// not the raw path, so account IDs don't blow up the label cardinality.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Process request
+		c.Next()
+
+		duration := time.Since(start)
+
+		method := c.Request.Method
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unknown"
+		}
+		status := c.Writer.Status()
+
+		metrics.HTTPDuration.WithLabelValues(method, endpoint, strconv.Itoa(status)).Observe(duration.Seconds())
+
+		bytesWritten := c.Writer.Size()
+		if bytesWritten < 0 {
+			bytesWritten = 0
+		}
+
+		fields := map[string]interface{}{
+			"method":         method,
+			"path":           c.Request.URL.Path,
+			"endpoint":       endpoint,
+			"status":         status,
+			"duration_ms":    float64(duration.Microseconds()) / 1000,
+			"latency_bucket": latencyBucket(duration),
+			"bytes":          bytesWritten,
+			"ip":             c.ClientIP(),
+		}
+		if reqCtx, ok := GetRequestContext(c); ok {
+			fields["request_id"] = reqCtx.RequestID
+			fields["trace_id"] = reqCtx.TraceID
+		}
+
+		switch {
+		case status >= 500:
+			logging.Error("HTTP request", nil, fields)
+		case status >= 400:
+			logging.Warn("HTTP request", fields)
+		default:
+			logging.Info("HTTP request", fields)
+		}
+	}
+}
+
+// latencyBucket returns the upper bound of the HTTPDuration bucket the duration falls into,
+// so log queries can group requests the same way the histogram does
+func latencyBucket(duration time.Duration) string {
+	seconds := duration.Seconds()
+	for _, bound := range metrics.HTTPDurationBuckets {
+		if seconds <= bound {
+			return strconv.FormatFloat(bound, 'f', -1, 64)
+		}
+	}
+	return "+Inf"
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bank-api/internal/pkg/errors"
+	"bytes"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySize rejects request bodies larger than limit bytes with 413 before any handler reads
+// them. A declared Content-Length is checked up front; bodies of unknown length (chunked) are
+// read up to the limit and buffered for the handler. A limit of 0 or less disables the check.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			abortRequestTooLarge(c)
+			return
+		}
+
+		if c.Request.ContentLength < 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+			var tooLarge *http.MaxBytesError
+			if stderrors.As(err, &tooLarge) {
+				abortRequestTooLarge(c)
+				return
+			}
+			if err != nil {
+				apiErr := errors.NewValidationError("Failed to read request body")
+				c.AbortWithStatusJSON(apiErr.Status, apiErr)
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		c.Next()
+	}
+}
+
+func abortRequestTooLarge(c *gin.Context) {
+	apiErr := errors.NewRequestTooLargeError()
+	c.AbortWithStatusJSON(apiErr.Status, apiErr)
+}
@@ -11,12 +11,20 @@ import (
 // CORS adds Cross-Origin Resource Sharing headers to each response
 // allowing the dashboard to communicate with the API from configured origins.
 func CORS(cfg *config.Config) gin.HandlerFunc {
+	return CORSWithConfig(cfg.CORS)
+}
+
+// CORSWithConfig applies a single CORS policy, so route groups can each carry their own.
+// With RejectDisallowedOrigins set, a request from an origin outside the allow list is
+// refused with 403 instead of receiving the fallback origin; requests without an Origin
+// header (server-to-server, curl) are unaffected.
+func CORSWithConfig(cors config.CORSConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
 		// Check if origin is allowed
 		allowed := false
-		for _, allowedOrigin := range cfg.CORS.AllowOrigins {
+		for _, allowedOrigin := range cors.AllowOrigins {
 			if allowedOrigin == "*" || allowedOrigin == origin {
 				allowed = true
 				c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
@@ -24,22 +32,27 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
 			}
 		}
 
-		if !allowed && len(cfg.CORS.AllowOrigins) > 0 {
+		if !allowed && origin != "" && cors.RejectDisallowedOrigins {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		if !allowed && len(cors.AllowOrigins) > 0 {
 			// If origin not allowed, set to first allowed origin (fallback)
-			c.Writer.Header().Set("Access-Control-Allow-Origin", cfg.CORS.AllowOrigins[0])
+			c.Writer.Header().Set("Access-Control-Allow-Origin", cors.AllowOrigins[0])
 		}
 
-		if cfg.CORS.AllowCredentials {
+		if cors.AllowCredentials {
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
 
 		c.Writer.Header().Set(
 			"Access-Control-Allow-Headers",
-			strings.Join(cfg.CORS.AllowHeaders, ", "),
+			strings.Join(cors.AllowHeaders, ", "),
 		)
 		c.Writer.Header().Set(
 			"Access-Control-Allow-Methods",
-			strings.Join(cfg.CORS.AllowMethods, ", "),
+			strings.Join(cors.AllowMethods, ", "),
 		)
 
 		if c.Request.Method == http.MethodOptions {
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bank-api/internal/pkg/errors"
+	"bank-api/internal/pkg/telemetry"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadSheddingRetryAfter is the Retry-After hint sent with shed requests
+const loadSheddingRetryAfter = time.Second
+
+// MaxInFlight caps the number of requests served concurrently. Once limit requests are in
+// flight, further ones are rejected immediately with 503 and a Retry-After header instead of
+// queueing, so latency degrades gracefully under overload. Paths in exempt (health probes,
+// metrics scrapes) are never shed. A limit of 0 or less disables shedding.
+func MaxInFlight(limit int, exempt ...string) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	semaphore := make(chan struct{}, limit)
+	retryAfter := strconv.Itoa(int(loadSheddingRetryAfter.Seconds()))
+
+	return func(c *gin.Context) {
+		if exemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+			c.Next()
+		default:
+			metrics.RecordRequestShed()
+			apiErr := errors.NewServiceOverloadedError()
+			c.Header("Retry-After", retryAfter)
+			c.AbortWithStatusJSON(apiErr.Status, apiErr)
+		}
+	}
+}
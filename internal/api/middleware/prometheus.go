@@ -32,8 +32,7 @@ func PrometheusMiddleware() gin.HandlerFunc {
 		}
 		statusCode := strconv.Itoa(c.Writer.Status())
 
-		// Record metrics
-		metrics.HTTPDuration.WithLabelValues(method, endpoint, statusCode).Observe(duration.Seconds())
+		// Record metrics (the HTTPDuration histogram is observed by AccessLog)
 		metrics.HTTPRequestsTotal.WithLabelValues(method, endpoint, statusCode).Inc()
 
 		// Also record in existing metrics system for compatibility
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bank-api/internal/pkg/tracing"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +17,12 @@ func RequestContextMiddleware() gin.HandlerFunc {
 		// Store in gin context for handlers to access
 		c.Set(RequestContextKey, reqCtx)
 
+		// Handlers pass c.Request.Context() to the repository, so give it the request deadline
+		c.Request = c.Request.WithContext(reqCtx.Context)
+
+		// Echo the trace ID so clients that didn't send one can correlate the request
+		c.Header(tracing.Header, reqCtx.TraceID)
+
 		// Log request start
 		reqCtx.Logger.Info("Request started", map[string]interface{}{
 			"method":     c.Request.Method,
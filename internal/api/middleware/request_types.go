@@ -3,6 +3,7 @@ package middleware
 import (
 	"bank-api/internal/infrastructure/database"
 	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/tracing"
 	"context"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 type RequestContext struct {
 	// Request metadata
 	RequestID  string
+	TraceID    string // Client-supplied X-Trace-Id, or generated; carried into published events
 	UserIP     string
 	UserAgent  string
 	StartTime  time.Time
@@ -30,6 +32,7 @@ type RequestContext struct {
 // RequestLogger provides request-scoped logging with automatic field injection
 type RequestLogger struct {
 	requestID string
+	traceID   string
 	userIP    string
 }
 
@@ -37,12 +40,16 @@ type RequestLogger struct {
 // This should be called at the beginning of each HTTP handler
 func NewRequestContext(ginCtx *gin.Context) *RequestContext {
 	requestID := uuid.New().String()
+	traceID := tracing.Resolve(ginCtx.GetHeader(tracing.Header))
 
-	// Create request context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Create request context with timeout; derived from the HTTP request so a client
+	// disconnect cancels it too
+	ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), 30*time.Second)
+	ctx = tracing.NewContext(ctx, traceID)
 
 	return &RequestContext{
 		RequestID:  requestID,
+		TraceID:    traceID,
 		UserIP:     ginCtx.ClientIP(),
 		UserAgent:  ginCtx.GetHeader("User-Agent"),
 		StartTime:  time.Now(),
@@ -54,6 +61,7 @@ func NewRequestContext(ginCtx *gin.Context) *RequestContext {
 		Database: database.Repo,
 		Logger: RequestLogger{
 			requestID: requestID,
+			traceID:   traceID,
 			userIP:    ginCtx.ClientIP(),
 		},
 	}
@@ -65,6 +73,7 @@ func (rl RequestLogger) Info(message string, fields map[string]interface{}) {
 		fields = make(map[string]interface{})
 	}
 	fields["request_id"] = rl.requestID
+	fields["trace_id"] = rl.traceID
 	fields["user_ip"] = rl.userIP
 
 	logging.Info(message, fields)
@@ -76,6 +85,7 @@ func (rl RequestLogger) Warn(message string, fields map[string]interface{}) {
 		fields = make(map[string]interface{})
 	}
 	fields["request_id"] = rl.requestID
+	fields["trace_id"] = rl.traceID
 	fields["user_ip"] = rl.userIP
 
 	logging.Warn(message, fields)
@@ -87,6 +97,7 @@ func (rl RequestLogger) Error(message string, err error, fields map[string]inter
 		fields = make(map[string]interface{})
 	}
 	fields["request_id"] = rl.requestID
+	fields["trace_id"] = rl.traceID
 	fields["user_ip"] = rl.userIP
 
 	logging.Error(message, err, fields)
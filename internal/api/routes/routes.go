@@ -3,24 +3,94 @@ package routes
 import (
 	"bank-api/internal/api/handlers"
 	"bank-api/internal/api/middleware"
+	"bank-api/internal/config"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CORSPolicies holds the CORS policy applied to each route group
+type CORSPolicies struct {
+	Public config.CORSConfig // Customer-facing banking operations
+	Admin  config.CORSConfig // Operator endpoints: freeze, overdraft, metrics
+}
+
 // RegisterRoutes registers all routes with the container dependencies
-func RegisterRoutes(router *gin.Engine, container handlers.HandlerDependencies) {
+func RegisterRoutes(router *gin.Engine, container handlers.HandlerDependencies, cors CORSPolicies) {
 	router.Use(middleware.RequestContextMiddleware()) // Add request-scoped context (first!)
+	router.Use(middleware.AccessLog())                // One structured log line + latency histogram per request
 	router.Use(middleware.Metrics())
 	router.Use(middleware.PrometheusMiddleware()) // Add Prometheus metrics collection
 
+	// Groups must be created after the global middleware so they inherit it
+	public := newCORSGroup(router, cors.Public)
+	admin := newCORSGroup(router, cors.Admin)
+
 	// Banking operations - using closure-based handlers with container dependencies
-	router.POST("/accounts", handlers.MakeCreateAccountHandler(container))
-	router.GET("/accounts/:id/balance", handlers.MakeGetBalanceHandler(container))
-	router.POST("/accounts/:id/deposit", handlers.MakeDepositHandler(container))
-	router.POST("/accounts/:id/withdraw", handlers.MakeWithdrawHandler(container))
-	router.POST("/accounts/transfer", handlers.MakeTransferHandler(container))
+	public.POST("/accounts", handlers.MakeCreateAccountHandler(container))
+	public.GET("/accounts", handlers.MakeListAccountsHandler(container))
+	public.GET("/accounts/:id/balance", handlers.MakeGetBalanceHandler(container))
+	public.DELETE("/accounts/:id", handlers.MakeCloseAccountHandler(container))
+	public.POST("/accounts/:id/holds", handlers.MakePlaceHoldHandler(container))
+	public.POST("/holds/:id/capture", handlers.MakeCaptureHoldHandler(container))
+	public.POST("/holds/:id/release", handlers.MakeReleaseHoldHandler(container))
+	public.GET("/operations/:id", handlers.MakeGetOperationHandler(container))
+	public.GET("/accounts/:id/transactions", handlers.MakeTransactionHistoryHandler(container))
+	public.POST("/accounts/:id/deposit", handlers.MakeDepositHandler(container))
+	public.POST("/accounts/:id/withdraw", handlers.MakeWithdrawHandler(container))
+	public.POST("/accounts/transfer", handlers.MakeTransferHandler(container))
+	public.POST("/accounts/transfer/batch", handlers.MakeBatchTransferHandler(container))
+
+	// Account administration
+	admin.POST("/accounts/bulk", handlers.MakeBulkCreateAccountsHandler(container))
+	admin.PUT("/accounts/:id/overdraft", handlers.MakeSetOverdraftLimitHandler(container))
+	admin.POST("/accounts/:id/freeze", handlers.MakeFreezeAccountHandler(container))
+	admin.POST("/accounts/:id/unfreeze", handlers.MakeUnfreezeAccountHandler(container))
+	admin.POST("/accounts/:id/restore", handlers.MakeRestoreAccountHandler(container))
 
 	// System endpoints
-	router.GET("/metrics", handlers.GetMetrics)
-	router.GET("/prometheus", handlers.PrometheusMetrics)
+	admin.GET("/metrics", handlers.GetMetrics)
+	admin.GET("/metrics/business", handlers.MakeBusinessMetricsHandler(container))
+	admin.GET("/prometheus", handlers.PrometheusMetrics)
+	public.GET("/readyz", handlers.MakeReadinessHandler(container))
+	public.GET("/healthz", handlers.MakeHealthHandler(container))
+}
+
+// corsGroup is a route group sharing one CORS policy. Group middleware only runs for matched
+// routes, so every path also gets an OPTIONS route for the policy to answer preflights on.
+type corsGroup struct {
+	group     *gin.RouterGroup
+	preflight map[string]bool
+}
+
+func newCORSGroup(router *gin.Engine, cors config.CORSConfig) *corsGroup {
+	return &corsGroup{
+		group:     router.Group("", middleware.CORSWithConfig(cors)),
+		preflight: make(map[string]bool),
+	}
+}
+
+func (g *corsGroup) GET(path string, handler gin.HandlerFunc) {
+	g.handle(http.MethodGet, path, handler)
+}
+
+func (g *corsGroup) POST(path string, handler gin.HandlerFunc) {
+	g.handle(http.MethodPost, path, handler)
+}
+
+func (g *corsGroup) PUT(path string, handler gin.HandlerFunc) {
+	g.handle(http.MethodPut, path, handler)
+}
+
+func (g *corsGroup) DELETE(path string, handler gin.HandlerFunc) {
+	g.handle(http.MethodDelete, path, handler)
+}
+
+func (g *corsGroup) handle(method, path string, handler gin.HandlerFunc) {
+	g.group.Handle(method, path, handler)
+	if !g.preflight[path] {
+		g.preflight[path] = true
+		// Never reached: the CORS middleware answers every OPTIONS request itself
+		g.group.OPTIONS(path, func(c *gin.Context) {})
+	}
 }
@@ -12,13 +12,23 @@ type Config struct {
 	Database    DatabaseConfig
 	RateLimit   RateLimitConfig
 	CORS        CORSConfig
+	AdminCORS   CORSConfig // Policy for operator endpoints (freeze, overdraft, metrics)
 	Logging     LoggingConfig
+	Interest    InterestConfig
+	Limits      LimitsConfig
+	Callbacks   CallbacksConfig
+	Runtime     RuntimeConfig
 	Environment string
 }
 
 type ServerConfig struct {
-	Port string
-	Host string
+	Port     string
+	Host     string
+	GRPCPort string // empty disables the gRPC listener
+	// MaxInFlightRequests caps concurrent HTTP requests; excess ones get 503 (0 = unlimited)
+	MaxInFlightRequests int
+	// MaxRequestBodyBytes caps HTTP request bodies; larger ones get 413 (0 = unlimited)
+	MaxRequestBodyBytes int64
 }
 
 type RateLimitConfig struct {
@@ -31,6 +41,9 @@ type CORSConfig struct {
 	AllowMethods     []string
 	AllowHeaders     []string
 	AllowCredentials bool
+	// RejectDisallowedOrigins refuses requests from unlisted origins instead of
+	// answering with the first allowed origin
+	RejectDisallowedOrigins bool
 }
 
 type DatabaseConfig struct {
@@ -43,30 +56,80 @@ type LoggingConfig struct {
 	Format string
 }
 
+// InterestConfig controls scheduled interest accrual; a zero Rate disables it
+type InterestConfig struct {
+	Rate     float64 // fraction of the balance credited per interval (e.g. 0.001 = 0.1%)
+	Interval time.Duration
+}
+
+// LimitsConfig bounds single transaction amounts, in hundredths of a major unit (centavos for BRL)
+type LimitsConfig struct {
+	MinTransactionAmount int
+	MaxTransactionAmount int
+}
+
+// CallbacksConfig controls where deposit callback_url notifications may be sent
+type CallbacksConfig struct {
+	// AllowPrivateHosts permits loopback, private and link-local targets (local development only)
+	AllowPrivateHosts bool
+}
+
+// RuntimeConfig tunes the Go runtime; GOGC itself is read by the runtime directly
+type RuntimeConfig struct {
+	BallastMB int // size of the GC heap ballast in megabytes (0 = none)
+}
+
 func Load() *Config {
+	cors := CORSConfig{
+		AllowOrigins:     getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173"}),
+		AllowMethods:     getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		AllowHeaders:     getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "Accept", "X-Requested-With"}),
+		AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
+	}
+
+	// The admin policy shares methods and headers with the public one but keeps its own
+	// origin list, and never falls back for an unlisted origin
+	adminCORS := cors
+	adminCORS.AllowOrigins = getEnvAsSlice("ADMIN_CORS_ALLOWED_ORIGINS", cors.AllowOrigins)
+	adminCORS.RejectDisallowedOrigins = true
+
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "localhost"),
+			Port:     getEnv("SERVER_PORT", "8080"),
+			Host:     getEnv("SERVER_HOST", "localhost"),
+			GRPCPort: getEnv("GRPC_PORT", ""),
+
+			MaxInFlightRequests: getEnvAsInt("MAX_IN_FLIGHT_REQUESTS", 0),
+			MaxRequestBodyBytes: int64(getEnvAsInt("MAX_REQUEST_BODY_BYTES", 1<<20)),
 		},
 		Database: DatabaseConfig{
-			Type: getEnv("DATABASE_TYPE", "inmemory"),
+			Type: getEnv("DATABASE_TYPE", "postgres"),
 			DSN:  getEnv("DATABASE_DSN", ""),
 		},
 		RateLimit: RateLimitConfig{
 			RequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
 			Window:            time.Minute,
 		},
-		CORS: CORSConfig{
-			AllowOrigins:     getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173"}),
-			AllowMethods:     getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowHeaders:     getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "Accept", "X-Requested-With"}),
-			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
-		},
+		CORS:      cors,
+		AdminCORS: adminCORS,
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Interest: InterestConfig{
+			Rate:     getEnvAsFloat("INTEREST_RATE", 0),
+			Interval: getEnvAsDuration("INTEREST_INTERVAL", 24*time.Hour),
+		},
+		Limits: LimitsConfig{
+			MinTransactionAmount: getEnvAsInt("MIN_TRANSACTION_AMOUNT", 1),
+			MaxTransactionAmount: getEnvAsInt("MAX_TRANSACTION_AMOUNT", 1000000),
+		},
+		Callbacks: CallbacksConfig{
+			AllowPrivateHosts: getEnvAsBool("DEPOSIT_CALLBACK_ALLOW_PRIVATE_HOSTS", false),
+		},
+		Runtime: RuntimeConfig{
+			BallastMB: getEnvAsInt("GO_BALLAST_MB", 0),
+		},
 		Environment: getEnv("ENVIRONMENT", "development"),
 	}
 }
@@ -86,6 +149,22 @@ func getEnvAsInt(name string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvAsFloat(name string, defaultVal float64) float64 {
+	valueStr := getEnv(name, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultVal
+}
+
+func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
+	valueStr := getEnv(name, "")
+	if value, err := time.ParseDuration(valueStr); err == nil && value > 0 {
+		return value
+	}
+	return defaultVal
+}
+
 func getEnvAsBool(name string, defaultVal bool) bool {
 	valStr := getEnv(name, "")
 	if val, err := strconv.ParseBool(valStr); err == nil {
@@ -13,7 +13,7 @@ func withAccountLock(acc *models.Account, fn func()) {
 }
 
 func AddAmount(acc *models.Account, amount int) error {
-	if err := validation.ValidateAmount(amount); err != nil {
+	if err := validation.ValidateAmount(amount, acc.Currency); err != nil {
 		return err
 	}
 
@@ -25,7 +25,7 @@ func AddAmount(acc *models.Account, amount int) error {
 }
 
 func RemoveAmount(acc *models.Account, amount int) error {
-	if err := validation.ValidateAmount(amount); err != nil {
+	if err := validation.ValidateAmount(amount, acc.Currency); err != nil {
 		return err
 	}
 
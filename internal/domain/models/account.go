@@ -5,11 +5,32 @@ import (
 	"time"
 )
 
+// Account lifecycle states
+const (
+	AccountStatusActive = "active"
+	AccountStatusClosed = "closed"
+)
+
+// Account types; savings accounts allow a limited number of withdrawals per period
+const (
+	AccountTypeChecking = "checking"
+	AccountTypeSavings  = "savings"
+)
+
+// DefaultCurrency is the ISO 4217 code used when an account is opened without one
+const DefaultCurrency = "BRL"
+
 type Account struct {
-	Id        int       `json:"id"`
-	Owner     string    `json:"owner_name"`
-	Balance   int       `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	Id             int       `json:"id"`
+	Owner          string    `json:"owner_name"`
+	Balance        int       `json:"balance"`
+	Status         string    `json:"status"`
+	OverdraftLimit int       `json:"overdraft_limit"` // How far below zero the balance may go, in cents
+	Currency       string    `json:"currency"`        // ISO 4217 code the balance is held in
+	Frozen         bool      `json:"frozen"`          // Blocks deposits, withdrawals and transfers while set
+	AccountType    string    `json:"account_type"`    // AccountTypeChecking or AccountTypeSavings
+	Version        int       `json:"version"`         // Incremented on every update (optimistic locking)
+	CreatedAt      time.Time `json:"created_at"`
 
 	Mu sync.Mutex `json:"-"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Hold lifecycle states
+const (
+	HoldStatusActive   = "active"
+	HoldStatusReleased = "released"
+	HoldStatusCaptured = "captured"
+)
+
+// Hold reserves funds on an account. While active, the amount counts against the available
+// balance without being debited; capturing it debits the account, releasing it frees the funds.
+type Hold struct {
+	Id        int       `json:"id"`
+	AccountID int       `json:"account_id"`
+	Amount    int       `json:"amount"` // in cents
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Operation lifecycle states; completed, failed and duplicate are final. Duplicate means the
+// idempotency key had already been applied, so this operation moved no money of its own.
+const (
+	OperationStatusPending   = "pending"
+	OperationStatusCompleted = "completed"
+	OperationStatusFailed    = "failed"
+	OperationStatusDuplicate = "duplicate"
+)
+
+// Operation tracks an asynchronous request (e.g. a deposit accepted with 202) from the moment
+// it is published until the consumer has processed it
+type Operation struct {
+	ID        string    `json:"operation_id"`
+	Type      string    `json:"type"`
+	AccountID int       `json:"account_id"`
+	Amount    int       `json:"amount"` // in cents
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"` // set when Status is failed
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,986 @@
+// Package memory provides an in-memory database.Repository for unit tests and quick local runs
+// (DATABASE_TYPE=memory). It mirrors the PostgreSQL repository's behaviour, including
+// idempotency, holds, limits and the transaction log, and returns the same sentinel errors from
+// the postgres package so callers can't tell the two apart. Nothing survives a restart.
+package memory
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accountRecord is the stored form of an account; models.Account embeds a mutex and is only
+// built on the way out
+type accountRecord struct {
+	id              int
+	owner           string
+	balance         int // in cents
+	status          string
+	overdraftLimit  int
+	currency        string
+	frozen          bool
+	accountType     string
+	version         int
+	clientRequestID string
+	createdAt       time.Time
+	deletedAt       *time.Time
+}
+
+func (a *accountRecord) toModel() *models.Account {
+	return &models.Account{
+		Id:             a.id,
+		Owner:          a.owner,
+		Balance:        a.balance,
+		Status:         a.status,
+		OverdraftLimit: a.overdraftLimit,
+		Currency:       a.currency,
+		Frozen:         a.frozen,
+		AccountType:    a.accountType,
+		Version:        a.version,
+		CreatedAt:      a.createdAt,
+	}
+}
+
+// processedOperation is an idempotency record
+type processedOperation struct {
+	resultBalance int
+	processedAt   time.Time
+}
+
+// InMemoryRepository implements the Repository interface with maps guarded by a single mutex.
+// Every operation runs under the lock, which makes each one atomic the way a database
+// transaction is.
+type InMemoryRepository struct {
+	mu sync.RWMutex
+
+	accounts      map[int]*accountRecord
+	nextAccountID int
+
+	transactions []postgres.TransactionRecord // in ID order
+	processed    map[string]processedOperation
+	holds        map[int]*models.Hold
+	nextHoldID   int
+	operations   map[string]*models.Operation
+
+	// Credits may not take a balance above this many hundredths of the major unit (0 = no cap)
+	maxBalance int
+	// Savings accounts may make this many withdrawals per savingsWithdrawalPeriod (0 = no limit)
+	savingsWithdrawalLimit  int
+	savingsWithdrawalPeriod time.Duration
+}
+
+// NewInMemoryRepository creates an empty repository. The balance cap and savings withdrawal
+// limits are taken from cfg like the PostgreSQL repository does; a nil cfg means no limits.
+func NewInMemoryRepository(cfg *postgres.Config) *InMemoryRepository {
+	r := &InMemoryRepository{}
+	if cfg != nil {
+		r.maxBalance = cfg.MaxAccountBalance
+		r.savingsWithdrawalLimit = cfg.SavingsWithdrawalLimit
+		if period, err := time.ParseDuration(cfg.SavingsWithdrawalPeriod); err == nil && period > 0 {
+			r.savingsWithdrawalPeriod = period
+		}
+	}
+	r.reset()
+	return r
+}
+
+// exceedsMaxBalance reports whether balance, in minor units of currency, is above the cap
+func (r *InMemoryRepository) exceedsMaxBalance(balance int, currency string) bool {
+	return r.maxBalance > 0 && balance > postgres.ScaleMaxBalance(r.maxBalance, currency)
+}
+
+func (r *InMemoryRepository) reset() {
+	r.accounts = make(map[int]*accountRecord)
+	r.nextAccountID = 1
+	r.transactions = nil
+	r.processed = make(map[string]processedOperation)
+	r.holds = make(map[int]*models.Hold)
+	r.nextHoldID = 1
+	r.operations = make(map[string]*models.Operation)
+}
+
+// Ping always succeeds; there is nothing to connect to
+func (r *InMemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Reset clears all data and restarts IDs at 1
+// WARNING: This is only for testing purposes
+func (r *InMemoryRepository) Reset(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reset()
+	log.Println("In-memory database reset completed")
+}
+
+// CreateAccount creates a new account with the given owner in the default currency
+func (r *InMemoryRepository) CreateAccount(ctx context.Context, owner string) int {
+	return r.CreateAccountWithCurrency(ctx, owner, models.DefaultCurrency)
+}
+
+// CreateAccountWithCurrency creates a new checking account holding balances in the given currency
+func (r *InMemoryRepository) CreateAccountWithCurrency(ctx context.Context, owner string, currency string) int {
+	return r.CreateAccountWithType(ctx, owner, currency, models.AccountTypeChecking)
+}
+
+// CreateAccountWithType creates a new account of the given type (checking or savings)
+func (r *InMemoryRepository) CreateAccountWithType(ctx context.Context, owner string, currency string, accountType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insertAccount(owner, currency, accountType, "").id
+}
+
+// insertAccount stores a new zero-balance account. Must be called with the lock held.
+func (r *InMemoryRepository) insertAccount(owner string, currency string, accountType string, clientRequestID string) *accountRecord {
+	account := &accountRecord{
+		id:              r.nextAccountID,
+		owner:           owner,
+		status:          models.AccountStatusActive,
+		currency:        currency,
+		accountType:     accountType,
+		version:         1,
+		clientRequestID: clientRequestID,
+		createdAt:       time.Now().UTC(),
+	}
+	r.accounts[account.id] = account
+	r.nextAccountID++
+	return account
+}
+
+// CreateAccountIdempotent creates an account tagged with the client's request ID. Replaying the
+// same clientRequestID returns the account created the first time and created=false.
+func (r *InMemoryRepository) CreateAccountIdempotent(ctx context.Context, owner string, currency string, accountType string, clientRequestID string) (*models.Account, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, account := range r.accounts {
+		if account.clientRequestID == clientRequestID {
+			return account.toModel(), false, nil
+		}
+	}
+
+	return r.insertAccount(owner, currency, accountType, clientRequestID).toModel(), true, nil
+}
+
+// CreateAccountsBulk opens count checking accounts named "<ownerPrefix>-1".."<ownerPrefix>-N",
+// crediting each with initialBalance (cents) when it is positive
+func (r *InMemoryRepository) CreateAccountsBulk(ctx context.Context, ownerPrefix string, count int, initialBalance int) ([]int, error) {
+	if r.exceedsMaxBalance(initialBalance, models.DefaultCurrency) {
+		return nil, postgres.ErrBalanceLimitExceeded
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]int, 0, count)
+	for i := 1; i <= count; i++ {
+		account := r.insertAccount(fmt.Sprintf("%s-%d", ownerPrefix, i), models.DefaultCurrency, models.AccountTypeChecking, "")
+		if initialBalance > 0 {
+			account.balance = initialBalance
+			r.recordTransaction(account.id, "deposit", initialBalance, initialBalance, "")
+		}
+		ids = append(ids, account.id)
+	}
+
+	return ids, nil
+}
+
+// GetAccount retrieves a live (not soft-deleted) account by ID
+func (r *InMemoryRepository) GetAccount(ctx context.Context, id int) (*models.Account, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return nil, false
+	}
+	return account.toModel(), true
+}
+
+// liveAccount returns the account unless it is missing or soft-deleted. Must be called with the lock held.
+func (r *InMemoryRepository) liveAccount(id int) (*accountRecord, bool) {
+	account, ok := r.accounts[id]
+	if !ok || account.deletedAt != nil {
+		return nil, false
+	}
+	return account, true
+}
+
+// ListAccounts returns a page of live accounts ordered by ID, plus the total number of live accounts
+func (r *InMemoryRepository) ListAccounts(ctx context.Context, limit int, offset int) ([]*models.Account, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.accounts))
+	for id, account := range r.accounts {
+		if account.deletedAt == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	accounts := make([]*models.Account, 0, limit)
+	for i := offset; i < len(ids) && len(accounts) < limit; i++ {
+		accounts = append(accounts, r.accounts[ids[i]].toModel())
+	}
+
+	return accounts, len(ids), nil
+}
+
+// UpdateAccount overwrites an account's balance
+func (r *InMemoryRepository) UpdateAccount(ctx context.Context, acc *models.Account) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if account, ok := r.liveAccount(acc.Id); ok {
+		account.balance = acc.Balance
+		account.version++
+	}
+}
+
+// UpdateAccountVersioned overwrites the balance only if the stored version still matches
+// expectedVersion. Returns ErrAccountNotFound or ErrVersionConflict
+func (r *InMemoryRepository) UpdateAccountVersioned(ctx context.Context, acc *models.Account, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.liveAccount(acc.Id)
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.version != expectedVersion {
+		return postgres.ErrVersionConflict
+	}
+
+	account.balance = acc.Balance
+	account.version++
+	acc.Version = account.version
+	return nil
+}
+
+// CloseAccount closes a zero-balance account.
+// Returns ErrAccountNotFound, ErrAccountClosed if already closed, or ErrAccountHasBalance
+func (r *InMemoryRepository) CloseAccount(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.status == models.AccountStatusClosed {
+		return postgres.ErrAccountClosed
+	}
+	if account.balance != 0 {
+		return postgres.ErrAccountHasBalance
+	}
+
+	account.status = models.AccountStatusClosed
+	account.version++
+	return nil
+}
+
+// SoftDeleteAccount hides a zero-balance account from reads and operations, keeping its
+// transactions. Returns ErrAccountNotFound (also for an already deleted account) or ErrAccountHasBalance
+func (r *InMemoryRepository) SoftDeleteAccount(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.balance != 0 {
+		return postgres.ErrAccountHasBalance
+	}
+
+	now := time.Now().UTC()
+	account.deletedAt = &now
+	account.version++
+	return nil
+}
+
+// RestoreAccount undoes SoftDeleteAccount; restoring a live account is a no-op.
+// Returns ErrAccountNotFound if no account has the ID
+func (r *InMemoryRepository) RestoreAccount(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.deletedAt != nil {
+		account.deletedAt = nil
+		account.version++
+	}
+	return nil
+}
+
+// SetOverdraftLimit sets how far below zero (in minor units of the account currency) the
+// account balance may go.
+// Returns ErrAccountNotFound, ErrAccountClosed, or ErrOverdraftInUse
+func (r *InMemoryRepository) SetOverdraftLimit(ctx context.Context, id int, limitCents int) error {
+	if limitCents < 0 {
+		return fmt.Errorf("overdraft limit must not be negative, got %d", limitCents)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.status == models.AccountStatusClosed {
+		return postgres.ErrAccountClosed
+	}
+	if account.balance < -limitCents {
+		return postgres.ErrOverdraftInUse
+	}
+
+	account.overdraftLimit = limitCents
+	account.version++
+	return nil
+}
+
+// SetFrozen freezes or unfreezes an account.
+// Returns ErrAccountNotFound, or ErrAccountClosed if the account has been closed
+func (r *InMemoryRepository) SetFrozen(ctx context.Context, id int, frozen bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return postgres.ErrAccountNotFound
+	}
+	if account.status == models.AccountStatusClosed {
+		return postgres.ErrAccountClosed
+	}
+
+	account.frozen = frozen
+	account.version++
+	return nil
+}
+
+// usableAccount returns a live account that money may move in or out of.
+// Returns ErrAccountNotFound, ErrAccountClosed or ErrAccountFrozen. Must be called with the lock held.
+func (r *InMemoryRepository) usableAccount(id int) (*accountRecord, error) {
+	account, ok := r.liveAccount(id)
+	if !ok {
+		return nil, postgres.ErrAccountNotFound
+	}
+	if account.status == models.AccountStatusClosed {
+		return nil, postgres.ErrAccountClosed
+	}
+	if account.frozen {
+		return nil, postgres.ErrAccountFrozen
+	}
+	return account, nil
+}
+
+// heldAmount returns the total of the account's active holds. Must be called with the lock held.
+func (r *InMemoryRepository) heldAmount(accountID int) int {
+	held := 0
+	for _, hold := range r.holds {
+		if hold.AccountID == accountID && hold.Status == models.HoldStatusActive {
+			held += hold.Amount
+		}
+	}
+	return held
+}
+
+// checkDebit returns ErrInsufficientFunds if the account's available balance (balance minus
+// active holds, plus overdraft) doesn't cover amount. Must be called with the lock held.
+func (r *InMemoryRepository) checkDebit(account *accountRecord, amount int) error {
+	if account.balance-r.heldAmount(account.id)-amount < -account.overdraftLimit {
+		return postgres.ErrInsufficientFunds
+	}
+	return nil
+}
+
+// checkWithdrawalLimit returns ErrWithdrawalLimitExceeded if a savings account has already made
+// savingsWithdrawalLimit withdrawals within the trailing period. Must be called with the lock held.
+func (r *InMemoryRepository) checkWithdrawalLimit(account *accountRecord) error {
+	if account.accountType != models.AccountTypeSavings || r.savingsWithdrawalLimit <= 0 || r.savingsWithdrawalPeriod <= 0 {
+		return nil
+	}
+
+	since := time.Now().UTC().Add(-r.savingsWithdrawalPeriod)
+	withdrawals := 0
+	for _, tx := range r.transactions {
+		if tx.AccountID == account.id && tx.Type == "withdraw" && tx.CreatedAt.After(since) {
+			withdrawals++
+		}
+	}
+
+	if withdrawals >= r.savingsWithdrawalLimit {
+		return postgres.ErrWithdrawalLimitExceeded
+	}
+	return nil
+}
+
+// recordTransaction appends to the transaction log; debits are stored as negative amounts.
+// Must be called with the lock held.
+func (r *InMemoryRepository) recordTransaction(accountID int, txType string, amount int, balanceAfter int, referenceID string) {
+	if txType == "withdraw" || txType == "transfer_out" {
+		amount = -amount
+	}
+
+	r.transactions = append(r.transactions, postgres.TransactionRecord{
+		ID:           len(r.transactions) + 1,
+		AccountID:    accountID,
+		Type:         txType,
+		Amount:       amount,
+		BalanceAfter: balanceAfter,
+		ReferenceID:  referenceID,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// PlaceHold reserves amount (in cents) on the account without debiting it.
+// Returns ErrAccountNotFound, ErrAccountClosed, ErrAccountFrozen or ErrInsufficientFunds
+func (r *InMemoryRepository) PlaceHold(ctx context.Context, accountID int, amount int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, err := r.usableAccount(accountID)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.checkDebit(account, amount); err != nil {
+		return 0, err
+	}
+
+	hold := &models.Hold{
+		Id:        r.nextHoldID,
+		AccountID: accountID,
+		Amount:    amount,
+		Status:    models.HoldStatusActive,
+		CreatedAt: time.Now().UTC(),
+	}
+	r.holds[hold.Id] = hold
+	r.nextHoldID++
+
+	return hold.Id, nil
+}
+
+// activeHold returns a hold that can still be released or captured.
+// Returns ErrHoldNotFound or ErrHoldNotActive. Must be called with the lock held.
+func (r *InMemoryRepository) activeHold(holdID int) (*models.Hold, error) {
+	hold, ok := r.holds[holdID]
+	if !ok {
+		return nil, postgres.ErrHoldNotFound
+	}
+	if hold.Status != models.HoldStatusActive {
+		return nil, postgres.ErrHoldNotActive
+	}
+	return hold, nil
+}
+
+// ReleaseHold frees the funds reserved by an active hold without debiting them.
+// Returns ErrHoldNotFound or ErrHoldNotActive
+func (r *InMemoryRepository) ReleaseHold(ctx context.Context, holdID int) (*models.Hold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hold, err := r.activeHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.Status = models.HoldStatusReleased
+	released := *hold
+	return &released, nil
+}
+
+// CaptureHold debits the amount reserved by an active hold and records it as a withdrawal.
+// Returns ErrHoldNotFound, ErrHoldNotActive, ErrAccountClosed or ErrAccountFrozen
+func (r *InMemoryRepository) CaptureHold(ctx context.Context, holdID int) (*models.Hold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hold, err := r.activeHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := r.usableAccount(hold.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account.balance -= hold.Amount
+	account.version++
+	r.recordTransaction(account.id, "withdraw", hold.Amount, account.balance, "")
+
+	hold.Status = models.HoldStatusCaptured
+	captured := *hold
+	return &captured, nil
+}
+
+// CreatePendingOperation records an operation as pending before it is published
+func (r *InMemoryRepository) CreatePendingOperation(ctx context.Context, operationID string, operationType string, accountID int, amount int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.operations[operationID]; exists {
+		return fmt.Errorf("failed to record operation: operation %s already exists", operationID)
+	}
+
+	now := time.Now().UTC()
+	r.operations[operationID] = &models.Operation{
+		ID:        operationID,
+		Type:      operationType,
+		AccountID: accountID,
+		Amount:    amount,
+		Status:    models.OperationStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return nil
+}
+
+// SetOperationStatus moves a pending operation to completed, duplicate or failed (with a reason).
+// Final states are never overwritten; unknown operations are ignored
+func (r *InMemoryRepository) SetOperationStatus(ctx context.Context, operationID string, status string, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operation, ok := r.operations[operationID]
+	if !ok || operation.Status != models.OperationStatusPending {
+		return nil
+	}
+
+	operation.Status = status
+	operation.Reason = reason
+	operation.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// GetOperation returns the recorded status of an operation, or ErrOperationNotFound
+func (r *InMemoryRepository) GetOperation(ctx context.Context, operationID string) (*models.Operation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	operation, ok := r.operations[operationID]
+	if !ok {
+		return nil, postgres.ErrOperationNotFound
+	}
+	found := *operation
+	return &found, nil
+}
+
+// AtomicWithdraw debits amount from the account.
+// Returns ErrAccountNotFound, ErrAccountClosed, ErrAccountFrozen, ErrInsufficientFunds or ErrWithdrawalLimitExceeded
+func (r *InMemoryRepository) AtomicWithdraw(ctx context.Context, accountID int, amount int) (*models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.withdraw(accountID, amount)
+}
+
+// withdraw performs a withdrawal. Must be called with the lock held.
+func (r *InMemoryRepository) withdraw(accountID int, amount int) (*models.Account, error) {
+	account, err := r.usableAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkDebit(account, amount); err != nil {
+		return nil, err
+	}
+	if err := r.checkWithdrawalLimit(account); err != nil {
+		return nil, err
+	}
+
+	account.balance -= amount
+	account.version++
+	r.recordTransaction(accountID, "withdraw", amount, account.balance, "")
+
+	return account.toModel(), nil
+}
+
+// AtomicTransfer moves amount between two accounts.
+// Returns ErrAccountNotFound, ErrAccountClosed, ErrAccountFrozen, ErrCurrencyMismatch, ErrInsufficientFunds
+// or ErrBalanceLimitExceeded
+func (r *InMemoryRepository) AtomicTransfer(ctx context.Context, fromID int, toID int, amount int) (*models.Account, *models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.transfer(fromID, toID, amount)
+}
+
+// transfer performs a transfer. Must be called with the lock held.
+func (r *InMemoryRepository) transfer(fromID int, toID int, amount int) (*models.Account, *models.Account, error) {
+	fromAccount, ok := r.liveAccount(fromID)
+	if !ok {
+		return nil, nil, postgres.ErrAccountNotFound
+	}
+	toAccount, ok := r.liveAccount(toID)
+	if !ok {
+		return nil, nil, postgres.ErrAccountNotFound
+	}
+
+	if fromAccount.status == models.AccountStatusClosed || toAccount.status == models.AccountStatusClosed {
+		return nil, nil, postgres.ErrAccountClosed
+	}
+	if fromAccount.frozen || toAccount.frozen {
+		return nil, nil, postgres.ErrAccountFrozen
+	}
+	if fromAccount.currency != toAccount.currency {
+		return nil, nil, fmt.Errorf("cannot transfer %s to %s: %w", fromAccount.currency, toAccount.currency, postgres.ErrCurrencyMismatch)
+	}
+	if err := r.checkDebit(fromAccount, amount); err != nil {
+		return nil, nil, err
+	}
+	if r.exceedsMaxBalance(toAccount.balance+amount, toAccount.currency) {
+		return nil, nil, postgres.ErrBalanceLimitExceeded
+	}
+
+	fromAccount.balance -= amount
+	fromAccount.version++
+	toAccount.balance += amount
+	toAccount.version++
+
+	referenceID := uuid.New().String()
+	r.recordTransaction(fromID, "transfer_out", amount, fromAccount.balance, referenceID)
+	r.recordTransaction(toID, "transfer_in", amount, toAccount.balance, referenceID)
+
+	return fromAccount.toModel(), toAccount.toModel(), nil
+}
+
+// AtomicBatchTransfer moves money from one account to many; either every leg is applied or none is.
+// Returns the source account followed by each target account, in the order of targets.
+func (r *InMemoryRepository) AtomicBatchTransfer(ctx context.Context, fromID int, targets []postgres.Transfer) ([]*models.Account, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("batch transfer requires at least one target")
+	}
+
+	total := 0
+	seen := map[int]bool{fromID: true}
+	for _, t := range targets {
+		if t.Amount <= 0 {
+			return nil, fmt.Errorf("invalid amount %d for account %d", t.Amount, t.ToID)
+		}
+		if seen[t.ToID] {
+			return nil, fmt.Errorf("account %d appears more than once in batch transfer", t.ToID)
+		}
+		seen[t.ToID] = true
+		total += t.Amount
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Validate every account before touching any balance
+	fromAccount, err := r.usableAccount(fromID)
+	if err != nil {
+		return nil, fmt.Errorf("account %d: %w", fromID, err)
+	}
+	for _, t := range targets {
+		toAccount, err := r.usableAccount(t.ToID)
+		if err != nil {
+			return nil, fmt.Errorf("account %d: %w", t.ToID, err)
+		}
+		if toAccount.currency != fromAccount.currency {
+			return nil, fmt.Errorf("account %d: %w", t.ToID, postgres.ErrCurrencyMismatch)
+		}
+		if r.exceedsMaxBalance(toAccount.balance+t.Amount, toAccount.currency) {
+			return nil, fmt.Errorf("account %d: %w", t.ToID, postgres.ErrBalanceLimitExceeded)
+		}
+	}
+	if err := r.checkDebit(fromAccount, total); err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Account, 0, len(targets)+1)
+	for _, t := range targets {
+		toAccount := r.accounts[t.ToID]
+		fromAccount.balance -= t.Amount
+		toAccount.balance += t.Amount
+		toAccount.version++
+
+		// Each leg gets its own reference_id so its debit and credit rows can be paired
+		referenceID := uuid.New().String()
+		r.recordTransaction(fromID, "transfer_out", t.Amount, fromAccount.balance, referenceID)
+		r.recordTransaction(t.ToID, "transfer_in", t.Amount, toAccount.balance, referenceID)
+
+		result = append(result, toAccount.toModel())
+	}
+	fromAccount.version++
+
+	return append([]*models.Account{fromAccount.toModel()}, result...), nil
+}
+
+// AtomicDepositWithIdempotency credits amount unless idempotencyKey was already processed,
+// in which case it returns the recorded balance and ErrDuplicateOperation.
+// Returns ErrAccountNotFound, ErrAccountClosed, ErrAccountFrozen or ErrBalanceLimitExceeded;
+// a failed deposit doesn't consume the key
+func (r *InMemoryRepository) AtomicDepositWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if processed, ok := r.processed[idempotencyKey]; ok {
+		return &models.Account{Id: accountID, Balance: processed.resultBalance}, postgres.ErrDuplicateOperation
+	}
+
+	account, err := r.usableAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	newBalance := account.balance + amount
+	if r.exceedsMaxBalance(newBalance, account.currency) {
+		return nil, postgres.ErrBalanceLimitExceeded
+	}
+
+	account.balance = newBalance
+	account.version++
+	r.recordTransaction(accountID, "deposit", amount, newBalance, "")
+	r.processed[idempotencyKey] = processedOperation{resultBalance: newBalance, processedAt: time.Now().UTC()}
+
+	return account.toModel(), nil
+}
+
+// AtomicWithdrawWithIdempotency debits amount unless idempotencyKey was already processed,
+// in which case it returns the recorded balance and ErrDuplicateOperation.
+// A failed withdrawal (e.g. ErrInsufficientFunds) doesn't consume the key
+func (r *InMemoryRepository) AtomicWithdrawWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if processed, ok := r.processed[idempotencyKey]; ok {
+		return &models.Account{Id: accountID, Balance: processed.resultBalance}, postgres.ErrDuplicateOperation
+	}
+
+	account, err := r.withdraw(accountID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	r.processed[idempotencyKey] = processedOperation{resultBalance: account.Balance, processedAt: time.Now().UTC()}
+	return account, nil
+}
+
+// AtomicTransferWithIdempotency transfers amount unless idempotencyKey was already processed.
+// On replay it returns ErrDuplicateOperation with the source balance recorded by the original
+// transfer; the destination account carries only its ID
+func (r *InMemoryRepository) AtomicTransferWithIdempotency(ctx context.Context, fromID int, toID int, amount int, idempotencyKey string) (*models.Account, *models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if processed, ok := r.processed[idempotencyKey]; ok {
+		return &models.Account{Id: fromID, Balance: processed.resultBalance}, &models.Account{Id: toID}, postgres.ErrDuplicateOperation
+	}
+
+	fromAccount, toAccount, err := r.transfer(fromID, toID, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.processed[idempotencyKey] = processedOperation{resultBalance: fromAccount.Balance, processedAt: time.Now().UTC()}
+	return fromAccount, toAccount, nil
+}
+
+// CleanupProcessedOperations deletes idempotency records processed more than olderThan ago
+// Returns the number of records removed
+func (r *InMemoryRepository) CleanupProcessedOperations(ctx context.Context, olderThan time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().UTC().Add(-olderThan)
+	var removed int64
+	for key, processed := range r.processed {
+		if processed.processedAt.Before(cutoff) {
+			delete(r.processed, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// ApplyInterestWithCredits credits floor(balance * rate) minor units to every active, unfrozen account
+// with a positive balance and returns the per-account credits. Credits are clamped to the maximum
+// balance, and accounts whose interest comes to zero are skipped.
+func (r *InMemoryRepository) ApplyInterestWithCredits(ctx context.Context, rate float64) ([]postgres.InterestCredit, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("interest rate must be positive, got %v", rate)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]int, 0, len(r.accounts))
+	for id := range r.accounts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	credits := make([]postgres.InterestCredit, 0)
+	for _, id := range ids {
+		account := r.accounts[id]
+		if account.status != models.AccountStatusActive || account.frozen || account.balance <= 0 {
+			continue
+		}
+
+		interest := int(math.Floor(float64(account.balance) * rate))
+		if r.maxBalance > 0 {
+			interest = min(interest, postgres.ScaleMaxBalance(r.maxBalance, account.currency)-account.balance)
+		}
+		if interest <= 0 {
+			continue
+		}
+
+		account.balance += interest
+		account.version++
+		r.recordTransaction(id, "interest", interest, account.balance, "")
+
+		credits = append(credits, postgres.InterestCredit{
+			AccountID:    id,
+			Currency:     account.currency,
+			Amount:       interest,
+			BalanceAfter: account.balance,
+		})
+	}
+
+	return credits, nil
+}
+
+// ApplyInterest credits interest to every active account and returns the number credited
+func (r *InMemoryRepository) ApplyInterest(ctx context.Context, rate float64) (int64, error) {
+	credits, err := r.ApplyInterestWithCredits(ctx, rate)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(credits)), nil
+}
+
+// GetAggregates returns the number of active accounts, the total balance and the number of
+// transactions recorded in the last hour
+func (r *InMemoryRepository) GetAggregates(ctx context.Context) (postgres.Aggregates, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aggregates := postgres.Aggregates{TotalBalance: make(map[string]int)}
+	for _, account := range r.accounts {
+		if account.deletedAt != nil {
+			continue
+		}
+		if account.status == models.AccountStatusActive {
+			aggregates.ActiveAccounts++
+		}
+		aggregates.TotalBalance[account.currency] += account.balance
+	}
+
+	since := time.Now().UTC().Add(-time.Hour)
+	for _, tx := range r.transactions {
+		if !tx.CreatedAt.Before(since) {
+			aggregates.TransactionsLastHour++
+		}
+	}
+
+	return aggregates, nil
+}
+
+// GetTransactionHistory returns the most recent transactions of an account first
+func (r *InMemoryRepository) GetTransactionHistory(ctx context.Context, accountID int, limit int) ([]map[string]interface{}, error) {
+	page, err := r.GetTransactionHistoryFiltered(ctx, accountID, postgres.HistoryFilter{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Transactions, nil
+}
+
+// GetTransactionHistoryFiltered returns a page of an account's transactions, most recent first,
+// keyed on (created_at, id) like the PostgreSQL repository
+func (r *InMemoryRepository) GetTransactionHistoryFiltered(ctx context.Context, accountID int, filter postgres.HistoryFilter) (postgres.HistoryPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := postgres.HistoryPage{Transactions: make([]map[string]interface{}, 0)}
+
+	// The log is in ID order and timestamps never go backwards, so walking it in reverse
+	// yields (created_at, id) descending
+	for i := len(r.transactions) - 1; i >= 0; i-- {
+		tx := r.transactions[i]
+		if tx.AccountID != accountID || (filter.Type != "" && tx.Type != filter.Type) {
+			continue
+		}
+		if !filter.Before.IsZero() {
+			before := tx.CreatedAt.Before(filter.Before)
+			if filter.BeforeID > 0 && tx.CreatedAt.Equal(filter.Before) {
+				before = tx.ID < filter.BeforeID
+			}
+			if !before {
+				continue
+			}
+		}
+
+		if len(page.Transactions) == filter.Limit {
+			last := page.Transactions[len(page.Transactions)-1]
+			page.Next = &postgres.HistoryCursor{
+				Before:   last["created_at"].(time.Time),
+				BeforeID: last["id"].(int),
+			}
+			break
+		}
+
+		entry := map[string]interface{}{
+			"id":            tx.ID,
+			"type":          tx.Type,
+			"amount":        tx.Amount,
+			"balance_after": tx.BalanceAfter,
+			"created_at":    tx.CreatedAt,
+		}
+		if tx.ReferenceID != "" {
+			entry["reference_id"] = tx.ReferenceID
+		}
+		page.Transactions = append(page.Transactions, entry)
+	}
+
+	return page, nil
+}
+
+// GetBalanceAsOf returns the balance_after of the account's latest transaction at or before ts,
+// or 0 if it had none yet
+func (r *InMemoryRepository) GetBalanceAsOf(ctx context.Context, accountID int, ts time.Time) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.transactions) - 1; i >= 0; i-- {
+		tx := r.transactions[i]
+		if tx.AccountID == accountID && !tx.CreatedAt.After(ts) {
+			return tx.BalanceAfter, nil
+		}
+	}
+	return 0, nil
+}
+
+// ListTransactions returns up to limit transactions with an ID of at least fromID, created at or
+// after since (zero means no lower bound), in ID order
+func (r *InMemoryRepository) ListTransactions(ctx context.Context, fromID int, since time.Time, limit int) ([]postgres.TransactionRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]postgres.TransactionRecord, 0)
+	for _, tx := range r.transactions {
+		if len(records) == limit {
+			break
+		}
+		if tx.ID >= fromID && !tx.CreatedAt.Before(since) {
+			records = append(records, tx)
+		}
+	}
+	return records, nil
+}
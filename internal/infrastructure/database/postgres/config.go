@@ -19,6 +19,26 @@ type Config struct {
 	ConnMaxLifetime   string
 	ConnMaxIdleTime   string
 	HealthCheckPeriod string
+
+	// Per-call deadline applied by the repository on top of the caller's context ("0" disables)
+	StatementTimeout string
+
+	// Optional read replica; when set, read-only queries are routed to it
+	ReadReplicaConnectionString string
+
+	// Idempotency record retention (empty disables the cleanup job)
+	IdempotencyRetention       string
+	IdempotencyCleanupInterval string
+
+	// How often connection pool statistics are exported to Prometheus
+	PoolMetricsInterval string
+
+	// Maximum balance a deposit or incoming transfer may produce, in hundredths of the major unit (0 = no cap)
+	MaxAccountBalance int
+
+	// Savings accounts may make at most this many withdrawals per period (0 = no limit)
+	SavingsWithdrawalLimit  int
+	SavingsWithdrawalPeriod string
 }
 
 // NewConfigFromEnv creates a database configuration from environment variables
@@ -35,6 +55,19 @@ func NewConfigFromEnv() *Config {
 		ConnMaxLifetime:   getEnv("DB_CONN_MAX_LIFETIME", "30m"),
 		ConnMaxIdleTime:   getEnv("DB_CONN_MAX_IDLE_TIME", "5m"),
 		HealthCheckPeriod: getEnv("DB_HEALTH_CHECK_PERIOD", "1m"),
+		StatementTimeout:  getEnv("DB_STATEMENT_TIMEOUT", "5s"),
+
+		ReadReplicaConnectionString: getEnv("DB_READ_REPLICA_URL", ""),
+
+		IdempotencyRetention:       getEnv("IDEMPOTENCY_RETENTION", ""),
+		IdempotencyCleanupInterval: getEnv("IDEMPOTENCY_CLEANUP_INTERVAL", "1h"),
+
+		PoolMetricsInterval: getEnv("DB_POOL_METRICS_INTERVAL", "15s"),
+
+		MaxAccountBalance: getEnvAsInt("MAX_ACCOUNT_BALANCE", 0),
+
+		SavingsWithdrawalLimit:  getEnvAsInt("SAVINGS_WITHDRAWAL_LIMIT", 6),
+		SavingsWithdrawalPeriod: getEnv("SAVINGS_WITHDRAWAL_PERIOD", "720h"),
 	}
 }
 
@@ -0,0 +1,11 @@
+-- Migration: Restore unsigned transaction amounts
+-- Version: 000003
+-- Description: Rollback migration for signed transaction amounts
+
+ALTER TABLE transactions DROP CONSTRAINT signed_amount;
+
+UPDATE transactions SET amount = ABS(amount) WHERE amount < 0;
+
+ALTER TABLE transactions ADD CONSTRAINT positive_amount CHECK (amount > 0);
+
+COMMENT ON COLUMN transactions.amount IS NULL;
@@ -0,0 +1,13 @@
+-- Migration: Store signed amounts in the transaction log
+-- Version: 000003
+-- Description: Debits (withdraw, transfer_out) are stored as negative amounts and credits
+-- (deposit, transfer_in) as positive amounts, so the log can be summed into a balance
+
+ALTER TABLE transactions DROP CONSTRAINT positive_amount;
+
+ALTER TABLE transactions ADD CONSTRAINT signed_amount CHECK (
+    (transaction_type IN ('deposit', 'transfer_in') AND amount > 0) OR
+    (transaction_type IN ('withdraw', 'transfer_out') AND amount < 0)
+);
+
+COMMENT ON COLUMN transactions.amount IS 'Signed amount: positive for credits, negative for debits';
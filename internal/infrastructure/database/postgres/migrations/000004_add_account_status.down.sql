@@ -0,0 +1,7 @@
+-- Migration: Remove account status
+-- Version: 000004
+-- Description: Rollback migration for account status
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS valid_status;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
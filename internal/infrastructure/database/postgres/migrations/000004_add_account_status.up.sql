@@ -0,0 +1,9 @@
+-- Migration: Add account status
+-- Version: 000004
+-- Description: Accounts can be closed once their balance is zero; closed accounts reject operations
+
+ALTER TABLE accounts ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'active';
+
+ALTER TABLE accounts ADD CONSTRAINT valid_status CHECK (status IN ('active', 'closed'));
+
+COMMENT ON COLUMN accounts.status IS 'Account lifecycle state: active or closed (closing requires a zero balance)';
@@ -0,0 +1,18 @@
+-- Migration: Remove interest transaction type
+-- Version: 000005
+-- Description: Rollback migration for interest transaction type
+
+DELETE FROM transactions WHERE transaction_type = 'interest';
+
+ALTER TABLE transactions DROP CONSTRAINT signed_amount;
+
+ALTER TABLE transactions ADD CONSTRAINT signed_amount CHECK (
+    (transaction_type IN ('deposit', 'transfer_in') AND amount > 0) OR
+    (transaction_type IN ('withdraw', 'transfer_out') AND amount < 0)
+);
+
+ALTER TABLE transactions DROP CONSTRAINT valid_transaction_type;
+
+ALTER TABLE transactions ADD CONSTRAINT valid_transaction_type CHECK (
+    transaction_type IN ('deposit', 'withdraw', 'transfer_in', 'transfer_out')
+);
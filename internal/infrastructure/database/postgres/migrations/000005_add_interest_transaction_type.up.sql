@@ -0,0 +1,16 @@
+-- Migration: Add interest transaction type
+-- Version: 000005
+-- Description: Scheduled interest accrual records a positive 'interest' row per credited account
+
+ALTER TABLE transactions DROP CONSTRAINT valid_transaction_type;
+
+ALTER TABLE transactions ADD CONSTRAINT valid_transaction_type CHECK (
+    transaction_type IN ('deposit', 'withdraw', 'transfer_in', 'transfer_out', 'interest')
+);
+
+ALTER TABLE transactions DROP CONSTRAINT signed_amount;
+
+ALTER TABLE transactions ADD CONSTRAINT signed_amount CHECK (
+    (transaction_type IN ('deposit', 'transfer_in', 'interest') AND amount > 0) OR
+    (transaction_type IN ('withdraw', 'transfer_out') AND amount < 0)
+);
@@ -0,0 +1,11 @@
+-- Migration: Remove per-account overdraft limit
+-- Version: 000006
+-- Description: Rollback migration for overdraft limit (fails while any account is overdrawn)
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS balance_within_overdraft;
+
+ALTER TABLE accounts ADD CONSTRAINT positive_balance CHECK (balance >= 0);
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS non_negative_overdraft_limit;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS overdraft_limit;
@@ -0,0 +1,13 @@
+-- Migration: Add per-account overdraft limit
+-- Version: 000006
+-- Description: Balances may go as low as -overdraft_limit; the limit is stored in cents
+
+ALTER TABLE accounts ADD COLUMN overdraft_limit BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE accounts ADD CONSTRAINT non_negative_overdraft_limit CHECK (overdraft_limit >= 0);
+
+ALTER TABLE accounts DROP CONSTRAINT positive_balance;
+
+ALTER TABLE accounts ADD CONSTRAINT balance_within_overdraft CHECK (balance >= -(overdraft_limit / 100.0));
+
+COMMENT ON COLUMN accounts.overdraft_limit IS 'How far below zero the balance may go, in cents (0 = no overdraft)';
@@ -0,0 +1,7 @@
+-- Migration: Remove account currency
+-- Version: 000007
+-- Description: Rollback migration for account currency
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS valid_currency;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
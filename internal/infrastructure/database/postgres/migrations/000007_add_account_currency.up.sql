@@ -0,0 +1,9 @@
+-- Migration: Add account currency
+-- Version: 000007
+-- Description: Every account holds balances in a single ISO 4217 currency; existing accounts are BRL
+
+ALTER TABLE accounts ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+ALTER TABLE accounts ADD CONSTRAINT valid_currency CHECK (currency ~ '^[A-Z]{3}$');
+
+COMMENT ON COLUMN accounts.currency IS 'ISO 4217 currency code the balance is held in';
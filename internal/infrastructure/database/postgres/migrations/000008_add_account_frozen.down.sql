@@ -0,0 +1,5 @@
+-- Migration: Remove account freeze flag
+-- Version: 000008
+-- Description: Rollback migration for account freeze flag
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS frozen;
@@ -0,0 +1,7 @@
+-- Migration: Add account freeze flag
+-- Version: 000008
+-- Description: A frozen account stays open but rejects deposits, withdrawals and transfers (e.g. fraud holds)
+
+ALTER TABLE accounts ADD COLUMN frozen BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN accounts.frozen IS 'When true, money movements on the account are rejected until it is unfrozen';
@@ -0,0 +1,5 @@
+-- Migration: Drop account_holds table
+-- Version: 000009
+-- Description: Rollback migration for account_holds table
+
+DROP TABLE IF EXISTS account_holds;
@@ -0,0 +1,21 @@
+-- Migration: Create account_holds table
+-- Version: 000009
+-- Description: Reserved funds (e.g. card authorizations) that reduce the available balance without debiting it
+
+CREATE TABLE account_holds (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP,
+
+    CONSTRAINT valid_hold_status CHECK (status IN ('active', 'released', 'captured')),
+    CONSTRAINT positive_hold_amount CHECK (amount > 0)
+);
+
+-- Available balance sums the active holds of one account
+CREATE INDEX idx_account_holds_active ON account_holds(account_id) WHERE status = 'active';
+
+COMMENT ON TABLE account_holds IS 'Funds reserved against an account; available balance = balance - sum of active holds';
+COMMENT ON COLUMN account_holds.status IS 'active until released (funds freed) or captured (funds debited)';
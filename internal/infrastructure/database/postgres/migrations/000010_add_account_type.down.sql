@@ -0,0 +1,7 @@
+-- Migration: Remove account type
+-- Version: 000010
+-- Description: Rollback migration for account type
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS valid_account_type;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS account_type;
@@ -0,0 +1,9 @@
+-- Migration: Add account type
+-- Version: 000010
+-- Description: Accounts are checking or savings; savings accounts allow a limited number of withdrawals per period
+
+ALTER TABLE accounts ADD COLUMN account_type VARCHAR(10) NOT NULL DEFAULT 'checking';
+
+ALTER TABLE accounts ADD CONSTRAINT valid_account_type CHECK (account_type IN ('checking', 'savings'));
+
+COMMENT ON COLUMN accounts.account_type IS 'checking or savings; savings withdrawals are capped per period (SAVINGS_WITHDRAWAL_LIMIT)';
@@ -0,0 +1,7 @@
+-- Migration: Remove account client request ID
+-- Version: 000011
+-- Description: Rollback migration for account client request ID
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS unique_client_request_id;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS client_request_id;
@@ -0,0 +1,10 @@
+-- Migration: Add account client request ID
+-- Version: 000011
+-- Description: Optional client-supplied ID that makes account creation idempotent; a replayed ID returns the existing account
+
+ALTER TABLE accounts ADD COLUMN client_request_id VARCHAR(128);
+
+-- NULLs are distinct, so accounts created without an ID are unaffected
+ALTER TABLE accounts ADD CONSTRAINT unique_client_request_id UNIQUE (client_request_id);
+
+COMMENT ON COLUMN accounts.client_request_id IS 'Client-supplied ID of the create request (NULL when none was sent)';
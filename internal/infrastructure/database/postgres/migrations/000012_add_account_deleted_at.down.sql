@@ -0,0 +1,5 @@
+-- Migration: Remove account soft delete
+-- Version: 000012
+-- Description: Rollback migration for account soft delete
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+-- Migration: Add account soft delete
+-- Version: 000012
+-- Description: Soft-deleted accounts are hidden from reads and operations but keep their transactions and can be restored
+
+ALTER TABLE accounts ADD COLUMN deleted_at TIMESTAMP;
+
+COMMENT ON COLUMN accounts.deleted_at IS 'When the account was soft-deleted (NULL while it is live)';
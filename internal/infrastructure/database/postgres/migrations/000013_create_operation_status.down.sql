@@ -0,0 +1,5 @@
+-- Migration: Drop operation_status table
+-- Version: 000013
+-- Description: Rollback migration for operation status tracking
+
+DROP TABLE IF EXISTS operation_status;
@@ -0,0 +1,19 @@
+-- Migration: Create operation_status table
+-- Version: 000013
+-- Description: Outcome of each asynchronous operation by operation_id, so clients can poll a deposit they were given a 202 for
+
+CREATE TABLE operation_status (
+    operation_id VARCHAR(64) PRIMARY KEY,
+    operation_type VARCHAR(20) NOT NULL,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    reason VARCHAR(50),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_operation_status CHECK (status IN ('pending', 'completed', 'failed'))
+);
+
+COMMENT ON TABLE operation_status IS 'Written as pending when an operation is published, then completed or failed by the consumer';
+COMMENT ON COLUMN operation_status.reason IS 'Why a failed operation failed (e.g. insufficient_funds); NULL otherwise';
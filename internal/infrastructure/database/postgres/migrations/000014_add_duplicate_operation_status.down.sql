@@ -0,0 +1,11 @@
+-- Migration: Remove duplicate operation status
+-- Version: 000014
+-- Description: Rollback migration for duplicate operation status
+
+UPDATE operation_status SET status = 'completed' WHERE status = 'duplicate';
+
+ALTER TABLE operation_status DROP CONSTRAINT valid_operation_status;
+
+ALTER TABLE operation_status ADD CONSTRAINT valid_operation_status CHECK (
+    status IN ('pending', 'completed', 'failed')
+);
@@ -0,0 +1,10 @@
+-- Migration: Add duplicate operation status
+-- Version: 000014
+-- Description: A deposit whose idempotency key had already been applied ends as 'duplicate'
+-- instead of 'completed', so clients can tell their retry was deduplicated
+
+ALTER TABLE operation_status DROP CONSTRAINT valid_operation_status;
+
+ALTER TABLE operation_status ADD CONSTRAINT valid_operation_status CHECK (
+    status IN ('pending', 'completed', 'failed', 'duplicate')
+);
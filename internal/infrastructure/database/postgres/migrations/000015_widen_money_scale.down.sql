@@ -0,0 +1,22 @@
+-- Migration: Narrow money columns back to two decimal places
+-- Version: 000015
+-- Description: Rollback migration for three decimal money columns. Amounts of currencies with
+-- three decimal places are rounded to two.
+
+COMMENT ON COLUMN accounts.overdraft_limit IS 'How far below zero the balance may go, in cents (0 = no overdraft)';
+COMMENT ON COLUMN accounts.balance IS NULL;
+
+ALTER TABLE accounts DROP CONSTRAINT balance_within_overdraft;
+ALTER TABLE accounts ADD CONSTRAINT balance_within_overdraft CHECK (balance >= -(overdraft_limit / 100.0));
+
+ALTER TABLE operation_status ALTER COLUMN amount TYPE DECIMAL(15,2);
+
+ALTER TABLE account_holds ALTER COLUMN amount TYPE DECIMAL(15,2);
+
+ALTER TABLE processed_operations ALTER COLUMN result_balance TYPE DECIMAL(15,2);
+ALTER TABLE processed_operations ALTER COLUMN amount TYPE DECIMAL(15,2);
+
+ALTER TABLE transactions ALTER COLUMN balance_after TYPE DECIMAL(15,2);
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(15,2);
+
+ALTER TABLE accounts ALTER COLUMN balance TYPE DECIMAL(15,2);
@@ -0,0 +1,26 @@
+-- Migration: Widen money columns to three decimal places
+-- Version: 000015
+-- Description: Amounts are stored in the major unit of the account's currency, and some
+-- currencies (e.g. KWD) have three decimal places. The precision grows with the scale so
+-- the largest storable amount is unchanged. The overdraft limit is kept in minor units of the
+-- account currency, so the balance check divides by that currency's scale instead of by 100.
+
+ALTER TABLE accounts ALTER COLUMN balance TYPE DECIMAL(16,3);
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(16,3);
+ALTER TABLE transactions ALTER COLUMN balance_after TYPE DECIMAL(16,3);
+
+ALTER TABLE processed_operations ALTER COLUMN amount TYPE DECIMAL(16,3);
+ALTER TABLE processed_operations ALTER COLUMN result_balance TYPE DECIMAL(16,3);
+
+ALTER TABLE account_holds ALTER COLUMN amount TYPE DECIMAL(16,3);
+
+ALTER TABLE operation_status ALTER COLUMN amount TYPE DECIMAL(16,3);
+
+ALTER TABLE accounts DROP CONSTRAINT balance_within_overdraft;
+ALTER TABLE accounts ADD CONSTRAINT balance_within_overdraft CHECK (
+    balance >= -(overdraft_limit / CASE currency WHEN 'JPY' THEN 1.0 WHEN 'KWD' THEN 1000.0 ELSE 100.0 END)
+);
+
+COMMENT ON COLUMN accounts.balance IS 'Balance in the major unit of the account currency (e.g. 10.500 KWD, 1000 JPY)';
+COMMENT ON COLUMN accounts.overdraft_limit IS 'How far below zero the balance may go, in minor units of the account currency (0 = no overdraft)';
@@ -2,14 +2,20 @@ package postgres
 
 import (
 	"bank-api/internal/domain/models"
+	"bank-api/internal/pkg/keylock"
+	"bank-api/internal/pkg/money"
 	"context"
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,22 +31,128 @@ var (
 
 	// ErrAccountNotFound indicates that an account with the given ID doesn't exist.
 	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrAccountClosed indicates that the account has been closed and can't be operated on.
+	ErrAccountClosed = errors.New("account closed")
+
+	// ErrVersionConflict indicates that the account was modified since it was read
+	// (optimistic locking). The caller should re-read the account and retry.
+	ErrVersionConflict = errors.New("account version conflict")
+
+	// ErrAccountHasBalance indicates that an account can't be closed while it still holds funds.
+	ErrAccountHasBalance = errors.New("account has non-zero balance")
+
+	// ErrOverdraftInUse indicates that a new overdraft limit is smaller than the amount
+	// the account is currently overdrawn by.
+	ErrOverdraftInUse = errors.New("overdraft limit below current overdrawn balance")
+
+	// ErrBalanceLimitExceeded indicates that a deposit or incoming transfer would take the account
+	// above the configured maximum balance.
+	ErrBalanceLimitExceeded = errors.New("balance limit exceeded")
+
+	// ErrCurrencyMismatch indicates that a transfer's accounts hold different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrAccountFrozen indicates that the account is on hold and money can't move in or out.
+	ErrAccountFrozen = errors.New("account frozen")
+
+	// ErrHoldNotFound indicates that a hold with the given ID doesn't exist.
+	ErrHoldNotFound = errors.New("hold not found")
+
+	// ErrHoldNotActive indicates that the hold was already released or captured.
+	ErrHoldNotActive = errors.New("hold not active")
+
+	// ErrOperationNotFound indicates that no operation has been recorded with the given ID.
+	ErrOperationNotFound = errors.New("operation not found")
+
+	// ErrWithdrawalLimitExceeded indicates that a savings account has used up its withdrawals
+	// for the current period.
+	ErrWithdrawalLimitExceeded = errors.New("withdrawal limit exceeded")
 )
 
+// accountLockShards is the number of account-level mutexes shared by all accounts
+const accountLockShards = 1024
+
 // PostgresRepository implements the Repository interface using PostgreSQL
 type PostgresRepository struct {
-	pool *pgxpool.Pool
-	mu   sync.RWMutex // Protects account mutex map
-	// Account-level mutexes for concurrency control (same as in-memory)
-	accountMutexes map[int]*sync.Mutex
+	pool     *pgxpool.Pool
+	readPool *pgxpool.Pool // Read replica pool; same as pool when no replica is configured
+	// Account-level locks for concurrency control, striped so memory stays bounded however
+	// many accounts are touched
+	accountLocks *keylock.Striped
+	// Upper bound applied to each repository call on top of the caller's context (0 = none)
+	statementTimeout time.Duration
+	// Credits may not take a balance above this many hundredths of the major unit (0 = no cap)
+	maxBalance int
+	// Savings accounts may make this many withdrawals per savingsWithdrawalPeriod (0 = no limit)
+	savingsWithdrawalLimit  int
+	savingsWithdrawalPeriod time.Duration
+}
+
+// ScaleMaxBalance converts a balance cap in hundredths of the major unit to minor units of
+// currency, so every currency is capped at the same face value
+func ScaleMaxBalance(maxBalance int, currency string) int {
+	return maxBalance * money.Factor(currency) / 100
+}
+
+// exceedsMaxBalance reports whether balance, in minor units of currency, is above the cap
+func (r *PostgresRepository) exceedsMaxBalance(balance int, currency string) bool {
+	return r.maxBalance > 0 && balance > ScaleMaxBalance(r.maxBalance, currency)
 }
 
 // NewPostgresRepository creates a new PostgreSQL repository with connection pool
 func NewPostgresRepository(cfg *Config) (*PostgresRepository, error) {
+	pool, err := newPool(cfg, cfg.ConnectionString())
+	if err != nil {
+		return nil, err
+	}
+
+	readPool := pool
+	if cfg.ReadReplicaConnectionString != "" {
+		readPool, err = newPool(cfg, cfg.ReadReplicaConnectionString)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		log.Println("PostgreSQL read replica configured, read queries will use the replica pool")
+	}
+
+	statementTimeout, err := time.ParseDuration(cfg.StatementTimeout)
+	if err != nil || statementTimeout < 0 {
+		statementTimeout = 0
+	}
+
+	savingsWithdrawalPeriod, err := time.ParseDuration(cfg.SavingsWithdrawalPeriod)
+	if err != nil || savingsWithdrawalPeriod < 0 {
+		savingsWithdrawalPeriod = 0
+	}
+
+	return &PostgresRepository{
+		pool:                    pool,
+		readPool:                readPool,
+		statementTimeout:        statementTimeout,
+		maxBalance:              cfg.MaxAccountBalance,
+		savingsWithdrawalLimit:  cfg.SavingsWithdrawalLimit,
+		savingsWithdrawalPeriod: savingsWithdrawalPeriod,
+		accountLocks:            keylock.NewStriped(accountLockShards),
+	}, nil
+}
+
+// withStatementTimeout bounds ctx by the configured statement timeout, so a slow query or a
+// long lock wait gives up even when the caller's context has no deadline
+func (r *PostgresRepository) withStatementTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.statementTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.statementTimeout)
+}
+
+// newPool creates a connection pool for the given connection string using the pool settings from cfg
+func newPool(cfg *Config, connString string) (*pgxpool.Pool, error) {
 	ctx := context.Background()
 
 	// Parse connection string and create pool config
-	poolConfig, err := pgxpool.ParseConfig(cfg.ConnectionString())
+	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
@@ -75,14 +187,37 @@ func NewPostgresRepository(cfg *Config) (*PostgresRepository, error) {
 	log.Printf("PostgreSQL connection pool created successfully (max: %d, min: %d)",
 		poolConfig.MaxConns, poolConfig.MinConns)
 
-	return &PostgresRepository{
-		pool:           pool,
-		accountMutexes: make(map[int]*sync.Mutex),
-	}, nil
+	return pool, nil
+}
+
+// PoolStats returns connection pool statistics keyed by pool name ("primary", and "replica"
+// when a read replica is configured)
+func (r *PostgresRepository) PoolStats() map[string]*pgxpool.Stat {
+	stats := map[string]*pgxpool.Stat{"primary": r.pool.Stat()}
+	if r.readPool != r.pool {
+		stats["replica"] = r.readPool.Stat()
+	}
+	return stats
+}
+
+// Ping checks connectivity to the primary and, when configured, the read replica
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	if err := r.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("primary: %w", err)
+	}
+	if r.readPool != r.pool {
+		if err := r.readPool.Ping(ctx); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
 }
 
-// Close closes the database connection pool
+// Close closes the database connection pools
 func (r *PostgresRepository) Close() {
+	if r.readPool != nil && r.readPool != r.pool {
+		r.readPool.Close()
+	}
 	if r.pool != nil {
 		r.pool.Close()
 		log.Println("PostgreSQL connection pool closed")
@@ -90,60 +225,195 @@ func (r *PostgresRepository) Close() {
 }
 
 // getAccountMutex returns the mutex for a specific account ID
-// This maintains the same concurrency control pattern as in-memory implementation
+// Accounts whose IDs are equal modulo accountLockShards share a mutex
 func (r *PostgresRepository) getAccountMutex(accountID int) *sync.Mutex {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if _, exists := r.accountMutexes[accountID]; !exists {
-		r.accountMutexes[accountID] = &sync.Mutex{}
-	}
-	return r.accountMutexes[accountID]
+	return r.accountLocks.For(accountID)
 }
 
-// CreateAccount creates a new account with the given owner
+// CreateAccount creates a new account with the given owner in the default currency
 // Returns the ID of the newly created account
-func (r *PostgresRepository) CreateAccount(owner string) int {
-	ctx := context.Background()
+func (r *PostgresRepository) CreateAccount(ctx context.Context, owner string) int {
+	return r.CreateAccountWithCurrency(ctx, owner, models.DefaultCurrency)
+}
+
+// CreateAccountWithCurrency creates a new checking account holding balances in the given ISO 4217 currency
+// Returns the ID of the newly created account, or 0 on failure
+func (r *PostgresRepository) CreateAccountWithCurrency(ctx context.Context, owner string, currency string) int {
+	return r.CreateAccountWithType(ctx, owner, currency, models.AccountTypeChecking)
+}
+
+// CreateAccountWithType creates a new account of the given type (checking or savings)
+// Returns the ID of the newly created account, or 0 on failure
+func (r *PostgresRepository) CreateAccountWithType(ctx context.Context, owner string, currency string, accountType string) int {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	query := `
-		INSERT INTO accounts (owner, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO accounts (owner, balance, currency, account_type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
 	`
 
 	var accountID int
 	now := time.Now().UTC() // Use UTC to avoid timezone issues with TIMESTAMP (without timezone)
 
-	err := r.pool.QueryRow(ctx, query, owner, 0, now, now).Scan(&accountID)
+	err := r.pool.QueryRow(ctx, query, owner, 0, currency, accountType, now, now).Scan(&accountID)
 	if err != nil {
 		log.Printf("Failed to create account for owner %s: %v", owner, err)
 		return 0
 	}
 
-	log.Printf("Account created: ID=%d, Owner=%s", accountID, owner)
+	log.Printf("Account created: ID=%d, Owner=%s, Currency=%s, Type=%s", accountID, owner, currency, accountType)
 	return accountID
 }
 
+// CreateAccountIdempotent creates an account tagged with the client's request ID. Replaying the
+// same clientRequestID returns the account created the first time (as stored, even if the owner,
+// currency or type differ) and created=false instead of inserting a second row.
+func (r *PostgresRepository) CreateAccountIdempotent(ctx context.Context, owner string, currency string, accountType string, clientRequestID string) (*models.Account, bool, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	const columns = "id, owner, balance, created_at, status, overdraft_limit, version, currency, frozen, account_type"
+
+	insertQuery := `
+		INSERT INTO accounts (owner, balance, currency, account_type, client_request_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (client_request_id) DO NOTHING
+		RETURNING ` + columns
+
+	// Conflict: the request was already applied. ON CONFLICT waits for a concurrent insert
+	// of the same ID to commit, so the existing row is visible to this follow-up read.
+	selectQuery := `SELECT ` + columns + ` FROM accounts WHERE client_request_id = $1`
+
+	var account models.Account
+	var balanceDecimal float64
+	scan := func(row pgx.Row) error {
+		return row.Scan(
+			&account.Id,
+			&account.Owner,
+			&balanceDecimal,
+			&account.CreatedAt,
+			&account.Status,
+			&account.OverdraftLimit,
+			&account.Version,
+			&account.Currency,
+			&account.Frozen,
+			&account.AccountType,
+		)
+	}
+
+	now := time.Now().UTC() // Use UTC to avoid timezone issues with TIMESTAMP (without timezone)
+
+	created := true
+	err := scan(r.pool.QueryRow(ctx, insertQuery, owner, 0, currency, accountType, clientRequestID, now, now))
+	if errors.Is(err, pgx.ErrNoRows) {
+		created = false
+		err = scan(r.pool.QueryRow(ctx, selectQuery, clientRequestID))
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	// Convert balance from DECIMAL to minor units (int)
+	account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
+
+	if created {
+		log.Printf("Account created: ID=%d, Owner=%s, Currency=%s, Type=%s, ClientRequestID=%s",
+			account.Id, owner, currency, accountType, clientRequestID)
+	} else {
+		log.Printf("Account creation replayed: ID=%d, ClientRequestID=%s", account.Id, clientRequestID)
+	}
+	return &account, created, nil
+}
+
+// CreateAccountsBulk opens count checking accounts named "<ownerPrefix>-1".."<ownerPrefix>-N" in
+// the default currency, crediting each with initialBalance (cents) when it is positive. Everything
+// runs in one transaction, so either all accounts are created or none are.
+// Returns the new account IDs in ascending order.
+func (r *PostgresRepository) CreateAccountsBulk(ctx context.Context, ownerPrefix string, count int, initialBalance int) ([]int, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	if r.exceedsMaxBalance(initialBalance, models.DefaultCurrency) {
+		return nil, ErrBalanceLimitExceeded
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insertQuery := `
+		INSERT INTO accounts (owner, balance, currency, account_type, created_at, updated_at)
+		SELECT $1 || '-' || g, $2, $3, $4, $5, $5
+		FROM generate_series(1, $6::int) AS g
+		ORDER BY g
+		RETURNING id
+	`
+
+	balanceDecimal := money.ToDecimal(initialBalance, models.DefaultCurrency)
+	now := time.Now().UTC() // Use UTC to avoid timezone issues with TIMESTAMP (without timezone)
+
+	rows, err := tx.Query(ctx, insertQuery, ownerPrefix, balanceDecimal, models.DefaultCurrency, models.AccountTypeChecking, now, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accounts: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accounts: %w", err)
+	}
+	sort.Ints(ids)
+
+	// The opening credit goes through the transaction log like any deposit, so history
+	// and point-in-time balances agree with the stored balance
+	if initialBalance > 0 {
+		creditQuery := `
+			INSERT INTO transactions (account_id, transaction_type, amount, balance_after)
+			SELECT id, 'deposit', $2, $2
+			FROM unnest($1::int[]) AS id
+		`
+		if _, err = tx.Exec(ctx, creditQuery, ids, balanceDecimal); err != nil {
+			return nil, fmt.Errorf("failed to record opening credits: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Accounts created in bulk: Count=%d, OwnerPrefix=%s, InitialBalance=%.2f", len(ids), ownerPrefix, balanceDecimal)
+	return ids, nil
+}
+
 // GetAccount retrieves an account by ID
 // Returns the account and true if found, nil and false otherwise
-func (r *PostgresRepository) GetAccount(id int) (*models.Account, bool) {
-	ctx := context.Background()
+// Served from the read replica when one is configured
+func (r *PostgresRepository) GetAccount(ctx context.Context, id int) (*models.Account, bool) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	query := `
-		SELECT id, owner, balance, created_at
+		SELECT id, owner, balance, created_at, status, overdraft_limit, version, currency, frozen, account_type
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var account models.Account
 	var balanceDecimal float64
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.readPool.QueryRow(ctx, query, id).Scan(
 		&account.Id,
 		&account.Owner,
 		&balanceDecimal,
 		&account.CreatedAt,
+		&account.Status,
+		&account.OverdraftLimit,
+		&account.Version,
+		&account.Currency,
+		&account.Frozen,
+		&account.AccountType,
 	)
 
 	if err != nil {
@@ -151,16 +421,75 @@ func (r *PostgresRepository) GetAccount(id int) (*models.Account, bool) {
 		return nil, false
 	}
 
-	// Convert balance from DECIMAL(15,2) to cents (int)
-	account.Balance = int(balanceDecimal * 100)
+	// Convert balance from DECIMAL to minor units (int)
+	account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
 
 	return &account, true
 }
 
+// ListAccounts returns a page of accounts ordered by ID, plus the total number of accounts
+// Served from the read replica when one is configured
+func (r *PostgresRepository) ListAccounts(ctx context.Context, limit int, offset int) ([]*models.Account, int, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	var total int
+	if err := r.readPool.QueryRow(ctx, "SELECT COUNT(*) FROM accounts WHERE deleted_at IS NULL").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count accounts: %w", err)
+	}
+
+	query := `
+		SELECT id, owner, balance, created_at, status, overdraft_limit, version, currency, frozen, account_type
+		FROM accounts
+		WHERE deleted_at IS NULL
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.readPool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make([]*models.Account, 0, limit)
+	for rows.Next() {
+		var account models.Account
+		var balanceDecimal float64
+
+		err := rows.Scan(
+			&account.Id,
+			&account.Owner,
+			&balanceDecimal,
+			&account.CreatedAt,
+			&account.Status,
+			&account.OverdraftLimit,
+			&account.Version,
+			&account.Currency,
+			&account.Frozen,
+			&account.AccountType,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan account: %w", err)
+		}
+
+		// Convert balance from DECIMAL to minor units (int)
+		account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
+		accounts = append(accounts, &account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, total, nil
+}
+
 // UpdateAccount updates an existing account's balance
 // This is called after in-memory modifications to persist changes
-func (r *PostgresRepository) UpdateAccount(acc *models.Account) {
-	ctx := context.Background()
+func (r *PostgresRepository) UpdateAccount(ctx context.Context, acc *models.Account) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	// Get account-specific mutex to prevent concurrent updates
 	mu := r.getAccountMutex(acc.Id)
@@ -170,11 +499,11 @@ func (r *PostgresRepository) UpdateAccount(acc *models.Account) {
 	query := `
 		UPDATE accounts
 		SET balance = $1, version = version + 1
-		WHERE id = $2
+		WHERE id = $2 AND deleted_at IS NULL
 	`
 
-	// Convert balance from cents (int) to DECIMAL(15,2)
-	balanceDecimal := float64(acc.Balance) / 100.0
+	// Convert balance from minor units (int) to DECIMAL
+	balanceDecimal := money.ToDecimal(acc.Balance, acc.Currency)
 
 	_, err := r.pool.Exec(ctx, query, balanceDecimal, acc.Id)
 	if err != nil {
@@ -185,20 +514,57 @@ func (r *PostgresRepository) UpdateAccount(acc *models.Account) {
 	log.Printf("Account updated: ID=%d, Balance=%.2f", acc.Id, balanceDecimal)
 }
 
+// UpdateAccountVersioned persists the account balance only if the stored version still
+// matches expectedVersion (optimistic locking). Returns ErrVersionConflict when another
+// writer got there first, instead of silently overwriting their update.
+func (r *PostgresRepository) UpdateAccountVersioned(ctx context.Context, acc *models.Account, expectedVersion int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2 AND version = $3 AND deleted_at IS NULL
+	`
+
+	// Convert balance from minor units (int) to DECIMAL
+	balanceDecimal := money.ToDecimal(acc.Balance, acc.Currency)
+
+	tag, err := r.pool.Exec(ctx, query, balanceDecimal, acc.Id, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		// Distinguish a stale version from a missing account
+		var exists bool
+		if err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE id = $1 AND deleted_at IS NULL)", acc.Id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check account: %w", err)
+		}
+		if !exists {
+			return ErrAccountNotFound
+		}
+		return ErrVersionConflict
+	}
+
+	acc.Version = expectedVersion + 1
+	log.Printf("Account updated (versioned): ID=%d, Balance=%.2f, Version=%d", acc.Id, balanceDecimal, acc.Version)
+
+	return nil
+}
+
 // Reset clears all data from the database
 // WARNING: This is only for testing purposes
-func (r *PostgresRepository) Reset() {
-	ctx := context.Background()
+func (r *PostgresRepository) Reset(ctx context.Context) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
-	// Clear account mutexes
-	r.mu.Lock()
-	r.accountMutexes = make(map[int]*sync.Mutex)
-	r.mu.Unlock()
-
-	// Truncate tables in correct order (transactions and processed_operations first due to foreign keys)
+	// Truncate tables in correct order (transactions, processed_operations, account_holds and operation_status first due to foreign keys)
 	queries := []string{
 		"TRUNCATE TABLE transactions RESTART IDENTITY CASCADE",
 		"TRUNCATE TABLE processed_operations RESTART IDENTITY CASCADE",
+		"TRUNCATE TABLE account_holds RESTART IDENTITY CASCADE",
+		"TRUNCATE TABLE operation_status RESTART IDENTITY CASCADE",
 		"TRUNCATE TABLE accounts RESTART IDENTITY CASCADE",
 	}
 
@@ -214,20 +580,62 @@ func (r *PostgresRepository) Reset() {
 }
 
 // CreateTransaction records a transaction in the database
-// This is called after successful account operations for audit trail
-func (r *PostgresRepository) CreateTransaction(accountID int, txType string, amount int, balanceAfter int, referenceID *string) error {
-	ctx := context.Background()
+// The amount is given as a positive value; its sign is derived from the transaction type
+func (r *PostgresRepository) CreateTransaction(ctx context.Context, accountID int, txType string, amount int, balanceAfter int, referenceID *string) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	currency, err := accountCurrency(ctx, r.pool, accountID)
+	if err != nil {
+		return err
+	}
+
+	return insertTransaction(ctx, r.pool, accountID, txType, amount, balanceAfter, currency, referenceID)
+}
+
+// execer is satisfied by both the pool and an open transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// querier is satisfied by both the pool and an open transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// accountCurrency returns the currency of an account, which decides how its amounts are
+// converted between minor units and DECIMAL. Soft-deleted accounts are included, since
+// their transactions and holds still need converting.
+func accountCurrency(ctx context.Context, db querier, accountID int) (string, error) {
+	var currency string
+	err := db.QueryRow(ctx, "SELECT currency FROM accounts WHERE id = $1", accountID).Scan(&currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrAccountNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get account currency: %w", err)
+	}
+	return currency, nil
+}
 
+// insertTransaction writes a transaction log row. Called with an open pgx.Tx by the atomic
+// operations so the audit trail commits or rolls back together with the balance change.
+// Debits (withdraw, transfer_out) are stored as negative amounts.
+func insertTransaction(ctx context.Context, db execer, accountID int, txType string, amount int, balanceAfter int, currency string, referenceID *string) error {
 	query := `
 		INSERT INTO transactions (account_id, transaction_type, amount, balance_after, reference_id)
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	// Convert amounts from cents to DECIMAL(15,2)
-	amountDecimal := float64(amount) / 100.0
-	balanceAfterDecimal := float64(balanceAfter) / 100.0
+	if txType == "withdraw" || txType == "transfer_out" {
+		amount = -amount
+	}
+
+	// Convert amounts from minor units to DECIMAL
+	amountDecimal := money.ToDecimal(amount, currency)
+	balanceAfterDecimal := money.ToDecimal(balanceAfter, currency)
 
-	_, err := r.pool.Exec(ctx, query, accountID, txType, amountDecimal, balanceAfterDecimal, referenceID)
+	_, err := db.Exec(ctx, query, accountID, txType, amountDecimal, balanceAfterDecimal, referenceID)
 	if err != nil {
 		return fmt.Errorf("failed to create transaction: %w", err)
 	}
@@ -236,25 +644,81 @@ func (r *PostgresRepository) CreateTransaction(accountID int, txType string, amo
 }
 
 // GetTransactionHistory retrieves the transaction history for an account
-// Returns the most recent transactions first
-func (r *PostgresRepository) GetTransactionHistory(accountID int, limit int) ([]map[string]interface{}, error) {
-	ctx := context.Background()
+// Returns the most recent transactions first, with signed amounts in minor units
+// Served from the read replica when one is configured
+func (r *PostgresRepository) GetTransactionHistory(ctx context.Context, accountID int, limit int) ([]map[string]interface{}, error) {
+	page, err := r.GetTransactionHistoryFiltered(ctx, accountID, HistoryFilter{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Transactions, nil
+}
+
+// HistoryFilter selects a page of an account's transaction history
+type HistoryFilter struct {
+	Before   time.Time // only transactions older than this; zero means from the most recent
+	BeforeID int       // breaks ties between transactions sharing Before (0 = no tie-break)
+	Type     string    // transaction_type to match; empty means all types
+	Limit    int
+}
+
+// HistoryCursor points just past the last transaction of a page
+type HistoryCursor struct {
+	Before   time.Time `json:"before"`
+	BeforeID int       `json:"before_id"`
+}
+
+// HistoryPage is one page of transaction history; Next is nil on the last page
+type HistoryPage struct {
+	Transactions []map[string]interface{}
+	Next         *HistoryCursor
+}
+
+// GetTransactionHistoryFiltered returns a page of an account's transactions, most recent first.
+// Pages are keyed on (created_at, id) so rows written in the same database transaction are
+// never skipped or repeated. Served from the read replica when one is configured
+func (r *PostgresRepository) GetTransactionHistoryFiltered(ctx context.Context, accountID int, filter HistoryFilter) (HistoryPage, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT id, transaction_type, amount, balance_after, reference_id, created_at
 		FROM transactions
 		WHERE account_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2
 	`
+	args := []interface{}{accountID}
+
+	if !filter.Before.IsZero() {
+		if filter.BeforeID > 0 {
+			args = append(args, filter.Before, filter.BeforeID)
+			query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(args)-1, len(args))
+		} else {
+			args = append(args, filter.Before)
+			query += fmt.Sprintf(" AND created_at < $%d", len(args))
+		}
+	}
+
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		query += fmt.Sprintf(" AND transaction_type = $%d", len(args))
+	}
+
+	// Fetch one extra row to know whether another page follows
+	args = append(args, filter.Limit+1)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
 
-	rows, err := r.pool.Query(ctx, query, accountID, limit)
+	currency, err := accountCurrency(ctx, r.readPool, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query transactions: %w", err)
+		return HistoryPage{}, err
+	}
+
+	rows, err := r.readPool.Query(ctx, query, args...)
+	if err != nil {
+		return HistoryPage{}, fmt.Errorf("failed to query transactions: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []map[string]interface{}
+	page := HistoryPage{Transactions: make([]map[string]interface{}, 0)}
 
 	for rows.Next() {
 		var (
@@ -268,14 +732,23 @@ func (r *PostgresRepository) GetTransactionHistory(accountID int, limit int) ([]
 
 		err := rows.Scan(&id, &txType, &amount, &balanceAfter, &referenceID, &createdAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+			return HistoryPage{}, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+
+		if len(page.Transactions) == filter.Limit {
+			last := page.Transactions[len(page.Transactions)-1]
+			page.Next = &HistoryCursor{
+				Before:   last["created_at"].(time.Time),
+				BeforeID: last["id"].(int),
+			}
+			break
 		}
 
 		tx := map[string]interface{}{
 			"id":            id,
 			"type":          txType,
-			"amount":        amount,
-			"balance_after": balanceAfter,
+			"amount":        money.FromDecimal(amount, currency),       // Convert to minor units
+			"balance_after": money.FromDecimal(balanceAfter, currency), // Convert to minor units
 			"created_at":    createdAt,
 		}
 
@@ -283,114 +756,1043 @@ func (r *PostgresRepository) GetTransactionHistory(accountID int, limit int) ([]
 			tx["reference_id"] = *referenceID
 		}
 
-		transactions = append(transactions, tx)
+		page.Transactions = append(page.Transactions, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return HistoryPage{}, fmt.Errorf("failed to read transactions: %w", err)
 	}
 
-	return transactions, nil
+	return page, nil
 }
 
-// AtomicWithdraw performs an atomic withdrawal operation using SELECT FOR UPDATE
-// This ensures no lost updates in concurrent scenarios
-func (r *PostgresRepository) AtomicWithdraw(accountID int, amount int) (*models.Account, error) {
-	ctx := context.Background()
+// GetBalanceAsOf returns the account's balance in minor units at ts: the balance_after of its latest
+// transaction at or before ts, or 0 if it had none yet. Balances changed without a transaction
+// row (e.g. direct UpdateAccount calls) are not reflected.
+// Served from the read replica when one is configured
+func (r *PostgresRepository) GetBalanceAsOf(ctx context.Context, accountID int, ts time.Time) (int, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT t.balance_after, a.currency
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.account_id = $1 AND t.created_at <= $2
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT 1
+	`
+
+	// created_at is a TIMESTAMP (without timezone) holding UTC wall-clock time
+	var balanceDecimal float64
+	var currency string
+	err := r.readPool.QueryRow(ctx, query, accountID, ts.UTC()).Scan(&balanceDecimal, &currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query balance as of %s: %w", ts.Format(time.RFC3339), err)
+	}
+
+	return money.FromDecimal(balanceDecimal, currency), nil
+}
+
+// TransactionRecord is one row of the transaction log across all accounts
+type TransactionRecord struct {
+	ID           int
+	AccountID    int
+	Type         string
+	Amount       int // in minor units of the account's currency, negative for debits
+	BalanceAfter int // in minor units of the account's currency
+	ReferenceID  string
+	CreatedAt    time.Time
+}
+
+// ListTransactions returns up to limit transactions with an ID of at least fromID, created at or
+// after since (zero means no lower bound), in ID order. Callers page by passing the last ID + 1.
+// Served from the read replica when one is configured
+func (r *PostgresRepository) ListTransactions(ctx context.Context, fromID int, since time.Time, limit int) ([]TransactionRecord, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT t.id, t.account_id, t.transaction_type, t.amount, t.balance_after, COALESCE(t.reference_id, ''), t.created_at, a.currency
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.id >= $1 AND t.created_at >= $2
+		ORDER BY t.id
+		LIMIT $3
+	`
+
+	rows, err := r.readPool.Query(ctx, query, fromID, since.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]TransactionRecord, 0)
+	for rows.Next() {
+		var record TransactionRecord
+		var amount, balanceAfter float64
+		var currency string
+
+		err := rows.Scan(&record.ID, &record.AccountID, &record.Type, &amount, &balanceAfter, &record.ReferenceID, &record.CreatedAt, &currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+
+		// Convert amounts from DECIMAL to minor units
+		record.Amount = money.FromDecimal(amount, currency)
+		record.BalanceAfter = money.FromDecimal(balanceAfter, currency)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+
+	log.Printf("Transactions listed: FromID=%d, Since=%s, Count=%d", fromID, since.UTC().Format(time.RFC3339), len(records))
+	return records, nil
+}
+
+// CloseAccount marks an account as closed. Only accounts with a zero balance can be closed.
+// Returns ErrAccountNotFound, ErrAccountClosed if already closed, or ErrAccountHasBalance
+func (r *PostgresRepository) CloseAccount(ctx context.Context, id int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	// Start transaction
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Lock the row with SELECT FOR UPDATE
+	// Lock the row so a concurrent deposit can't slip in between the check and the update
 	query := `
-		SELECT id, owner, balance, created_at
+		SELECT balance, status
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		FOR UPDATE
 	`
 
-	var account models.Account
 	var balanceDecimal float64
+	var status string
 
-	err = tx.QueryRow(ctx, query, accountID).Scan(
-		&account.Id,
-		&account.Owner,
-		&balanceDecimal,
-		&account.CreatedAt,
-	)
-
+	err = tx.QueryRow(ctx, query, id).Scan(&balanceDecimal, &status)
 	if err != nil {
-		return nil, fmt.Errorf("account not found: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		return fmt.Errorf("failed to lock account: %w", err)
 	}
 
-	// Convert balance from DECIMAL to cents
-	account.Balance = int(balanceDecimal * 100)
-
-	// Check if sufficient balance
-	if account.Balance < amount {
-		return nil, fmt.Errorf("insufficient balance")
+	if status == models.AccountStatusClosed {
+		return ErrAccountClosed
 	}
 
-	// Update balance
-	newBalance := account.Balance - amount
-	newBalanceDecimal := float64(newBalance) / 100.0
+	if balanceDecimal != 0 {
+		return ErrAccountHasBalance
+	}
 
 	updateQuery := `
 		UPDATE accounts
-		SET balance = $1, version = version + 1
+		SET status = $1, version = version + 1
 		WHERE id = $2
 	`
 
-	_, err = tx.Exec(ctx, updateQuery, newBalanceDecimal, accountID)
+	_, err = tx.Exec(ctx, updateQuery, models.AccountStatusClosed, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update balance: %w", err)
+		return fmt.Errorf("failed to close account: %w", err)
 	}
 
-	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	account.Balance = newBalance
-	log.Printf("Atomic withdraw: ID=%d, Amount=%.2f, NewBalance=%.2f", accountID, float64(amount)/100, newBalanceDecimal)
-
-	return &account, nil
+	log.Printf("Account closed: ID=%d", id)
+	return nil
 }
 
-// AtomicTransfer performs an atomic transfer operation using SELECT FOR UPDATE
-// This ensures no lost updates and no deadlocks (by ordering locks)
-func (r *PostgresRepository) AtomicTransfer(fromID int, toID int, amount int) (*models.Account, *models.Account, error) {
-	ctx := context.Background()
+// SoftDeleteAccount hides an account from reads and operations without removing it, so its
+// transactions stay intact; RestoreAccount brings it back. Only accounts with a zero balance
+// can be deleted. Returns ErrAccountNotFound (also for an already deleted account) or
+// ErrAccountHasBalance
+func (r *PostgresRepository) SoftDeleteAccount(ctx context.Context, id int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	// Start transaction
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Lock accounts in order (lower ID first) to prevent deadlocks
-	firstID, secondID := fromID, toID
+	// Lock the row so a concurrent deposit can't slip in between the check and the update
+	query := `
+		SELECT balance
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var balanceDecimal float64
+	err = tx.QueryRow(ctx, query, id).Scan(&balanceDecimal)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if balanceDecimal != 0 {
+		return ErrAccountHasBalance
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET deleted_at = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	_, err = tx.Exec(ctx, updateQuery, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Account soft-deleted: ID=%d", id)
+	return nil
+}
+
+// RestoreAccount undoes SoftDeleteAccount. Restoring an account that isn't deleted is a no-op.
+// Returns ErrAccountNotFound if no account has the ID
+func (r *PostgresRepository) RestoreAccount(ctx context.Context, id int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE accounts
+		SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore account: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		// Nothing restored - tell a missing account apart from a live one
+		var exists bool
+		if err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE id = $1)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check account existence: %w", err)
+		}
+		if !exists {
+			return ErrAccountNotFound
+		}
+		return nil
+	}
+
+	log.Printf("Account restored: ID=%d", id)
+	return nil
+}
+
+// SetOverdraftLimit sets how far below zero (in minor units of the account currency) the
+// account balance may go.
+// Returns ErrAccountNotFound, ErrAccountClosed, or ErrOverdraftInUse if the account is
+// already overdrawn by more than the new limit
+func (r *PostgresRepository) SetOverdraftLimit(ctx context.Context, id int, limitCents int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	if limitCents < 0 {
+		return fmt.Errorf("overdraft limit must not be negative, got %d", limitCents)
+	}
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the row so a concurrent withdrawal can't dig deeper between the check and the update
+	query := `
+		SELECT balance, status, currency
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var balanceDecimal float64
+	var status, currency string
+
+	err = tx.QueryRow(ctx, query, id).Scan(&balanceDecimal, &status, &currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if status == models.AccountStatusClosed {
+		return ErrAccountClosed
+	}
+
+	if money.FromDecimal(balanceDecimal, currency) < -limitCents {
+		return ErrOverdraftInUse
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET overdraft_limit = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	_, err = tx.Exec(ctx, updateQuery, limitCents, id)
+	if err != nil {
+		return fmt.Errorf("failed to set overdraft limit: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Overdraft limit set: ID=%d, Limit=%.2f", id, money.ToDecimal(limitCents, currency))
+	return nil
+}
+
+// SetFrozen freezes or unfreezes an account. While frozen, deposits, withdrawals and
+// transfers are rejected with ErrAccountFrozen; the account stays open.
+// Returns ErrAccountNotFound, or ErrAccountClosed if the account has been closed
+func (r *PostgresRepository) SetFrozen(ctx context.Context, id int, frozen bool) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE accounts
+		SET frozen = $1, version = version + 1
+		WHERE id = $2 AND status = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, frozen, id, models.AccountStatusActive)
+	if err != nil {
+		return fmt.Errorf("failed to update frozen flag: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		// Nothing updated - tell a missing account apart from a closed one
+		var exists bool
+		if err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM accounts WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check account existence: %w", err)
+		}
+		if !exists {
+			return ErrAccountNotFound
+		}
+		return ErrAccountClosed
+	}
+
+	log.Printf("Account frozen flag set: ID=%d, Frozen=%t", id, frozen)
+	return nil
+}
+
+// heldAmount returns the total of the account's active holds in minor units of currency.
+// Callers lock the account row first, so no hold can be placed or resolved while the result
+// is in use.
+func heldAmount(ctx context.Context, tx pgx.Tx, accountID int, currency string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM account_holds
+		WHERE account_id = $1 AND status = $2
+	`
+
+	var heldDecimal float64
+	if err := tx.QueryRow(ctx, query, accountID, models.HoldStatusActive).Scan(&heldDecimal); err != nil {
+		return 0, fmt.Errorf("failed to sum active holds: %w", err)
+	}
+
+	return money.FromDecimal(heldDecimal, currency), nil
+}
+
+// checkWithdrawalLimit returns ErrWithdrawalLimitExceeded if a savings account has already made
+// savingsWithdrawalLimit withdrawals within the trailing savingsWithdrawalPeriod.
+// Checking accounts are not limited. Must be called with the account row locked.
+func (r *PostgresRepository) checkWithdrawalLimit(ctx context.Context, tx pgx.Tx, account *models.Account) error {
+	if account.AccountType != models.AccountTypeSavings || r.savingsWithdrawalLimit <= 0 || r.savingsWithdrawalPeriod <= 0 {
+		return nil
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE account_id = $1 AND transaction_type = 'withdraw'
+		  AND created_at > NOW() - $2 * INTERVAL '1 second'
+	`
+
+	var withdrawals int
+	if err := tx.QueryRow(ctx, query, account.Id, r.savingsWithdrawalPeriod.Seconds()).Scan(&withdrawals); err != nil {
+		return fmt.Errorf("failed to count withdrawals: %w", err)
+	}
+
+	if withdrawals >= r.savingsWithdrawalLimit {
+		return ErrWithdrawalLimitExceeded
+	}
+	return nil
+}
+
+// PlaceHold reserves amount (in minor units) on the account without debiting it. The hold counts
+// against the available balance of withdrawals and transfers until it is released or captured.
+// Returns ErrAccountNotFound, ErrAccountClosed, ErrAccountFrozen, or ErrInsufficientFunds if
+// the available balance (overdraft included) doesn't cover the hold
+func (r *PostgresRepository) PlaceHold(ctx context.Context, accountID int, amount int) (int, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the row so concurrent holds and withdrawals see each other
+	query := `
+		SELECT balance, status, overdraft_limit, frozen, currency
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var balanceDecimal float64
+	var status, currency string
+	var overdraftLimit int
+	var frozen bool
+
+	err = tx.QueryRow(ctx, query, accountID).Scan(&balanceDecimal, &status, &overdraftLimit, &frozen, &currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrAccountNotFound
+		}
+		return 0, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if status == models.AccountStatusClosed {
+		return 0, ErrAccountClosed
+	}
+
+	if frozen {
+		return 0, ErrAccountFrozen
+	}
+
+	held, err := heldAmount(ctx, tx, accountID, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	if money.FromDecimal(balanceDecimal, currency)-held-amount < -overdraftLimit {
+		return 0, ErrInsufficientFunds
+	}
+
+	insertQuery := `
+		INSERT INTO account_holds (account_id, amount, status)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	var holdID int
+	err = tx.QueryRow(ctx, insertQuery, accountID, money.ToDecimal(amount, currency), models.HoldStatusActive).Scan(&holdID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Hold placed: ID=%d, AccountID=%d, Amount=%.2f", holdID, accountID, money.ToDecimal(amount, currency))
+	return holdID, nil
+}
+
+// CreatePendingOperation records an operation as pending before it is published, so its
+// status can be queried as soon as the client has the operation ID
+func (r *PostgresRepository) CreatePendingOperation(ctx context.Context, operationID string, operationType string, accountID int, amount int) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO operation_status (operation_id, operation_type, account_id, amount, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	currency, err := accountCurrency(ctx, r.pool, accountID)
+	if err != nil {
+		return err
+	}
+	amountDecimal := money.ToDecimal(amount, currency)
+
+	if _, err := r.pool.Exec(ctx, query, operationID, operationType, accountID, amountDecimal, models.OperationStatusPending); err != nil {
+		return fmt.Errorf("failed to record operation: %w", err)
+	}
+
+	log.Printf("Operation pending: ID=%s, Type=%s, AccountID=%d", operationID, operationType, accountID)
+	return nil
+}
+
+// SetOperationStatus moves a pending operation to completed, duplicate or failed (with a reason).
+// Final states are never overwritten, so a redelivered message can't change the outcome;
+// unknown or already final operations are left as they are
+func (r *PostgresRepository) SetOperationStatus(ctx context.Context, operationID string, status string, reason string) error {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE operation_status
+		SET status = $1, reason = NULLIF($2, ''), updated_at = NOW()
+		WHERE operation_id = $3 AND status = $4
+	`
+
+	result, err := r.pool.Exec(ctx, query, status, reason, operationID, models.OperationStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to update operation status: %w", err)
+	}
+
+	log.Printf("Operation status set: ID=%s, Status=%s, Reason=%s, Updated=%t", operationID, status, reason, result.RowsAffected() > 0)
+	return nil
+}
+
+// GetOperation returns the recorded status of an operation, or ErrOperationNotFound
+// Served from the read replica when one is configured
+func (r *PostgresRepository) GetOperation(ctx context.Context, operationID string) (*models.Operation, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT o.operation_id, o.operation_type, o.account_id, o.amount, o.status, COALESCE(o.reason, ''), o.created_at, o.updated_at, a.currency
+		FROM operation_status o
+		JOIN accounts a ON a.id = o.account_id
+		WHERE o.operation_id = $1
+	`
+
+	var operation models.Operation
+	var amountDecimal float64
+	var currency string
+
+	err := r.readPool.QueryRow(ctx, query, operationID).Scan(
+		&operation.ID,
+		&operation.Type,
+		&operation.AccountID,
+		&amountDecimal,
+		&operation.Status,
+		&operation.Reason,
+		&operation.CreatedAt,
+		&operation.UpdatedAt,
+		&currency,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOperationNotFound
+		}
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+
+	// Convert amount from DECIMAL to minor units
+	operation.Amount = money.FromDecimal(amountDecimal, currency)
+
+	log.Printf("Operation retrieved: ID=%s, Status=%s", operation.ID, operation.Status)
+	return &operation, nil
+}
+
+// ReleaseHold frees the funds reserved by an active hold without debiting them.
+// Returns ErrHoldNotFound, or ErrHoldNotActive if the hold was already released or captured
+func (r *PostgresRepository) ReleaseHold(ctx context.Context, holdID int) (*models.Hold, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err := lockActiveHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = resolveHold(ctx, tx, hold, models.HoldStatusReleased); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Hold released: ID=%d, AccountID=%d", hold.Id, hold.AccountID)
+	return hold, nil
+}
+
+// CaptureHold debits the amount reserved by an active hold and records it in the transaction
+// log as a withdrawal. The funds were already set aside, so no balance check is repeated.
+// Returns ErrHoldNotFound, ErrHoldNotActive, ErrAccountClosed or ErrAccountFrozen
+func (r *PostgresRepository) CaptureHold(ctx context.Context, holdID int) (*models.Hold, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err := lockActiveHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT balance, status, frozen, currency
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var balanceDecimal float64
+	var status, currency string
+	var frozen bool
+
+	if err = tx.QueryRow(ctx, query, hold.AccountID).Scan(&balanceDecimal, &status, &frozen, &currency); err != nil {
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if status == models.AccountStatusClosed {
+		return nil, ErrAccountClosed
+	}
+
+	if frozen {
+		return nil, ErrAccountFrozen
+	}
+
+	newBalance := money.FromDecimal(balanceDecimal, currency) - hold.Amount
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	if _, err = tx.Exec(ctx, updateQuery, money.ToDecimal(newBalance, currency), hold.AccountID); err != nil {
+		return nil, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	if err = insertTransaction(ctx, tx, hold.AccountID, "withdraw", hold.Amount, newBalance, currency, nil); err != nil {
+		return nil, err
+	}
+
+	if err = resolveHold(ctx, tx, hold, models.HoldStatusCaptured); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Hold captured: ID=%d, AccountID=%d, Amount=%.2f, NewBalance=%.2f",
+		hold.Id, hold.AccountID, money.ToDecimal(hold.Amount, currency), money.ToDecimal(newBalance, currency))
+	return hold, nil
+}
+
+// lockActiveHold locks a hold row for release or capture
+func lockActiveHold(ctx context.Context, tx pgx.Tx, holdID int) (*models.Hold, error) {
+	query := `
+		SELECT h.id, h.account_id, h.amount, h.status, h.created_at, a.currency
+		FROM account_holds h
+		JOIN accounts a ON a.id = h.account_id
+		WHERE h.id = $1
+		FOR UPDATE OF h
+	`
+
+	var hold models.Hold
+	var amountDecimal float64
+	var currency string
+
+	err := tx.QueryRow(ctx, query, holdID).Scan(&hold.Id, &hold.AccountID, &amountDecimal, &hold.Status, &hold.CreatedAt, &currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to lock hold: %w", err)
+	}
+
+	if hold.Status != models.HoldStatusActive {
+		return nil, ErrHoldNotActive
+	}
+
+	hold.Amount = money.FromDecimal(amountDecimal, currency)
+	return &hold, nil
+}
+
+// resolveHold moves a locked active hold to its final status
+func resolveHold(ctx context.Context, tx pgx.Tx, hold *models.Hold, status string) error {
+	query := `
+		UPDATE account_holds
+		SET status = $1, resolved_at = NOW()
+		WHERE id = $2
+	`
+
+	if _, err := tx.Exec(ctx, query, status, hold.Id); err != nil {
+		return fmt.Errorf("failed to update hold: %w", err)
+	}
+
+	hold.Status = status
+	return nil
+}
+
+// Aggregates holds system-wide business totals
+type Aggregates struct {
+	ActiveAccounts       int64          `json:"active_accounts"`
+	TotalBalance         map[string]int `json:"total_balance"` // in minor units, keyed by currency
+	TransactionsLastHour int64          `json:"transactions_last_hour"`
+}
+
+// GetAggregates returns the number of active accounts, the total balance per currency and
+// the number of transactions recorded in the last hour, leaving out soft-deleted accounts.
+// Served from the read replica when one is configured
+func (r *PostgresRepository) GetAggregates(ctx context.Context) (Aggregates, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Balances are summed per currency: amounts in different currencies can't be added up
+	accountsQuery := `
+		SELECT currency, COUNT(*) FILTER (WHERE status = $1), COALESCE(SUM(balance), 0)
+		FROM accounts
+		WHERE deleted_at IS NULL
+		GROUP BY currency
+	`
+
+	rows, err := r.readPool.Query(ctx, accountsQuery, models.AccountStatusActive)
+	if err != nil {
+		return Aggregates{}, fmt.Errorf("failed to aggregate accounts: %w", err)
+	}
+	defer rows.Close()
+
+	aggregates := Aggregates{TotalBalance: make(map[string]int)}
+	for rows.Next() {
+		var currency string
+		var activeAccounts int64
+		var balanceDecimal float64
+
+		if err := rows.Scan(&currency, &activeAccounts, &balanceDecimal); err != nil {
+			return Aggregates{}, fmt.Errorf("failed to aggregate accounts: %w", err)
+		}
+
+		aggregates.ActiveAccounts += activeAccounts
+		aggregates.TotalBalance[currency] = money.FromDecimal(balanceDecimal, currency)
+	}
+	if err := rows.Err(); err != nil {
+		return Aggregates{}, fmt.Errorf("failed to aggregate accounts: %w", err)
+	}
+
+	transactionsQuery := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE created_at >= NOW() - INTERVAL '1 hour'
+	`
+
+	if err := r.readPool.QueryRow(ctx, transactionsQuery).Scan(&aggregates.TransactionsLastHour); err != nil {
+		return Aggregates{}, fmt.Errorf("failed to count recent transactions: %w", err)
+	}
+
+	return aggregates, nil
+}
+
+// CleanupProcessedOperations deletes idempotency records processed more than olderThan ago
+// Returns the number of rows removed
+func (r *PostgresRepository) CleanupProcessedOperations(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Cutoff is computed by the database so it's consistent with processed_at DEFAULT NOW()
+	query := `
+		DELETE FROM processed_operations
+		WHERE processed_at < NOW() - $1 * INTERVAL '1 second'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup processed operations: %w", err)
+	}
+
+	removed := tag.RowsAffected()
+	log.Printf("Processed operations cleanup: removed=%d, older_than=%s", removed, olderThan)
+
+	return removed, nil
+}
+
+// InterestCredit describes the interest credited to a single account by ApplyInterestWithCredits
+type InterestCredit struct {
+	AccountID    int
+	Currency     string
+	Amount       int // in minor units of Currency
+	BalanceAfter int // in minor units of Currency
+}
+
+// ApplyInterest credits floor(balance * rate) minor units to every active account in a single transaction
+// Returns the number of accounts credited
+func (r *PostgresRepository) ApplyInterest(ctx context.Context, rate float64) (int64, error) {
+	credits, err := r.ApplyInterestWithCredits(ctx, rate)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(credits)), nil
+}
+
+// ApplyInterestWithCredits is ApplyInterest, returning the per-account credits so callers can publish events.
+// Accounts whose interest floors to zero minor units (including zero balances) are skipped and get no transaction row.
+// Frozen accounts earn nothing, and a credit is clamped so it never takes an account above the maximum balance.
+func (r *PostgresRepository) ApplyInterestWithCredits(ctx context.Context, rate float64) ([]InterestCredit, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	if rate <= 0 {
+		return nil, fmt.Errorf("interest rate must be positive, got %v", rate)
+	}
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock in ID order, consistent with the other multi-account operations
+	query := `
+		SELECT id, balance, currency
+		FROM accounts
+		WHERE status = $1 AND frozen = false AND balance > 0
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, models.AccountStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	credits := make([]InterestCredit, 0)
+	for rows.Next() {
+		var id int
+		var balanceDecimal float64
+		var currency string
+		if err := rows.Scan(&id, &balanceDecimal, &currency); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+
+		// Convert balance from DECIMAL to minor units and floor the interest to whole minor units
+		balance := money.FromDecimal(balanceDecimal, currency)
+		interest := int(math.Floor(float64(balance) * rate))
+		if r.maxBalance > 0 {
+			interest = min(interest, ScaleMaxBalance(r.maxBalance, currency)-balance)
+		}
+		if interest <= 0 {
+			continue
+		}
+
+		credits = append(credits, InterestCredit{
+			AccountID:    id,
+			Currency:     currency,
+			Amount:       interest,
+			BalanceAfter: balance + interest,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read accounts: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	for _, credit := range credits {
+		if _, err = tx.Exec(ctx, updateQuery, money.ToDecimal(credit.BalanceAfter, credit.Currency), credit.AccountID); err != nil {
+			return nil, fmt.Errorf("failed to credit interest to account %d: %w", credit.AccountID, err)
+		}
+		if err = insertTransaction(ctx, tx, credit.AccountID, "interest", credit.Amount, credit.BalanceAfter, credit.Currency, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Interest applied: rate=%v, accounts=%d", rate, len(credits))
+
+	return credits, nil
+}
+
+// AtomicWithdraw performs an atomic withdrawal operation using SELECT FOR UPDATE
+// This ensures no lost updates in concurrent scenarios
+func (r *PostgresRepository) AtomicWithdraw(ctx context.Context, accountID int, amount int) (*models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the row with SELECT FOR UPDATE
+	query := `
+		SELECT id, owner, balance, created_at, status, overdraft_limit, frozen, account_type, currency
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var account models.Account
+	var balanceDecimal float64
+
+	err = tx.QueryRow(ctx, query, accountID).Scan(
+		&account.Id,
+		&account.Owner,
+		&balanceDecimal,
+		&account.CreatedAt,
+		&account.Status,
+		&account.OverdraftLimit,
+		&account.Frozen,
+		&account.AccountType,
+		&account.Currency,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	if account.Status == models.AccountStatusClosed {
+		return nil, ErrAccountClosed
+	}
+
+	if account.Frozen {
+		return nil, ErrAccountFrozen
+	}
+
+	// Convert balance from DECIMAL to minor units
+	account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
+
+	held, err := heldAmount(ctx, tx, accountID, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if sufficient available balance (may go down to -OverdraftLimit)
+	if account.Balance-held-amount < -account.OverdraftLimit {
+		return nil, fmt.Errorf("insufficient balance: %w", ErrInsufficientFunds)
+	}
+
+	if err = r.checkWithdrawalLimit(ctx, tx, &account); err != nil {
+		return nil, err
+	}
+
+	// Update balance
+	newBalance := account.Balance - amount
+	newBalanceDecimal := money.ToDecimal(newBalance, account.Currency)
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	_, err = tx.Exec(ctx, updateQuery, newBalanceDecimal, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	// Record in the transaction log (atomic with the balance change)
+	if err = insertTransaction(ctx, tx, accountID, "withdraw", amount, newBalance, account.Currency, nil); err != nil {
+		return nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	account.Balance = newBalance
+	log.Printf("Atomic withdraw: ID=%d, Amount=%.2f, NewBalance=%.2f", accountID, money.ToDecimal(amount, account.Currency), newBalanceDecimal)
+
+	return &account, nil
+}
+
+// AtomicTransfer performs an atomic transfer operation using SELECT FOR UPDATE
+// This ensures no lost updates and no deadlocks (by ordering locks)
+func (r *PostgresRepository) AtomicTransfer(ctx context.Context, fromID int, toID int, amount int) (*models.Account, *models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	fromAccount, toAccount, err := r.transferInTx(ctx, tx, fromID, toID, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Atomic transfer: From=%d, To=%d, Amount=%.2f", fromID, toID, money.ToDecimal(amount, fromAccount.Currency))
+
+	return fromAccount, toAccount, nil
+}
+
+// transferInTx locks both accounts and moves amount from one to the other within tx.
+// Returns ErrBalanceLimitExceeded if the credit would take the destination above the configured
+// maximum balance. Returned accounts carry the balances after the transfer.
+func (r *PostgresRepository) transferInTx(ctx context.Context, tx pgx.Tx, fromID int, toID int, amount int) (*models.Account, *models.Account, error) {
+	// Lock accounts in order (lower ID first) to prevent deadlocks
+	firstID, secondID := fromID, toID
 	if fromID > toID {
 		firstID, secondID = toID, fromID
 	}
 
 	// Lock first account
 	query := `
-		SELECT id, owner, balance, created_at
+		SELECT id, owner, balance, created_at, status, overdraft_limit, currency, frozen
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		FOR UPDATE
 	`
 
 	var firstAccount, secondAccount models.Account
 	var firstBalanceDecimal, secondBalanceDecimal float64
 
-	err = tx.QueryRow(ctx, query, firstID).Scan(
+	err := tx.QueryRow(ctx, query, firstID).Scan(
 		&firstAccount.Id,
 		&firstAccount.Owner,
 		&firstBalanceDecimal,
 		&firstAccount.CreatedAt,
+		&firstAccount.Status,
+		&firstAccount.OverdraftLimit,
+		&firstAccount.Currency,
+		&firstAccount.Frozen,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("first account not found: %w", err)
@@ -402,6 +1804,10 @@ func (r *PostgresRepository) AtomicTransfer(fromID int, toID int, amount int) (*
 		&secondAccount.Owner,
 		&secondBalanceDecimal,
 		&secondAccount.CreatedAt,
+		&secondAccount.Status,
+		&secondAccount.OverdraftLimit,
+		&secondAccount.Currency,
+		&secondAccount.Frozen,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("second account not found: %w", err)
@@ -423,18 +1829,40 @@ func (r *PostgresRepository) AtomicTransfer(fromID int, toID int, amount int) (*
 		toBalanceDecimal = firstBalanceDecimal
 	}
 
-	// Convert balances from DECIMAL to cents
-	fromAccount.Balance = int(fromBalanceDecimal * 100)
-	toAccount.Balance = int(toBalanceDecimal * 100)
+	if fromAccount.Status == models.AccountStatusClosed || toAccount.Status == models.AccountStatusClosed {
+		return nil, nil, ErrAccountClosed
+	}
+
+	if fromAccount.Frozen || toAccount.Frozen {
+		return nil, nil, ErrAccountFrozen
+	}
+
+	// Amounts are bare minor units, so both sides must hold the same currency
+	if fromAccount.Currency != toAccount.Currency {
+		return nil, nil, fmt.Errorf("cannot transfer %s to %s: %w", fromAccount.Currency, toAccount.Currency, ErrCurrencyMismatch)
+	}
+
+	// Convert balances from DECIMAL to minor units
+	currency := fromAccount.Currency
+	fromAccount.Balance = money.FromDecimal(fromBalanceDecimal, currency)
+	toAccount.Balance = money.FromDecimal(toBalanceDecimal, currency)
+
+	held, err := heldAmount(ctx, tx, fromID, currency)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Check if sufficient balance
-	if fromAccount.Balance < amount {
-		return nil, nil, fmt.Errorf("insufficient balance")
+	// Check if sufficient available balance (may go down to -OverdraftLimit)
+	if fromAccount.Balance-held-amount < -fromAccount.OverdraftLimit {
+		return nil, nil, fmt.Errorf("insufficient balance: %w", ErrInsufficientFunds)
 	}
 
 	// Update balances
 	newFromBalance := fromAccount.Balance - amount
 	newToBalance := toAccount.Balance + amount
+	if r.exceedsMaxBalance(newToBalance, currency) {
+		return nil, nil, ErrBalanceLimitExceeded
+	}
 
 	updateQuery := `
 		UPDATE accounts
@@ -443,39 +1871,274 @@ func (r *PostgresRepository) AtomicTransfer(fromID int, toID int, amount int) (*
 	`
 
 	// Update from account
-	_, err = tx.Exec(ctx, updateQuery, float64(newFromBalance)/100.0, fromID)
+	_, err = tx.Exec(ctx, updateQuery, money.ToDecimal(newFromBalance, currency), fromID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to update from account: %w", err)
 	}
 
 	// Update to account
-	_, err = tx.Exec(ctx, updateQuery, float64(newToBalance)/100.0, toID)
+	_, err = tx.Exec(ctx, updateQuery, money.ToDecimal(newToBalance, currency), toID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to update to account: %w", err)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(ctx); err != nil {
-		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	// Record debit and credit legs with a shared reference (atomic with the balance changes)
+	referenceID := uuid.New().String()
+	if err = insertTransaction(ctx, tx, fromID, "transfer_out", amount, newFromBalance, currency, &referenceID); err != nil {
+		return nil, nil, err
+	}
+	if err = insertTransaction(ctx, tx, toID, "transfer_in", amount, newToBalance, currency, &referenceID); err != nil {
+		return nil, nil, err
 	}
 
 	fromAccount.Balance = newFromBalance
 	toAccount.Balance = newToBalance
 
-	log.Printf("Atomic transfer: From=%d, To=%d, Amount=%.2f", fromID, toID, float64(amount)/100)
+	return fromAccount, toAccount, nil
+}
+
+// AtomicTransferWithIdempotency performs an atomic transfer with an idempotency check.
+// The transfer, its transaction log rows and the processed_operations record (keyed on the
+// source account) are committed together. On replay it returns ErrDuplicateOperation with the
+// source balance recorded by the original transfer; the destination account carries only its ID.
+func (r *PostgresRepository) AtomicTransferWithIdempotency(ctx context.Context, fromID int, toID int, amount int, idempotencyKey string) (*models.Account, *models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Step 1: Check if operation already processed (idempotency check)
+	checkQuery := `
+		SELECT p.result_balance, a.currency
+		FROM processed_operations p
+		JOIN accounts a ON a.id = p.account_id
+		WHERE p.idempotency_key = $1
+	`
+
+	var resultBalance float64
+	var currency string
+	err = tx.QueryRow(ctx, checkQuery, idempotencyKey).Scan(&resultBalance, &currency)
+
+	if err == nil {
+		log.Printf("Duplicate transfer detected: idempotency_key=%s (skipping)", idempotencyKey)
+		return &models.Account{Id: fromID, Balance: money.FromDecimal(resultBalance, currency)},
+			&models.Account{Id: toID},
+			ErrDuplicateOperation
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	// Step 2: Lock both accounts and move the money
+	fromAccount, toAccount, err := r.transferInTx(ctx, tx, fromID, toID, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Step 3: Record the operation so a replay is rejected
+	insertQuery := `
+		INSERT INTO processed_operations
+		(idempotency_key, operation_type, account_id, amount, result_balance)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.Exec(ctx, insertQuery,
+		idempotencyKey,
+		"transfer",
+		fromID,
+		money.ToDecimal(amount, fromAccount.Currency),
+		money.ToDecimal(fromAccount.Balance, fromAccount.Currency),
+	)
+	if err != nil {
+		// A concurrent request with the same key committed first (unique_violation)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, nil, ErrDuplicateOperation
+		}
+		return nil, nil, fmt.Errorf("failed to record operation: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Atomic transfer with idempotency: From=%d, To=%d, Amount=%.2f, Key=%s",
+		fromID, toID, money.ToDecimal(amount, fromAccount.Currency), idempotencyKey)
 
 	return fromAccount, toAccount, nil
 }
 
+// Transfer is a single leg of a batch transfer
+type Transfer struct {
+	ToID   int `json:"to"`
+	Amount int `json:"amount"`
+}
+
+// AtomicBatchTransfer moves money from one account to many in a single database transaction.
+// All involved rows are locked in ascending ID order (same deadlock avoidance as AtomicTransfer)
+// and either every leg is applied or none is.
+// Returns the source account followed by each target account, in the order of targets.
+func (r *PostgresRepository) AtomicBatchTransfer(ctx context.Context, fromID int, targets []Transfer) ([]*models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("batch transfer requires at least one target")
+	}
+
+	total := 0
+	accountIDs := []int{fromID}
+	seen := map[int]bool{fromID: true}
+	for _, t := range targets {
+		if t.Amount <= 0 {
+			return nil, fmt.Errorf("invalid amount %d for account %d", t.Amount, t.ToID)
+		}
+		if seen[t.ToID] {
+			return nil, fmt.Errorf("account %d appears more than once in batch transfer", t.ToID)
+		}
+		seen[t.ToID] = true
+		accountIDs = append(accountIDs, t.ToID)
+		total += t.Amount
+	}
+	sort.Ints(accountIDs)
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, owner, balance, created_at, status, overdraft_limit, currency, frozen
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	// Lock accounts in order (lower ID first) to prevent deadlocks
+	accounts := make(map[int]*models.Account, len(accountIDs))
+	for _, id := range accountIDs {
+		var account models.Account
+		var balanceDecimal float64
+
+		err = tx.QueryRow(ctx, query, id).Scan(
+			&account.Id,
+			&account.Owner,
+			&balanceDecimal,
+			&account.CreatedAt,
+			&account.Status,
+			&account.OverdraftLimit,
+			&account.Currency,
+			&account.Frozen,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account %d: %w", id, ErrAccountNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock account %d: %w", id, err)
+		}
+
+		if account.Status == models.AccountStatusClosed {
+			return nil, ErrAccountClosed
+		}
+
+		if account.Frozen {
+			return nil, ErrAccountFrozen
+		}
+
+		// Convert balance from DECIMAL to minor units
+		account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
+		accounts[id] = &account
+	}
+
+	fromAccount := accounts[fromID]
+
+	// Every target must hold the source's currency
+	for _, t := range targets {
+		if accounts[t.ToID].Currency != fromAccount.Currency {
+			return nil, fmt.Errorf("account %d: %w", t.ToID, ErrCurrencyMismatch)
+		}
+	}
+
+	held, err := heldAmount(ctx, tx, fromID, fromAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the source's available balance covers the whole batch (overdraft included)
+	// before touching any balance
+	if fromAccount.Balance-held-total < -fromAccount.OverdraftLimit {
+		return nil, ErrInsufficientFunds
+	}
+
+	// No credit may take a target above the configured maximum balance
+	for _, t := range targets {
+		if toAccount := accounts[t.ToID]; r.exceedsMaxBalance(toAccount.Balance+t.Amount, toAccount.Currency) {
+			return nil, fmt.Errorf("account %d: %w", t.ToID, ErrBalanceLimitExceeded)
+		}
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	result := make([]*models.Account, 0, len(targets)+1)
+	result = append(result, fromAccount)
+
+	// Each leg gets its own reference_id so its debit and credit rows can be paired
+	for _, t := range targets {
+		toAccount := accounts[t.ToID]
+		fromAccount.Balance -= t.Amount
+		toAccount.Balance += t.Amount
+
+		if _, err = tx.Exec(ctx, updateQuery, money.ToDecimal(toAccount.Balance, toAccount.Currency), toAccount.Id); err != nil {
+			return nil, fmt.Errorf("failed to update account %d: %w", toAccount.Id, err)
+		}
+
+		referenceID := uuid.New().String()
+		if err = insertTransaction(ctx, tx, fromID, "transfer_out", t.Amount, fromAccount.Balance, fromAccount.Currency, &referenceID); err != nil {
+			return nil, err
+		}
+		if err = insertTransaction(ctx, tx, toAccount.Id, "transfer_in", t.Amount, toAccount.Balance, toAccount.Currency, &referenceID); err != nil {
+			return nil, err
+		}
+
+		result = append(result, toAccount)
+	}
+
+	if _, err = tx.Exec(ctx, updateQuery, money.ToDecimal(fromAccount.Balance, fromAccount.Currency), fromID); err != nil {
+		return nil, fmt.Errorf("failed to update from account: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Atomic batch transfer: From=%d, Legs=%d, Total=%.2f", fromID, len(targets), money.ToDecimal(total, fromAccount.Currency))
+
+	return result, nil
+}
+
 // AtomicDepositWithIdempotency performs an atomic deposit operation with idempotency check.
+// Returns ErrBalanceLimitExceeded if the deposit would exceed the configured maximum balance.
 // This ensures that:
 // 1. Duplicate messages with the same idempotency key are not processed twice
-// 2. The deposit and idempotency record are inserted atomically (all-or-nothing)
+// 2. The deposit, idempotency record and transaction log row are written atomically (all-or-nothing)
 // 3. Returns ErrDuplicateOperation if the idempotency key already exists
 //
 // This is the key method that makes the consumer idempotent!
-func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount int, idempotencyKey string) (*models.Account, error) {
-	ctx := context.Background()
+func (r *PostgresRepository) AtomicDepositWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
 
 	// Start transaction
 	tx, err := r.pool.Begin(ctx)
@@ -486,20 +2149,22 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 
 	// Step 1: Check if operation already processed (idempotency check)
 	checkQuery := `
-		SELECT result_balance
-		FROM processed_operations
-		WHERE idempotency_key = $1
+		SELECT p.result_balance, a.currency
+		FROM processed_operations p
+		JOIN accounts a ON a.id = p.account_id
+		WHERE p.idempotency_key = $1
 	`
 
 	var resultBalance float64
-	err = tx.QueryRow(ctx, checkQuery, idempotencyKey).Scan(&resultBalance)
+	var currency string
+	err = tx.QueryRow(ctx, checkQuery, idempotencyKey).Scan(&resultBalance, &currency)
 
 	if err == nil {
 		// Already processed! Return existing result (idempotent)
 		log.Printf("Duplicate operation detected: idempotency_key=%s (skipping)", idempotencyKey)
 		return &models.Account{
 			Id:      accountID,
-			Balance: int(resultBalance * 100), // Convert DECIMAL to cents
+			Balance: money.FromDecimal(resultBalance, currency), // Convert DECIMAL to minor units
 		}, ErrDuplicateOperation
 	}
 
@@ -509,9 +2174,9 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 
 	// Step 2: Operation not yet processed - lock account and perform deposit
 	lockQuery := `
-		SELECT id, owner, balance, created_at
+		SELECT id, owner, balance, created_at, status, overdraft_limit, frozen, currency
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		FOR UPDATE
 	`
 
@@ -523,18 +2188,36 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 		&account.Owner,
 		&balanceDecimal,
 		&account.CreatedAt,
+		&account.Status,
+		&account.OverdraftLimit,
+		&account.Frozen,
+		&account.Currency,
 	)
 
 	if err != nil {
-		return nil, ErrAccountNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if account.Status == models.AccountStatusClosed {
+		return nil, ErrAccountClosed
+	}
+
+	if account.Frozen {
+		return nil, ErrAccountFrozen
 	}
 
-	// Convert balance from DECIMAL to cents
-	account.Balance = int(balanceDecimal * 100)
+	// Convert balance from DECIMAL to minor units
+	account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
 
 	// Step 3: Update account balance
 	newBalance := account.Balance + amount
-	newBalanceDecimal := float64(newBalance) / 100.0
+	if r.exceedsMaxBalance(newBalance, account.Currency) {
+		return nil, ErrBalanceLimitExceeded
+	}
+	newBalanceDecimal := money.ToDecimal(newBalance, account.Currency)
 
 	updateQuery := `
 		UPDATE accounts
@@ -554,7 +2237,7 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	amountDecimal := float64(amount) / 100.0
+	amountDecimal := money.ToDecimal(amount, account.Currency)
 
 	_, err = tx.Exec(ctx, insertQuery,
 		idempotencyKey,
@@ -568,7 +2251,12 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 		return nil, fmt.Errorf("failed to record operation: %w", err)
 	}
 
-	// Step 5: Commit transaction (all-or-nothing)
+	// Step 5: Record in the transaction log
+	if err = insertTransaction(ctx, tx, accountID, "deposit", amount, newBalance, account.Currency, nil); err != nil {
+		return nil, err
+	}
+
+	// Step 6: Commit transaction (all-or-nothing)
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -579,3 +2267,154 @@ func (r *PostgresRepository) AtomicDepositWithIdempotency(accountID int, amount
 
 	return &account, nil
 }
+
+// AtomicWithdrawWithIdempotency performs an atomic withdrawal operation with idempotency check.
+// It mirrors AtomicDepositWithIdempotency:
+// 1. Duplicate messages with the same idempotency key are not processed twice
+// 2. The withdrawal, idempotency record and transaction log row are written atomically (all-or-nothing)
+// 3. Returns ErrDuplicateOperation if the idempotency key already exists
+// 4. Returns ErrInsufficientFunds if the balance doesn't cover the amount
+// 5. Returns ErrWithdrawalLimitExceeded if a savings account has no withdrawals left this period
+func (r *PostgresRepository) AtomicWithdrawWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error) {
+	ctx, cancel := r.withStatementTimeout(ctx)
+	defer cancel()
+
+	// Start transaction
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Step 1: Check if operation already processed (idempotency check)
+	checkQuery := `
+		SELECT p.result_balance, a.currency
+		FROM processed_operations p
+		JOIN accounts a ON a.id = p.account_id
+		WHERE p.idempotency_key = $1
+	`
+
+	var resultBalance float64
+	var currency string
+	err = tx.QueryRow(ctx, checkQuery, idempotencyKey).Scan(&resultBalance, &currency)
+
+	if err == nil {
+		// Already processed! Return existing result (idempotent)
+		log.Printf("Duplicate operation detected: idempotency_key=%s (skipping)", idempotencyKey)
+		return &models.Account{
+			Id:      accountID,
+			Balance: money.FromDecimal(resultBalance, currency), // Convert DECIMAL to minor units
+		}, ErrDuplicateOperation
+	}
+
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	// Step 2: Operation not yet processed - lock account
+	lockQuery := `
+		SELECT id, owner, balance, created_at, status, overdraft_limit, frozen, account_type, currency
+		FROM accounts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var account models.Account
+	var balanceDecimal float64
+
+	err = tx.QueryRow(ctx, lockQuery, accountID).Scan(
+		&account.Id,
+		&account.Owner,
+		&balanceDecimal,
+		&account.CreatedAt,
+		&account.Status,
+		&account.OverdraftLimit,
+		&account.Frozen,
+		&account.AccountType,
+		&account.Currency,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	if account.Status == models.AccountStatusClosed {
+		return nil, ErrAccountClosed
+	}
+
+	if account.Frozen {
+		return nil, ErrAccountFrozen
+	}
+
+	// Convert balance from DECIMAL to minor units
+	account.Balance = money.FromDecimal(balanceDecimal, account.Currency)
+
+	held, err := heldAmount(ctx, tx, accountID, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Check if sufficient available balance (may go down to -OverdraftLimit)
+	if account.Balance-held-amount < -account.OverdraftLimit {
+		return nil, ErrInsufficientFunds
+	}
+
+	if err = r.checkWithdrawalLimit(ctx, tx, &account); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Update account balance
+	newBalance := account.Balance - amount
+	newBalanceDecimal := money.ToDecimal(newBalance, account.Currency)
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance = $1, version = version + 1
+		WHERE id = $2
+	`
+
+	_, err = tx.Exec(ctx, updateQuery, newBalanceDecimal, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	// Step 5: Record operation as processed (atomic with withdrawal)
+	insertQuery := `
+		INSERT INTO processed_operations
+		(idempotency_key, operation_type, account_id, amount, result_balance)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	amountDecimal := money.ToDecimal(amount, account.Currency)
+
+	_, err = tx.Exec(ctx, insertQuery,
+		idempotencyKey,
+		"withdraw",
+		accountID,
+		amountDecimal,
+		newBalanceDecimal,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to record operation: %w", err)
+	}
+
+	// Step 6: Record in the transaction log
+	if err = insertTransaction(ctx, tx, accountID, "withdraw", amount, newBalance, account.Currency, nil); err != nil {
+		return nil, err
+	}
+
+	// Step 7: Commit transaction (all-or-nothing)
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	account.Balance = newBalance
+	log.Printf("Atomic withdraw with idempotency: ID=%d, Amount=%.2f, NewBalance=%.2f, Key=%s",
+		accountID, amountDecimal, newBalanceDecimal, idempotencyKey)
+
+	return &account, nil
+}
@@ -3,22 +3,108 @@ package database
 import (
 	"bank-api/internal/domain/models"
 	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"time"
 )
 
 // Repository defines the required methods for persisting accounts.
+// Every method takes the caller's context so cancellations and deadlines reach the database.
 type Repository interface {
-	CreateAccount(owner string) int
-	GetAccount(id int) (*models.Account, bool)
-	UpdateAccount(acc *models.Account)
-	Reset()
+	CreateAccount(ctx context.Context, owner string) int
+
+	// CreateAccountWithCurrency opens an account holding balances in the given ISO 4217 currency
+	CreateAccountWithCurrency(ctx context.Context, owner string, currency string) int
+
+	// CreateAccountWithType opens a checking or savings account; savings accounts get a per-period withdrawal limit
+	CreateAccountWithType(ctx context.Context, owner string, currency string, accountType string) int
+
+	// CreateAccountIdempotent creates an account at most once per clientRequestID
+	// Returns the existing account and created=false when the ID has been used before
+	CreateAccountIdempotent(ctx context.Context, owner string, currency string, accountType string, clientRequestID string) (*models.Account, bool, error)
+
+	// CreateAccountsBulk opens count accounts in one transaction, optionally crediting each
+	// with initialBalance, and returns their IDs in ascending order
+	CreateAccountsBulk(ctx context.Context, ownerPrefix string, count int, initialBalance int) ([]int, error)
+
+	GetAccount(ctx context.Context, id int) (*models.Account, bool)
+
+	// ListAccounts returns a page of accounts ordered by ID and the total account count
+	ListAccounts(ctx context.Context, limit int, offset int) ([]*models.Account, int, error)
+
+	UpdateAccount(ctx context.Context, acc *models.Account)
+
+	// UpdateAccountVersioned updates only if the stored version matches expectedVersion
+	// Returns ErrVersionConflict if the account changed since it was read
+	UpdateAccountVersioned(ctx context.Context, acc *models.Account, expectedVersion int) error
+	Reset(ctx context.Context)
+
+	// CloseAccount closes a zero-balance account
+	// Returns ErrAccountHasBalance if funds remain, ErrAccountClosed if already closed
+	CloseAccount(ctx context.Context, id int) error
+
+	// SoftDeleteAccount hides a zero-balance account from reads and operations, keeping its transactions
+	SoftDeleteAccount(ctx context.Context, id int) error
+
+	// RestoreAccount makes a soft-deleted account visible again
+	RestoreAccount(ctx context.Context, id int) error
+
+	// SetOverdraftLimit sets how far below zero (in minor units of the account currency) the balance may go
+	// Returns ErrOverdraftInUse if the account is already overdrawn by more than the limit
+	SetOverdraftLimit(ctx context.Context, id int, limitCents int) error
+
+	// SetFrozen places or lifts a hold; frozen accounts reject money movements with ErrAccountFrozen
+	// Returns ErrAccountClosed if the account has been closed
+	SetFrozen(ctx context.Context, id int, frozen bool) error
+
+	// CreatePendingOperation records an asynchronous operation as pending before it is published
+	CreatePendingOperation(ctx context.Context, operationID string, operationType string, accountID int, amount int) error
+
+	// SetOperationStatus moves a pending operation to completed, duplicate or failed; final states are kept
+	SetOperationStatus(ctx context.Context, operationID string, status string, reason string) error
+
+	// GetOperation returns an operation's status, or ErrOperationNotFound
+	GetOperation(ctx context.Context, operationID string) (*models.Operation, error)
+
+	// PlaceHold reserves funds that count against the available balance until released or captured
+	// Returns ErrInsufficientFunds if the available balance doesn't cover the hold
+	PlaceHold(ctx context.Context, accountID int, amount int) (int, error)
+	// ReleaseHold frees a hold's funds; CaptureHold debits them
+	// Both return ErrHoldNotFound, or ErrHoldNotActive if the hold was already resolved
+	ReleaseHold(ctx context.Context, holdID int) (*models.Hold, error)
+	CaptureHold(ctx context.Context, holdID int) (*models.Hold, error)
 
 	// Atomic operations for concurrency safety
-	AtomicWithdraw(accountID int, amount int) (*models.Account, error)
-	AtomicTransfer(fromID int, toID int, amount int) (*models.Account, *models.Account, error)
+	// Withdrawals return ErrWithdrawalLimitExceeded once a savings account reaches its limit
+	AtomicWithdraw(ctx context.Context, accountID int, amount int) (*models.Account, error)
+	// AtomicTransfer returns ErrCurrencyMismatch if the accounts hold different currencies
+	AtomicTransfer(ctx context.Context, fromID int, toID int, amount int) (*models.Account, *models.Account, error)
+
+	// AtomicBatchTransfer applies every leg or none; returns the source followed by each target
+	AtomicBatchTransfer(ctx context.Context, fromID int, targets []postgres.Transfer) ([]*models.Account, error)
+
+	// Atomic operations with idempotency check
+	// Return ErrDuplicateOperation if idempotency key already exists
+	AtomicDepositWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error)
+	AtomicWithdrawWithIdempotency(ctx context.Context, accountID int, amount int, idempotencyKey string) (*models.Account, error)
+	AtomicTransferWithIdempotency(ctx context.Context, fromID int, toID int, amount int, idempotencyKey string) (*models.Account, *models.Account, error)
+
+	// GetAggregates returns system-wide totals for business metrics
+	GetAggregates(ctx context.Context) (postgres.Aggregates, error)
+
+	// Transaction history (most recent first)
+	GetTransactionHistory(ctx context.Context, accountID int, limit int) ([]map[string]interface{}, error)
+
+	// GetTransactionHistoryFiltered pages through history by (created_at, id) cursor and type
+	GetTransactionHistoryFiltered(ctx context.Context, accountID int, filter postgres.HistoryFilter) (postgres.HistoryPage, error)
+
+	// GetBalanceAsOf returns the balance after the last transaction at or before ts (0 if none)
+	GetBalanceAsOf(ctx context.Context, accountID int, ts time.Time) (int, error)
+
+	// ListTransactions pages through the transaction log of all accounts in ID order
+	ListTransactions(ctx context.Context, fromID int, since time.Time, limit int) ([]postgres.TransactionRecord, error)
 
-	// Atomic operation with idempotency check
-	// Returns ErrDuplicateOperation if idempotency key already exists
-	AtomicDepositWithIdempotency(accountID int, amount int, idempotencyKey string) (*models.Account, error)
+	// Ping checks that the database is reachable
+	Ping(ctx context.Context) error
 }
 
 var (
@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"time"
+
+	"bank-api/internal/pkg/telemetry"
+
+	"github.com/IBM/sarama"
+)
+
+// consumerLagInterval is how often lag is re-reported while a claim is idle or slow
+const consumerLagInterval = 10 * time.Second
+
+// lagReporter exports kafka_consumer_lag for a claim: the distance between the partition's
+// high-water mark (refreshed by sarama on every fetch) and the offset the group resumes from,
+// i.e. the offset after the last message marked for commit.
+type lagReporter struct {
+	claim  sarama.ConsumerGroupClaim
+	next   int64
+	ticker *time.Ticker
+}
+
+// newLagReporter starts reporting from the claim's initial (committed) offset
+func newLagReporter(claim sarama.ConsumerGroupClaim) *lagReporter {
+	r := &lagReporter{
+		claim:  claim,
+		next:   claim.InitialOffset(),
+		ticker: time.NewTicker(consumerLagInterval),
+	}
+	r.Report()
+	return r
+}
+
+// Advance records that message was marked for commit and reports the new lag
+func (r *lagReporter) Advance(message *sarama.ConsumerMessage) {
+	r.next = message.Offset + 1
+	r.Report()
+}
+
+// Report sets the lag gauge. Nothing is reported until the position is known: a group with no
+// committed offset starts from sarama.OffsetOldest/OffsetNewest, which are negative sentinels.
+func (r *lagReporter) Report() {
+	if r.next < 0 {
+		return
+	}
+
+	lag := r.claim.HighWaterMarkOffset() - r.next
+	if lag < 0 {
+		lag = 0
+	}
+	metrics.UpdateKafkaConsumerLag(r.claim.Topic(), r.claim.Partition(), lag)
+}
+
+// Ticks fires every consumerLagInterval
+func (r *lagReporter) Ticks() <-chan time.Time {
+	return r.ticker.C
+}
+
+// Stop stops the report timer; the last reported lag is kept
+func (r *lagReporter) Stop() {
+	r.ticker.Stop()
+}
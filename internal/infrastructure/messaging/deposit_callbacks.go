@@ -0,0 +1,157 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/validation"
+)
+
+const (
+	// depositCallbackAttempts is how many times a completion is POSTed before giving up
+	depositCallbackAttempts = 3
+	// depositCallbackBackoff is the wait between attempts, multiplied by the attempt number
+	depositCallbackBackoff = 500 * time.Millisecond
+)
+
+// DepositCallbackPayload is the body POSTed to a deposit's callback URL
+type DepositCallbackPayload struct {
+	OperationID  string    `json:"operation_id"`
+	AccountID    int       `json:"account_id"`
+	Amount       int       `json:"amount"`        // in cents
+	BalanceAfter int       `json:"balance_after"` // in cents
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// DepositCallbackPublisher wraps the consumer's EventPublisher and POSTs each completed deposit
+// to the callback_url its DepositRequestedEvent carried, if any
+type DepositCallbackPublisher struct {
+	EventPublisher
+
+	client *http.Client
+	wg     sync.WaitGroup
+}
+
+// NewDepositCallbackPublisher wraps next with deposit completion callbacks
+func NewDepositCallbackPublisher(next EventPublisher) *DepositCallbackPublisher {
+	// Every connection, including redirects, is checked after DNS resolution so a hostname
+	// can't be used to reach an address ValidateCallbackURL would have refused
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: guardCallbackAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &DepositCallbackPublisher{
+		EventPublisher: next,
+		client:         &http.Client{Timeout: 5 * time.Second, Transport: transport},
+	}
+}
+
+// guardCallbackAddress refuses connections to addresses callbacks may not reach
+func guardCallbackAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !validation.CallbackAddressAllowed(addr) {
+		return fmt.Errorf("callback address %s is not allowed", addr)
+	}
+	return nil
+}
+
+// PublishDepositCompleted publishes the event and then delivers it to the deposit's callback URL.
+// Delivery happens in the background so a slow client can't hold up the consumer.
+func (p *DepositCallbackPublisher) PublishDepositCompleted(event DepositCompletedEvent) error {
+	if err := p.EventPublisher.PublishDepositCompleted(event); err != nil {
+		return err
+	}
+
+	if event.CallbackURL == "" {
+		return nil
+	}
+	// The URL arrives over Kafka, so it is checked here too rather than trusting the producer
+	if err := validation.ValidateCallbackURL(event.CallbackURL); err != nil {
+		logging.Warn("Deposit callback skipped", map[string]interface{}{
+			"operation_id": event.OperationID,
+			"error":        err.Error(),
+		})
+		return nil
+	}
+
+	payload := DepositCallbackPayload{
+		OperationID:  event.OperationID,
+		AccountID:    event.AccountID,
+		Amount:       event.Amount,
+		BalanceAfter: event.BalanceAfter,
+		Timestamp:    event.Timestamp,
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.deliver(event.CallbackURL, payload)
+	}()
+	return nil
+}
+
+// deliver POSTs the payload, retrying on transport errors and non-2xx responses
+func (p *DepositCallbackPublisher) deliver(callbackURL string, payload DepositCallbackPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logging.Error("Failed to marshal deposit callback", err, map[string]interface{}{
+			"operation_id": payload.OperationID,
+		})
+		return
+	}
+
+	for attempt := 1; attempt <= depositCallbackAttempts; attempt++ {
+		if err = p.post(callbackURL, body); err == nil {
+			logging.Debug("Deposit callback delivered", map[string]interface{}{
+				"operation_id": payload.OperationID,
+				"attempt":      attempt,
+			})
+			return
+		}
+
+		if attempt < depositCallbackAttempts {
+			time.Sleep(time.Duration(attempt) * depositCallbackBackoff)
+		}
+	}
+
+	logging.Warn("Deposit callback failed", map[string]interface{}{
+		"operation_id": payload.OperationID,
+		"attempts":     depositCallbackAttempts,
+		"error":        err.Error(),
+	})
+}
+
+func (p *DepositCallbackPublisher) post(callbackURL string, body []byte) error {
+	resp, err := p.client.Post(callbackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close waits for in-flight callbacks and then closes the wrapped publisher
+func (p *DepositCallbackPublisher) Close() error {
+	p.wg.Wait()
+	return p.EventPublisher.Close()
+}
@@ -2,12 +2,15 @@ package messaging
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	"bank-api/internal/domain/models"
 	"bank-api/internal/infrastructure/database"
 	"bank-api/internal/infrastructure/database/postgres"
 	"bank-api/internal/infrastructure/messaging/kafka"
@@ -65,32 +68,69 @@ func NewDepositConsumer(config *kafka.Config, publisher EventPublisher, db datab
 	}, nil
 }
 
+// errMalformedMessage marks messages that can never be processed and must skip retries
+var errMalformedMessage = errors.New("malformed message")
+
+// errUnsupportedEventVersion marks events published with a schema version this consumer doesn't understand
+var errUnsupportedEventVersion = errors.New("unsupported event version")
+
+// checkEventVersion reads only the event metadata, so a newer schema with incompatible fields
+// is reported as a version mismatch instead of failing on unmarshal. Events without a version
+// predate versioning and are read as the current version.
+func checkEventVersion(payload []byte) error {
+	var metadata EventMetadata
+	if err := json.Unmarshal(payload, &metadata); err != nil {
+		return fmt.Errorf("%w: %v", errMalformedMessage, err)
+	}
+
+	if metadata.EventVersion != "" && metadata.EventVersion != CurrentEventVersion {
+		return fmt.Errorf("%w: %w %q", errMalformedMessage, errUnsupportedEventVersion, metadata.EventVersion)
+	}
+	return nil
+}
+
+// messageTraceID returns the trace ID of a deposit request message, or "" if it has none or
+// can't be read. Used for log lines written before (or instead of) a successful decode.
+func messageTraceID(message *sarama.ConsumerMessage) string {
+	return peekDepositRequest(message).TraceID
+}
+
+// messageOperationID extracts the operation ID from a raw message, or "" if it can't be read
+func messageOperationID(message *sarama.ConsumerMessage) string {
+	return peekDepositRequest(message).OperationID
+}
+
+// NewDepositConsumerHandler returns the sarama handler used by DepositConsumer.
+// Exposed so the processing logic can be driven without a running broker.
+func NewDepositConsumerHandler(config *kafka.Config, publisher EventPublisher, db database.Repository) sarama.ConsumerGroupHandler {
+	maxRetries := config.ConsumerMaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	return &depositConsumerHandler{
+		publisher:       publisher,
+		db:              db,
+		maxRetries:      maxRetries,
+		retryBackoff:    config.ConsumerRetryBackoff,
+		commitBatchSize: config.ConsumerCommitBatchSize,
+		commitInterval:  config.ConsumerCommitInterval,
+	}
+}
+
 // Start begins consuming deposit request events
 func (c *DepositConsumer) Start() error {
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
 
-		handler := &depositConsumerHandler{
-			publisher: c.publisher,
-			db:        c.db,
-		}
-
+		handler := NewDepositConsumerHandler(c.config, c.publisher, c.db)
 		topics := []string{kafka.TopicDepositRequests}
 
-		for {
-			// `Consume` should be called inside an infinite loop, when a
-			// server-side rebalance happens, the consumer session will need to be
-			// recreated to get the new claims
-			if err := c.consumerGroup.Consume(c.ctx, topics, handler); err != nil {
-				log.Printf("Error from consumer: %v", err)
-			}
-
-			// check if context was cancelled, signaling that the consumer should stop
-			if c.ctx.Err() != nil {
-				return
-			}
-		}
+		// Back off between failed sessions so an unavailable broker doesn't busy-spin the loop
+		ConsumeWithBackoff(c.ctx, "deposit-processor-group", NewReconnectBackoff(c.config), func(ctx context.Context) error {
+			return c.consumerGroup.Consume(ctx, topics, handler)
+		})
 	}()
 
 	// Handle errors in a separate goroutine
@@ -129,8 +169,12 @@ func (c *DepositConsumer) Stop() error {
 
 // depositConsumerHandler implements sarama.ConsumerGroupHandler
 type depositConsumerHandler struct {
-	publisher EventPublisher
-	db        database.Repository
+	publisher       EventPublisher
+	db              database.Repository
+	maxRetries      int
+	retryBackoff    time.Duration
+	commitBatchSize int
+	commitInterval  time.Duration
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim
@@ -145,25 +189,51 @@ func (h *depositConsumerHandler) Cleanup(sarama.ConsumerGroupSession) error {
 
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages()
 func (h *depositConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	committer := newOffsetCommitter(session, h.commitBatchSize, h.commitInterval)
+	defer committer.Close() // Commit what was handled before the claim ends
+	lag := newLagReporter(claim)
+	defer lag.Stop()
+
 	for {
+		// Draining: once the session ends, don't pick up another message even if one is buffered
+		if session.Context().Err() != nil {
+			return nil
+		}
+
 		select {
 		case message := <-claim.Messages():
 			if message == nil {
 				return nil
 			}
 
-			// Process the deposit request
-			if err := h.processDepositRequest(message); err != nil {
-				log.Printf("Failed to process deposit request: offset=%d, error=%v", message.Offset, err)
-				// AT-LEAST-ONCE: Don't mark or commit on failure
-				// Message will be reprocessed after consumer restart/rebalance
-				continue
+			attempts, err := h.processWithRetries(session.Context(), message)
+			if err != nil && session.Context().Err() != nil {
+				// Session ended mid-retry - leave the message uncommitted for the next owner
+				return nil
 			}
+			if err != nil {
+				// Retries exhausted or message is malformed - park it in the DLQ
+				if dlqErr := h.publishDeadLetter(message, err, attempts); dlqErr != nil {
+					logging.Error("Failed to publish deposit request to dead-letter topic", dlqErr, map[string]interface{}{
+						"offset":   message.Offset,
+						"trace_id": messageTraceID(message),
+					})
+					// AT-LEAST-ONCE: Don't mark or commit if the DLQ publish failed
+					// Message will be reprocessed after consumer restart/rebalance
+					continue
+				}
+			}
+
+			// AT-LEAST-ONCE: Mark message only after successful processing (or after it has
+			// been safely parked in the DLQ); offsets are committed in batches
+			committer.Mark(message)
+			lag.Advance(message)
+
+		case <-committer.Ticks():
+			committer.Flush()
 
-			// AT-LEAST-ONCE: Mark message and commit immediately after successful processing
-			// This ensures we don't reprocess successfully handled messages
-			session.MarkMessage(message, "")
-			session.Commit() // Explicit commit for at-least-once guarantee
+		case <-lag.Ticks():
+			lag.Report()
 
 		case <-session.Context().Done():
 			return nil
@@ -171,56 +241,225 @@ func (h *depositConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSessio
 	}
 }
 
+// processWithRetries processes a message up to maxRetries times, backing off between attempts.
+// Malformed messages are not retried. Returns the number of attempts made and the last error.
+// An attempt that has started runs to completion even if ctx is cancelled (shutdown drains it);
+// cancellation only stops further attempts.
+func (h *depositConsumerHandler) processWithRetries(ctx context.Context, message *sarama.ConsumerMessage) (int, error) {
+	var err error
+	for attempt := 1; attempt <= h.maxRetries; attempt++ {
+		if err = h.processDepositRequest(context.WithoutCancel(ctx), message); err == nil {
+			return attempt, nil
+		}
+
+		logging.Warn("Failed to process deposit request", map[string]interface{}{
+			"offset":   message.Offset,
+			"attempt":  attempt,
+			"max":      h.maxRetries,
+			"trace_id": messageTraceID(message),
+			"error":    err.Error(),
+		})
+
+		if errors.Is(err, errMalformedMessage) || attempt == h.maxRetries {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(h.retryBackoff):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+	}
+	return h.maxRetries, err
+}
+
+// newDeadLetterEvent wraps a message that could not be processed for its dead-letter topic
+func newDeadLetterEvent(message *sarama.ConsumerMessage, cause error, attempts int) DeadLetterEvent {
+	// Binary payloads would be mangled in a JSON string, so they are kept as base64
+	contentType := kafka.MessageContentType(message)
+	payload := string(message.Value)
+	if contentType != kafka.ContentTypeJSON {
+		payload = base64.StdEncoding.EncodeToString(message.Value)
+	}
+
+	return DeadLetterEvent{
+		OriginalTopic: message.Topic,
+		Partition:     message.Partition,
+		Offset:        message.Offset,
+		Key:           string(message.Key),
+		Payload:       payload,
+		ContentType:   contentType,
+		ErrorMessage:  cause.Error(),
+		Attempts:      attempts,
+		Timestamp:     time.Now(),
+	}
+}
+
+// publishDeadLetter sends the raw message to the deposit dead-letter topic
+func (h *depositConsumerHandler) publishDeadLetter(message *sarama.ConsumerMessage, cause error, attempts int) error {
+	if err := h.publisher.PublishDeadLetter(newDeadLetterEvent(message, cause, attempts)); err != nil {
+		return err
+	}
+
+	logging.Warn("Deposit request moved to dead-letter topic", map[string]interface{}{
+		"topic":     message.Topic,
+		"partition": message.Partition,
+		"offset":    message.Offset,
+		"attempts":  attempts,
+		"trace_id":  messageTraceID(message),
+		"error":     cause.Error(),
+	})
+	metrics.RecordBankingOperation("deposit", "dead_lettered")
+
+	if operationID := messageOperationID(message); operationID != "" {
+		recordOutcome(h.db, operationID, models.OperationStatusFailed, FailureReasonDeadLettered)
+	}
+	return nil
+}
+
+// recordOutcome stores the final status of an operation for GET /operations/:id.
+// The operation itself has already been settled, so a failure here is logged rather than retried.
+func recordOutcome(db database.Repository, operationID string, status string, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.SetOperationStatus(ctx, operationID, status, reason); err != nil {
+		logging.Error("Failed to record operation status", err, map[string]interface{}{
+			"operation_id": operationID,
+			"status":       status,
+		})
+	}
+}
+
+// alreadyCompleted reports whether the operation was recorded as completed, i.e. this message
+// was applied before. Operations without an ID can't be told apart, so they never are.
+func alreadyCompleted(ctx context.Context, db database.Repository, operationID string) bool {
+	if operationID == "" {
+		return false
+	}
+	operation, err := db.GetOperation(ctx, operationID)
+	return err == nil && operation.Status == models.OperationStatusCompleted
+}
+
+// publishCompleted publishes the deposit completed event for an applied deposit request
+func (h *depositConsumerHandler) publishCompleted(event DepositRequestedEvent, balance int) error {
+	completedEvent := DepositCompletedEvent{
+		OperationID:  event.OperationID,
+		AccountID:    event.AccountID,
+		Amount:       event.Amount,
+		BalanceAfter: balance,
+		TraceID:      event.TraceID,
+		Timestamp:    time.Now(),
+		CallbackURL:  event.CallbackURL,
+	}
+	if err := h.publisher.PublishDepositCompleted(completedEvent); err != nil {
+		logging.Error("Failed to publish deposit completed event", err, map[string]interface{}{
+			"operation_id": event.OperationID,
+			"trace_id":     event.TraceID,
+			"account_id":   event.AccountID,
+		})
+		return err
+	}
+	return nil
+}
+
 // processDepositRequest processes a single deposit request event with idempotency
-func (h *depositConsumerHandler) processDepositRequest(message *sarama.ConsumerMessage) error {
-	// Deserialize the event
-	var event DepositRequestedEvent
-	if err := json.Unmarshal(message.Value, &event); err != nil {
-		logging.Error("Failed to unmarshal deposit request event", err, map[string]interface{}{
-			"offset": message.Offset,
+func (h *depositConsumerHandler) processDepositRequest(ctx context.Context, message *sarama.ConsumerMessage) error {
+	// Deserialize the event in the format its content-type header declares
+	event, err := DecodeDepositRequested(message)
+	if err != nil {
+		logging.Error("Failed to decode deposit request event", err, map[string]interface{}{
+			"offset":       message.Offset,
+			"content_type": kafka.MessageContentType(message),
+			"trace_id":     messageTraceID(message),
 		})
 		return err
 	}
 
-	log.Printf("Processing deposit request: operation_id=%s, idempotency_key=%s, account_id=%d, amount=%d",
-		event.OperationID, event.IdempotencyKey, event.AccountID, event.Amount)
+	logging.Info("Processing deposit request", map[string]interface{}{
+		"operation_id":    event.OperationID,
+		"idempotency_key": event.IdempotencyKey,
+		"trace_id":        event.TraceID,
+		"account_id":      event.AccountID,
+		"amount":          event.Amount,
+	})
 
 	// Perform atomic deposit with idempotency check
 	// This is THE KEY OPERATION that makes the consumer idempotent!
-	acc, err := h.db.AtomicDepositWithIdempotency(event.AccountID, event.Amount, event.IdempotencyKey)
+	acc, err := h.db.AtomicDepositWithIdempotency(ctx, event.AccountID, event.Amount, event.IdempotencyKey)
 
 	if err != nil {
 		// Check if this is a duplicate operation (expected with at-least-once)
 		if errors.Is(err, postgres.ErrDuplicateOperation) {
-			log.Printf("Duplicate operation detected (idempotent): idempotency_key=%s, account_id=%d - skipping",
-				event.IdempotencyKey, event.AccountID)
+			// A redelivery of an operation already applied here - typically a retry after its
+			// completed event failed to publish - re-publishes that event from the stored result.
+			// A different operation reusing the key is only marked as a duplicate.
+			if alreadyCompleted(ctx, h.db, event.OperationID) {
+				logging.Info("Deposit already applied - re-publishing completed event", map[string]interface{}{
+					"operation_id":    event.OperationID,
+					"idempotency_key": event.IdempotencyKey,
+					"trace_id":        event.TraceID,
+					"account_id":      event.AccountID,
+				})
+				return h.publishCompleted(event, acc.Balance)
+			}
+
+			logging.Info("Duplicate operation detected (idempotent) - skipping", map[string]interface{}{
+				"idempotency_key": event.IdempotencyKey,
+				"trace_id":        event.TraceID,
+				"account_id":      event.AccountID,
+			})
 			metrics.RecordBankingOperation("deposit", "duplicate")
+			recordOutcome(h.db, event.OperationID, models.OperationStatusDuplicate, "")
 			return nil // Success! This is idempotent behavior
 		}
 
-		// Check if account doesn't exist
-		if errors.Is(err, postgres.ErrAccountNotFound) {
+		// Business failures are final - account doesn't exist, was closed or frozen, or would exceed its cap
+		if errors.Is(err, postgres.ErrAccountNotFound) || errors.Is(err, postgres.ErrAccountClosed) ||
+			errors.Is(err, postgres.ErrAccountFrozen) || errors.Is(err, postgres.ErrBalanceLimitExceeded) {
+			errorMessage, reason := "Account not found", FailureReasonAccountNotFound
+			switch {
+			case errors.Is(err, postgres.ErrAccountClosed):
+				errorMessage, reason = "Account closed", FailureReasonAccountClosed
+			case errors.Is(err, postgres.ErrAccountFrozen):
+				errorMessage, reason = "Account frozen", FailureReasonAccountFrozen
+			case errors.Is(err, postgres.ErrBalanceLimitExceeded):
+				errorMessage, reason = "Deposit would exceed the maximum account balance", FailureReasonBalanceLimitExceeded
+			}
+
 			// Publish transaction failed event
 			failedEvent := TransactionFailedEvent{
 				TransactionType: "deposit",
+				OperationID:     event.OperationID,
 				AccountID:       event.AccountID,
 				Amount:          event.Amount,
-				ErrorMessage:    "Account not found",
+				ErrorMessage:    errorMessage,
+				Reason:          reason,
+				TraceID:         event.TraceID,
 				Timestamp:       time.Now(),
 			}
 			if err := h.publisher.PublishTransactionFailed(failedEvent); err != nil {
 				logging.Error("Failed to publish transaction failed event", err, map[string]interface{}{
 					"operation_id": event.OperationID,
+					"trace_id":     event.TraceID,
 				})
 			}
+			logging.Warn("Deposit rejected", map[string]interface{}{
+				"operation_id": event.OperationID,
+				"trace_id":     event.TraceID,
+				"account_id":   event.AccountID,
+				"reason":       reason,
+			})
 			metrics.RecordBankingOperation("deposit", "error")
-			return nil // Don't retry - account doesn't exist
+			recordOutcome(h.db, event.OperationID, models.OperationStatusFailed, reason)
+			return nil // Don't retry - retrying can't change the outcome
 		}
 
 		// Real error - log and retry
 		logging.Error("Failed to process deposit", err, map[string]interface{}{
 			"operation_id":    event.OperationID,
 			"idempotency_key": event.IdempotencyKey,
+			"trace_id":        event.TraceID,
 			"account_id":      event.AccountID,
 		})
 		metrics.RecordBankingOperation("deposit", "error")
@@ -233,24 +472,19 @@ func (h *depositConsumerHandler) processDepositRequest(message *sarama.ConsumerM
 	// Record successful operation and metrics
 	metrics.RecordBankingOperation("deposit", "success")
 	metrics.RecordAccountBalance(float64(balance))
+	recordOutcome(h.db, event.OperationID, models.OperationStatusCompleted, "")
 
-	// Publish deposit completed event
-	completedEvent := DepositCompletedEvent{
-		AccountID:    event.AccountID,
-		Amount:       event.Amount,
-		BalanceAfter: balance,
-		Timestamp:    time.Now(),
-	}
-	if err := h.publisher.PublishDepositCompleted(completedEvent); err != nil {
-		logging.Error("Failed to publish deposit completed event", err, map[string]interface{}{
-			"operation_id": event.OperationID,
-			"account_id":   event.AccountID,
-		})
-		return err // Retry on publish failure
+	if err := h.publishCompleted(event, balance); err != nil {
+		return err // Retry on publish failure; the retry re-publishes from the stored result
 	}
 
-	log.Printf("Deposit processed successfully: operation_id=%s, idempotency_key=%s, account_id=%d, new_balance=%d",
-		event.OperationID, event.IdempotencyKey, event.AccountID, balance)
+	logging.Info("Deposit processed successfully", map[string]interface{}{
+		"operation_id":    event.OperationID,
+		"idempotency_key": event.IdempotencyKey,
+		"trace_id":        event.TraceID,
+		"account_id":      event.AccountID,
+		"new_balance":     balance,
+	})
 
 	return nil
 }
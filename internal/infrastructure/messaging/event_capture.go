@@ -6,11 +6,15 @@ import "sync"
 // It captures all published events and allows verification in tests
 type EventCapture struct {
 	accountCreated      []AccountCreatedEvent
+	accountClosed       []AccountClosedEvent
 	depositRequested    []DepositRequestedEvent
 	depositCompleted    []DepositCompletedEvent
+	withdrawalRequested []WithdrawalRequestedEvent
 	withdrawalCompleted []WithdrawalCompletedEvent
 	transferCompleted   []TransferCompletedEvent
+	interestApplied     []InterestAppliedEvent
 	transactionFailed   []TransactionFailedEvent
+	deadLetters         []DeadLetterEvent
 	mu                  sync.RWMutex
 }
 
@@ -18,24 +22,39 @@ type EventCapture struct {
 func NewEventCapture() *EventCapture {
 	return &EventCapture{
 		accountCreated:      make([]AccountCreatedEvent, 0),
+		accountClosed:       make([]AccountClosedEvent, 0),
 		depositRequested:    make([]DepositRequestedEvent, 0),
 		depositCompleted:    make([]DepositCompletedEvent, 0),
+		withdrawalRequested: make([]WithdrawalRequestedEvent, 0),
 		withdrawalCompleted: make([]WithdrawalCompletedEvent, 0),
 		transferCompleted:   make([]TransferCompletedEvent, 0),
+		interestApplied:     make([]InterestAppliedEvent, 0),
 		transactionFailed:   make([]TransactionFailedEvent, 0),
+		deadLetters:         make([]DeadLetterEvent, 0),
 	}
 }
 
 // PublishAccountCreated captures account created event
 func (e *EventCapture) PublishAccountCreated(event AccountCreatedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountCreated)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.accountCreated = append(e.accountCreated, event)
 	return nil
 }
 
+// PublishAccountClosed captures account closed event
+func (e *EventCapture) PublishAccountClosed(event AccountClosedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountClosed)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.accountClosed = append(e.accountClosed, event)
+	return nil
+}
+
 // PublishDepositRequested captures deposit requested event
 func (e *EventCapture) PublishDepositRequested(event DepositRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositRequested)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.depositRequested = append(e.depositRequested, event)
@@ -44,14 +63,25 @@ func (e *EventCapture) PublishDepositRequested(event DepositRequestedEvent) erro
 
 // PublishDepositCompleted captures deposit completed event
 func (e *EventCapture) PublishDepositCompleted(event DepositCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositCompleted)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.depositCompleted = append(e.depositCompleted, event)
 	return nil
 }
 
+// PublishWithdrawalRequested captures withdrawal requested event
+func (e *EventCapture) PublishWithdrawalRequested(event WithdrawalRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalRequested)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.withdrawalRequested = append(e.withdrawalRequested, event)
+	return nil
+}
+
 // PublishWithdrawalCompleted captures withdrawal completed event
 func (e *EventCapture) PublishWithdrawalCompleted(event WithdrawalCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalCompleted)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.withdrawalCompleted = append(e.withdrawalCompleted, event)
@@ -60,20 +90,40 @@ func (e *EventCapture) PublishWithdrawalCompleted(event WithdrawalCompletedEvent
 
 // PublishTransferCompleted captures transfer completed event
 func (e *EventCapture) PublishTransferCompleted(event TransferCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransferCompleted)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.transferCompleted = append(e.transferCompleted, event)
 	return nil
 }
 
+// PublishInterestApplied captures interest applied event
+func (e *EventCapture) PublishInterestApplied(event InterestAppliedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeInterestApplied)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.interestApplied = append(e.interestApplied, event)
+	return nil
+}
+
 // PublishTransactionFailed captures transaction failed event
 func (e *EventCapture) PublishTransactionFailed(event TransactionFailedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransactionFailed)
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.transactionFailed = append(e.transactionFailed, event)
 	return nil
 }
 
+// PublishDeadLetter captures dead-letter event
+func (e *EventCapture) PublishDeadLetter(event DeadLetterEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDeadLetter)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deadLetters = append(e.deadLetters, event)
+	return nil
+}
+
 // Close is a no-op for event capture
 func (e *EventCapture) Close() error {
 	return nil
@@ -94,6 +144,15 @@ func (e *EventCapture) GetAccountCreatedEvents() []AccountCreatedEvent {
 	return events
 }
 
+// GetAccountClosedEvents returns all captured account closed events
+func (e *EventCapture) GetAccountClosedEvents() []AccountClosedEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	events := make([]AccountClosedEvent, len(e.accountClosed))
+	copy(events, e.accountClosed)
+	return events
+}
+
 // GetDepositRequestedEvents returns all captured deposit requested events
 func (e *EventCapture) GetDepositRequestedEvents() []DepositRequestedEvent {
 	e.mu.RLock()
@@ -112,6 +171,15 @@ func (e *EventCapture) GetDepositCompletedEvents() []DepositCompletedEvent {
 	return events
 }
 
+// GetWithdrawalRequestedEvents returns all captured withdrawal requested events
+func (e *EventCapture) GetWithdrawalRequestedEvents() []WithdrawalRequestedEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	events := make([]WithdrawalRequestedEvent, len(e.withdrawalRequested))
+	copy(events, e.withdrawalRequested)
+	return events
+}
+
 // GetWithdrawalCompletedEvents returns all captured withdrawal completed events
 func (e *EventCapture) GetWithdrawalCompletedEvents() []WithdrawalCompletedEvent {
 	e.mu.RLock()
@@ -130,6 +198,15 @@ func (e *EventCapture) GetTransferCompletedEvents() []TransferCompletedEvent {
 	return events
 }
 
+// GetInterestAppliedEvents returns all captured interest applied events
+func (e *EventCapture) GetInterestAppliedEvents() []InterestAppliedEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	events := make([]InterestAppliedEvent, len(e.interestApplied))
+	copy(events, e.interestApplied)
+	return events
+}
+
 // GetTransactionFailedEvents returns all captured transaction failed events
 func (e *EventCapture) GetTransactionFailedEvents() []TransactionFailedEvent {
 	e.mu.RLock()
@@ -139,23 +216,37 @@ func (e *EventCapture) GetTransactionFailedEvents() []TransactionFailedEvent {
 	return events
 }
 
+// GetDeadLetterEvents returns all captured dead-letter events
+func (e *EventCapture) GetDeadLetterEvents() []DeadLetterEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	events := make([]DeadLetterEvent, len(e.deadLetters))
+	copy(events, e.deadLetters)
+	return events
+}
+
 // Reset clears all captured events (useful between tests)
 func (e *EventCapture) Reset() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.accountCreated = make([]AccountCreatedEvent, 0)
+	e.accountClosed = make([]AccountClosedEvent, 0)
 	e.depositRequested = make([]DepositRequestedEvent, 0)
 	e.depositCompleted = make([]DepositCompletedEvent, 0)
+	e.withdrawalRequested = make([]WithdrawalRequestedEvent, 0)
 	e.withdrawalCompleted = make([]WithdrawalCompletedEvent, 0)
 	e.transferCompleted = make([]TransferCompletedEvent, 0)
+	e.interestApplied = make([]InterestAppliedEvent, 0)
 	e.transactionFailed = make([]TransactionFailedEvent, 0)
+	e.deadLetters = make([]DeadLetterEvent, 0)
 }
 
 // GetEventCount returns the total number of events captured
 func (e *EventCapture) GetEventCount() int {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	return len(e.accountCreated) + len(e.depositRequested) +
-		len(e.depositCompleted) + len(e.withdrawalCompleted) +
-		len(e.transferCompleted) + len(e.transactionFailed)
+	return len(e.accountCreated) + len(e.accountClosed) + len(e.depositRequested) +
+		len(e.depositCompleted) + len(e.withdrawalRequested) + len(e.withdrawalCompleted) +
+		len(e.transferCompleted) + len(e.interestApplied) + len(e.transactionFailed) +
+		len(e.deadLetters)
 }
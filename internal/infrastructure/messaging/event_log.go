@@ -0,0 +1,144 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/logging"
+)
+
+// EventLogEntry is one line of the event log file
+type EventLogEntry struct {
+	Topic     string          `json:"topic"`
+	Timestamp time.Time       `json:"timestamp"`
+	Event     json.RawMessage `json:"event"`
+	Error     string          `json:"error,omitempty"` // Set when the wrapped publisher failed
+}
+
+// EventLogPublisher wraps an EventPublisher and appends every event it publishes to a file,
+// one JSON line per event. It's meant for following async flows in development, including
+// with Kafka disabled; failing to write the log never fails the publish.
+type EventLogPublisher struct {
+	next EventPublisher
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewEventLogPublisher wraps next, appending to the file at path (created if missing)
+func NewEventLogPublisher(next EventPublisher, path string) (*EventLogPublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	return &EventLogPublisher{
+		next: next,
+		file: file,
+	}, nil
+}
+
+// record forwards the result of publishing event to topic and appends it to the log
+func (p *EventLogPublisher) record(topic string, event interface{}, publishErr error) error {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		entry := EventLogEntry{Topic: topic, Timestamp: time.Now().UTC(), Event: payload}
+		if publishErr != nil {
+			entry.Error = publishErr.Error()
+		}
+		var line []byte
+		line, err = json.Marshal(entry)
+		if err == nil {
+			p.mu.Lock()
+			_, err = p.file.Write(append(line, '\n'))
+			p.mu.Unlock()
+		}
+	}
+	if err != nil {
+		logging.Error("Failed to write event log", err, map[string]interface{}{
+			"topic": topic,
+		})
+	}
+
+	return publishErr
+}
+
+// PublishAccountCreated publishes and logs an account created event
+func (p *EventLogPublisher) PublishAccountCreated(event AccountCreatedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountCreated)
+	return p.record(kafka.TopicAccountCreated, event, p.next.PublishAccountCreated(event))
+}
+
+// PublishAccountClosed publishes and logs an account closed event
+func (p *EventLogPublisher) PublishAccountClosed(event AccountClosedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountClosed)
+	return p.record(kafka.TopicAccountClosed, event, p.next.PublishAccountClosed(event))
+}
+
+// PublishDepositRequested publishes and logs a deposit request command
+func (p *EventLogPublisher) PublishDepositRequested(event DepositRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositRequested)
+	return p.record(kafka.TopicDepositRequests, event, p.next.PublishDepositRequested(event))
+}
+
+// PublishDepositCompleted publishes and logs a deposit completed event
+func (p *EventLogPublisher) PublishDepositCompleted(event DepositCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositCompleted)
+	return p.record(kafka.TopicTransactionDeposit, event, p.next.PublishDepositCompleted(event))
+}
+
+// PublishWithdrawalRequested publishes and logs a withdrawal request command
+func (p *EventLogPublisher) PublishWithdrawalRequested(event WithdrawalRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalRequested)
+	return p.record(kafka.TopicWithdrawalRequests, event, p.next.PublishWithdrawalRequested(event))
+}
+
+// PublishWithdrawalCompleted publishes and logs a withdrawal completed event
+func (p *EventLogPublisher) PublishWithdrawalCompleted(event WithdrawalCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalCompleted)
+	return p.record(kafka.TopicTransactionWithdrawal, event, p.next.PublishWithdrawalCompleted(event))
+}
+
+// PublishTransferCompleted publishes and logs a transfer completed event
+func (p *EventLogPublisher) PublishTransferCompleted(event TransferCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransferCompleted)
+	return p.record(kafka.TopicTransactionTransfer, event, p.next.PublishTransferCompleted(event))
+}
+
+// PublishInterestApplied publishes and logs an interest applied event
+func (p *EventLogPublisher) PublishInterestApplied(event InterestAppliedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeInterestApplied)
+	return p.record(kafka.TopicTransactionInterest, event, p.next.PublishInterestApplied(event))
+}
+
+// PublishTransactionFailed publishes and logs a transaction failed event
+func (p *EventLogPublisher) PublishTransactionFailed(event TransactionFailedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransactionFailed)
+	return p.record(kafka.TopicTransactionFailed, event, p.next.PublishTransactionFailed(event))
+}
+
+// PublishDeadLetter publishes and logs a dead-lettered message
+func (p *EventLogPublisher) PublishDeadLetter(event DeadLetterEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDeadLetter)
+	return p.record(kafka.DeadLetterTopic(event.OriginalTopic), event, p.next.PublishDeadLetter(event))
+}
+
+// Close closes the wrapped publisher and the log file
+func (p *EventLogPublisher) Close() error {
+	err := p.next.Close()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if closeErr := p.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// IsHealthy reports the health of the wrapped publisher
+func (p *EventLogPublisher) IsHealthy() bool {
+	return p.next.IsHealthy()
+}
@@ -2,8 +2,52 @@ package messaging
 
 import "time"
 
+// CurrentEventVersion is the schema version stamped on every published event.
+// Bump it when an event's fields change in a way older consumers can't read.
+const CurrentEventVersion = "1"
+
+// Event types stamped on published events
+const (
+	EventTypeAccountCreated      = "account.created"
+	EventTypeAccountClosed       = "account.closed"
+	EventTypeDepositRequested    = "deposit.requested"
+	EventTypeDepositCompleted    = "deposit.completed"
+	EventTypeWithdrawalRequested = "withdrawal.requested"
+	EventTypeWithdrawalCompleted = "withdrawal.completed"
+	EventTypeTransferCompleted   = "transfer.completed"
+	EventTypeInterestApplied     = "interest.applied"
+	EventTypeTransactionFailed   = "transaction.failed"
+	EventTypeDeadLetter          = "dead_letter"
+)
+
+// EventMetadata is embedded in every event and populated by the publisher,
+// so consumers can detect schema changes instead of misreading payloads
+type EventMetadata struct {
+	EventVersion string `json:"event_version"`
+	EventType    string `json:"event_type"`
+}
+
+// newEventMetadata returns the metadata for an event of the given type at the current version
+func newEventMetadata(eventType string) EventMetadata {
+	return EventMetadata{
+		EventVersion: CurrentEventVersion,
+		EventType:    eventType,
+	}
+}
+
 // AccountCreatedEvent represents an account creation event
 type AccountCreatedEvent struct {
+	EventMetadata
+
+	AccountID int       `json:"account_id"`
+	Owner     string    `json:"owner"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AccountClosedEvent represents an account closure event
+type AccountClosedEvent struct {
+	EventMetadata
+
 	AccountID int       `json:"account_id"`
 	Owner     string    `json:"owner"`
 	Timestamp time.Time `json:"timestamp"`
@@ -11,46 +55,120 @@ type AccountCreatedEvent struct {
 
 // DepositRequestedEvent represents a deposit command request
 type DepositRequestedEvent struct {
+	EventMetadata
+
 	OperationID    string    `json:"operation_id"`    // UUID for tracking (legacy)
 	IdempotencyKey string    `json:"idempotency_key"` // SHA-256 hash for deduplication
 	AccountID      int       `json:"account_id"`
-	Amount         int       `json:"amount"` // in cents
+	Amount         int       `json:"amount"`                 // in cents
+	TraceID        string    `json:"trace_id,omitempty"`     // X-Trace-Id of the originating HTTP request
+	CallbackURL    string    `json:"callback_url,omitempty"` // Receives a POST once the deposit completes
 	Timestamp      time.Time `json:"timestamp"`
 }
 
 // DepositCompletedEvent represents a successful deposit
 type DepositCompletedEvent struct {
+	EventMetadata
+
+	OperationID  string    `json:"operation_id,omitempty"` // From the originating DepositRequestedEvent
 	AccountID    int       `json:"account_id"`
-	Amount       int       `json:"amount"`        // in cents
-	BalanceAfter int       `json:"balance_after"` // in cents
+	Amount       int       `json:"amount"`             // in cents
+	BalanceAfter int       `json:"balance_after"`      // in cents
+	TraceID      string    `json:"trace_id,omitempty"` // From the originating DepositRequestedEvent
 	Timestamp    time.Time `json:"timestamp"`
+	Replay       bool      `json:"replay,omitempty"` // Re-emitted from the transaction log by ReplayTransactions
+	CallbackURL  string    `json:"-"`                // From the originating DepositRequestedEvent; never published
+}
+
+// WithdrawalRequestedEvent represents a withdrawal command request
+type WithdrawalRequestedEvent struct {
+	EventMetadata
+
+	OperationID    string    `json:"operation_id"`    // UUID for tracking
+	IdempotencyKey string    `json:"idempotency_key"` // SHA-256 hash for deduplication
+	AccountID      int       `json:"account_id"`
+	Amount         int       `json:"amount"`             // in cents
+	TraceID        string    `json:"trace_id,omitempty"` // X-Trace-Id of the originating HTTP request
+	Timestamp      time.Time `json:"timestamp"`
 }
 
 // WithdrawalCompletedEvent represents a successful withdrawal
 type WithdrawalCompletedEvent struct {
+	EventMetadata
+
+	OperationID  string    `json:"operation_id,omitempty"` // From the originating WithdrawalRequestedEvent
 	AccountID    int       `json:"account_id"`
+	Amount       int       `json:"amount"`             // in cents
+	BalanceAfter int       `json:"balance_after"`      // in cents
+	TraceID      string    `json:"trace_id,omitempty"` // From the originating WithdrawalRequestedEvent
+	Timestamp    time.Time `json:"timestamp"`
+	Replay       bool      `json:"replay,omitempty"` // Re-emitted from the transaction log by ReplayTransactions
+}
+
+// InterestAppliedEvent represents interest credited to an account by the scheduled accrual
+type InterestAppliedEvent struct {
+	EventMetadata
+
+	AccountID    int       `json:"account_id"`
+	Rate         float64   `json:"rate"`
 	Amount       int       `json:"amount"`        // in cents
 	BalanceAfter int       `json:"balance_after"` // in cents
 	Timestamp    time.Time `json:"timestamp"`
+	Replay       bool      `json:"replay,omitempty"` // Re-emitted from the transaction log by ReplayTransactions
 }
 
 // TransferCompletedEvent represents a successful transfer
 type TransferCompletedEvent struct {
+	EventMetadata
+
 	FromAccountID    int       `json:"from_account_id"`
 	ToAccountID      int       `json:"to_account_id"`
 	Amount           int       `json:"amount"`             // in cents
 	FromBalanceAfter int       `json:"from_balance_after"` // in cents
 	ToBalanceAfter   int       `json:"to_balance_after"`   // in cents
 	Timestamp        time.Time `json:"timestamp"`
+	Replay           bool      `json:"replay,omitempty"` // Re-emitted from the transaction log by ReplayTransactions
 }
 
 // TransactionFailedEvent represents a failed transaction for audit trail
 type TransactionFailedEvent struct {
-	TransactionType string    `json:"transaction_type"` // deposit, withdrawal, transfer
+	EventMetadata
+
+	TransactionType string    `json:"transaction_type"`       // deposit, withdrawal, transfer
+	OperationID     string    `json:"operation_id,omitempty"` // From the originating request event, when it had one
 	AccountID       int       `json:"account_id,omitempty"`
 	FromAccountID   int       `json:"from_account_id,omitempty"`
 	ToAccountID     int       `json:"to_account_id,omitempty"`
 	Amount          int       `json:"amount"` // in cents
 	ErrorMessage    string    `json:"error_message"`
+	Reason          string    `json:"reason,omitempty"`   // one of the FailureReason values
+	TraceID         string    `json:"trace_id,omitempty"` // From the originating request event, when it had one
 	Timestamp       time.Time `json:"timestamp"`
 }
+
+// Machine-readable TransactionFailedEvent reasons, also recorded on failed operations
+const (
+	FailureReasonAccountNotFound         = "account_not_found"
+	FailureReasonAccountClosed           = "account_closed"
+	FailureReasonInsufficientFunds       = "insufficient_funds"
+	FailureReasonBalanceLimitExceeded    = "balance_limit_exceeded"
+	FailureReasonWithdrawalLimitExceeded = "withdrawal_limit_exceeded"
+	FailureReasonAccountFrozen           = "account_frozen"
+	FailureReasonDeadLettered            = "dead_lettered"  // operation only: retries exhausted or unreadable message
+	FailureReasonPublishFailed           = "publish_failed" // operation only: the request never reached Kafka
+)
+
+// DeadLetterEvent wraps a message that could not be processed and was routed to a DLQ
+type DeadLetterEvent struct {
+	EventMetadata
+
+	OriginalTopic string    `json:"original_topic"`
+	Partition     int32     `json:"partition"`
+	Offset        int64     `json:"offset"`
+	Key           string    `json:"key,omitempty"`
+	Payload       string    `json:"payload"`                // raw message value, base64-encoded unless ContentType is JSON
+	ContentType   string    `json:"content_type,omitempty"` // content-type header of the original message
+	ErrorMessage  string    `json:"error_message"`
+	Attempts      int       `json:"attempts"`
+	Timestamp     time.Time `json:"timestamp"`
+}
@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/IBM/sarama"
+)
+
+// EnsureTopics creates every banking topic (see GetAllTopics) that doesn't exist yet, with the
+// configured partition count and replication factor. Existing topics are left untouched, so it
+// is safe to run on every startup and from several replicas at once.
+func EnsureTopics(config *Config) error {
+	if config.TopicPartitions < 1 || config.TopicReplicationFactor < 1 {
+		return fmt.Errorf("invalid topic layout: partitions=%d, replication factor=%d",
+			config.TopicPartitions, config.TopicReplicationFactor)
+	}
+
+	saramaConfig, err := config.ToSaramaConfig()
+	if err != nil {
+		return err
+	}
+	// The admin client never produces, so the idempotent producer settings don't apply
+	saramaConfig.Producer.Idempotent = false
+
+	admin, err := sarama.NewClusterAdmin(config.Brokers, saramaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kafka admin client: %w", err)
+	}
+	defer admin.Close()
+
+	detail := &sarama.TopicDetail{
+		NumPartitions:     int32(config.TopicPartitions),
+		ReplicationFactor: int16(config.TopicReplicationFactor),
+	}
+	for _, topic := range GetAllTopics() {
+		err := admin.CreateTopic(topic, detail, false)
+		if errors.Is(err, sarama.ErrTopicAlreadyExists) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create topic %s: %w", topic, err)
+		}
+		log.Printf("Kafka topic created: %s (partitions=%d, replication=%d)", topic, detail.NumPartitions, detail.ReplicationFactor)
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package kafka
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -9,7 +12,18 @@ import (
 	"github.com/IBM/sarama"
 )
 
-// Config holds Kafka producer configuration
+// Partition key strategies for deposit request commands (see Config.PartitionKey).
+// Keying by account ID keeps each account's deposits in order but puts a very active account
+// on a single partition. Keying by operation ID or leaving the key empty for round-robin
+// spreads the load evenly and gives up that ordering, which is safe for deposits: the consumer
+// is idempotent and deposits commute.
+const (
+	PartitionKeyAccountID   = "account-id"
+	PartitionKeyOperationID = "operation-id"
+	PartitionKeyRoundRobin  = "round-robin"
+)
+
+// Config holds Kafka producer and consumer configuration
 type Config struct {
 	Brokers           []string
 	ClientID          string
@@ -18,6 +32,52 @@ type Config struct {
 	RequiredAcks      string
 	MaxRetries        int
 	RetryBackoff      time.Duration
+
+	// How deposit requests are keyed: account-id (default), operation-id or round-robin.
+	// Withdrawal requests always stay keyed by account, since their outcome depends on order.
+	PartitionKeyStrategy string
+
+	// Wire format of published events: json (default) or protobuf
+	EventSerialization string
+
+	// Create missing topics on startup (see EnsureTopics) with this many partitions and replicas,
+	// instead of relying on the broker's auto-creation settings
+	AutoCreateTopics       bool
+	TopicPartitions        int
+	TopicReplicationFactor int
+
+	// Consumer processing retries before a message is sent to the dead-letter topic
+	ConsumerMaxRetries   int
+	ConsumerRetryBackoff time.Duration
+
+	// Consumer offsets are committed every ConsumerCommitBatchSize messages or every
+	// ConsumerCommitInterval, whichever comes first
+	ConsumerCommitBatchSize int
+	ConsumerCommitInterval  time.Duration
+
+	// Wait between failed consumer group sessions, doubling from ConsumerReconnectBackoff
+	// up to ConsumerReconnectMaxBackoff while the broker stays unavailable
+	ConsumerReconnectBackoff    time.Duration
+	ConsumerReconnectMaxBackoff time.Duration
+
+	// Static group membership (group.instance.id). With a stable ID per replica, such as the
+	// pod name, a consumer that restarts within the session timeout gets its partitions back
+	// without a rebalance of the whole group. Must be unique per replica; empty (the default)
+	// keeps dynamic membership.
+	ConsumerInstanceID string
+
+	// TLS to the brokers; the CA file replaces the system roots, and the cert/key pair is
+	// presented for mutual TLS. All paths are optional.
+	TLSEnable   bool
+	TLSCAFile   string
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// SASL authentication: empty (none, the default), PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512.
+	// PLAIN sends the password as-is, so it should only be used together with TLS.
+	SASLMechanism string
+	SASLUsername  string
+	SASLPassword  string
 }
 
 // NewConfigFromEnv creates Kafka config from environment variables
@@ -33,6 +93,33 @@ func NewConfigFromEnv() *Config {
 		RequiredAcks:      getEnv("KAFKA_REQUIRED_ACKS", "1"), // Wait for leader only (changed from "all")
 		MaxRetries:        getEnvInt("KAFKA_MAX_RETRIES", 5),
 		RetryBackoff:      getEnvDuration("KAFKA_RETRY_BACKOFF", 100*time.Millisecond),
+
+		PartitionKeyStrategy: getEnv("KAFKA_PARTITION_KEY_STRATEGY", PartitionKeyAccountID),
+		EventSerialization:   getEnv("KAFKA_EVENT_SERIALIZATION", SerializationJSON),
+
+		AutoCreateTopics:       getEnvBool("KAFKA_AUTO_CREATE_TOPICS", false),
+		TopicPartitions:        getEnvInt("KAFKA_TOPIC_PARTITIONS", 3),
+		TopicReplicationFactor: getEnvInt("KAFKA_TOPIC_REPLICATION_FACTOR", 1),
+
+		ConsumerMaxRetries:   getEnvInt("KAFKA_CONSUMER_MAX_RETRIES", 3),
+		ConsumerRetryBackoff: getEnvDuration("KAFKA_CONSUMER_RETRY_BACKOFF", 500*time.Millisecond),
+
+		ConsumerCommitBatchSize: getEnvInt("KAFKA_CONSUMER_COMMIT_BATCH_SIZE", 100),
+		ConsumerCommitInterval:  getEnvDuration("KAFKA_CONSUMER_COMMIT_INTERVAL", time.Second),
+
+		ConsumerReconnectBackoff:    getEnvDuration("KAFKA_CONSUMER_RECONNECT_BACKOFF", 100*time.Millisecond),
+		ConsumerReconnectMaxBackoff: getEnvDuration("KAFKA_CONSUMER_RECONNECT_MAX_BACKOFF", 30*time.Second),
+
+		ConsumerInstanceID: os.Getenv("KAFKA_CONSUMER_INSTANCE_ID"),
+
+		TLSEnable:   getEnvBool("KAFKA_TLS_ENABLE", false),
+		TLSCAFile:   os.Getenv("KAFKA_TLS_CA_FILE"),
+		TLSCertFile: os.Getenv("KAFKA_TLS_CERT_FILE"),
+		TLSKeyFile:  os.Getenv("KAFKA_TLS_KEY_FILE"),
+
+		SASLMechanism: os.Getenv("KAFKA_SASL_MECHANISM"),
+		SASLUsername:  os.Getenv("KAFKA_SASL_USERNAME"),
+		SASLPassword:  os.Getenv("KAFKA_SASL_PASSWORD"),
 	}
 }
 
@@ -80,6 +167,32 @@ func (c *Config) ToSaramaConfig() (*sarama.Config, error) {
 		return nil, fmt.Errorf("invalid compression type: %s", c.CompressionType)
 	}
 
+	switch c.PartitionKeyStrategy {
+	case "", PartitionKeyAccountID, PartitionKeyOperationID, PartitionKeyRoundRobin:
+	default:
+		return nil, fmt.Errorf("invalid partition key strategy: %s", c.PartitionKeyStrategy)
+	}
+
+	switch c.EventSerialization {
+	case "", SerializationJSON, SerializationProtobuf:
+	default:
+		return nil, fmt.Errorf("invalid event serialization: %s", c.EventSerialization)
+	}
+
+	// Keyed messages are hashed as usual; unkeyed ones (round-robin strategy) are spread evenly
+	config.Producer.Partitioner = newKeyOrRoundRobinPartitioner
+
+	// Security (plaintext unless TLS and/or SASL are configured)
+	if err := c.configureTLS(config); err != nil {
+		return nil, err
+	}
+	if err := c.configureSASL(config); err != nil {
+		return nil, err
+	}
+
+	// Static membership; only used by consumer groups
+	config.Consumer.Group.InstanceId = c.ConsumerInstanceID
+
 	// Client ID
 	config.ClientID = c.ClientID
 
@@ -89,6 +202,114 @@ func (c *Config) ToSaramaConfig() (*sarama.Config, error) {
 	return config, nil
 }
 
+// configureTLS enables TLS to the brokers when TLSEnable is set
+func (c *Config) configureTLS(config *sarama.Config) error {
+	if !c.TLSEnable {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read Kafka TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("no certificates found in Kafka TLS CA file: %s", c.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("kafka TLS client certificate and key must be set together")
+	}
+	if c.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load Kafka TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	config.Net.TLS.Enable = true
+	config.Net.TLS.Config = tlsConfig
+	return nil
+}
+
+// configureSASL enables SASL authentication when a mechanism is set
+func (c *Config) configureSASL(config *sarama.Config) error {
+	if c.SASLMechanism == "" {
+		return nil
+	}
+
+	mechanism := sarama.SASLMechanism(strings.ToUpper(c.SASLMechanism))
+	switch mechanism {
+	case sarama.SASLTypePlaintext:
+	case sarama.SASLTypeSCRAMSHA256:
+		config.Net.SASL.SCRAMClientGeneratorFunc = newSCRAMSHA256Client
+	case sarama.SASLTypeSCRAMSHA512:
+		config.Net.SASL.SCRAMClientGeneratorFunc = newSCRAMSHA512Client
+	default:
+		return fmt.Errorf("invalid SASL mechanism: %s", c.SASLMechanism)
+	}
+
+	if c.SASLUsername == "" {
+		return fmt.Errorf("SASL mechanism %s requires a username", mechanism)
+	}
+
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Mechanism = mechanism
+	config.Net.SASL.User = c.SASLUsername
+	config.Net.SASL.Password = c.SASLPassword
+	config.Net.SASL.Handshake = true
+	return nil
+}
+
+// PartitionKey returns the message key for a deposit request under the configured strategy.
+// An empty key leaves the partition to the round-robin partitioner.
+func (c *Config) PartitionKey(accountID, operationID string) string {
+	switch c.PartitionKeyStrategy {
+	case PartitionKeyOperationID:
+		return operationID
+	case PartitionKeyRoundRobin:
+		return ""
+	default:
+		return accountID
+	}
+}
+
+// keyOrRoundRobinPartitioner hashes keyed messages like sarama's default partitioner, but sends
+// unkeyed ones round-robin instead of to a random partition
+type keyOrRoundRobinPartitioner struct {
+	hash       sarama.Partitioner
+	roundRobin sarama.Partitioner
+}
+
+func newKeyOrRoundRobinPartitioner(topic string) sarama.Partitioner {
+	return &keyOrRoundRobinPartitioner{
+		hash:       sarama.NewHashPartitioner(topic),
+		roundRobin: sarama.NewRoundRobinPartitioner(topic),
+	}
+}
+
+func (p *keyOrRoundRobinPartitioner) Partition(message *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if message.Key == nil {
+		return p.roundRobin.Partition(message, numPartitions)
+	}
+	return p.hash.Partition(message, numPartitions)
+}
+
+func (p *keyOrRoundRobinPartitioner) RequiresConsistency() bool {
+	return true
+}
+
+// MessageRequiresConsistency lets unkeyed messages move to another partition when theirs is unavailable
+func (p *keyOrRoundRobinPartitioner) MessageRequiresConsistency(message *sarama.ProducerMessage) bool {
+	return message.Key != nil
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
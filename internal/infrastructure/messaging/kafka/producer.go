@@ -1,20 +1,35 @@
 package kafka
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
+
+	"bank-api/internal/pkg/telemetry"
 
 	"github.com/IBM/sarama"
 )
 
 // Producer wraps Kafka producer for event publishing
 type Producer struct {
-	producer sarama.SyncProducer
-	config   *Config
-	mu       sync.RWMutex
-	closed   bool
+	producer   sarama.SyncProducer
+	config     *Config
+	serializer Serializer
+	mu         sync.RWMutex
+	closed     bool
+
+	successCount atomic.Int64
+	errorCount   atomic.Int64
+	droppedCount atomic.Int64
+}
+
+// ProducerMetrics is a snapshot of the producer's publish counters
+type ProducerMetrics struct {
+	SuccessCount int64
+	ErrorCount   int64
+	DroppedCount int64
+	ErrorRate    float64 // (errors + dropped) / attempts
 }
 
 // NewProducer creates a new Kafka producer
@@ -31,10 +46,22 @@ func NewProducer(config *Config) (*Producer, error) {
 
 	log.Printf("Kafka producer initialized: brokers=%v, client_id=%s", config.Brokers, config.ClientID)
 
+	return NewProducerWithClient(producer, config), nil
+}
+
+// NewProducerWithClient wraps an existing sarama.SyncProducer.
+// Exposed so tests can supply a mock producer.
+func NewProducerWithClient(producer sarama.SyncProducer, config *Config) *Producer {
 	return &Producer{
-		producer: producer,
-		config:   config,
-	}, nil
+		producer:   producer,
+		config:     config,
+		serializer: JSONSerializer{},
+	}
+}
+
+// SetSerializer changes how events are encoded; call it before publishing
+func (p *Producer) SetSerializer(serializer Serializer) {
+	p.serializer = serializer
 }
 
 // PublishEvent publishes an event to a Kafka topic
@@ -42,34 +69,78 @@ func (p *Producer) PublishEvent(topic string, key string, event interface{}) err
 	p.mu.RLock()
 	if p.closed {
 		p.mu.RUnlock()
+		p.recordOutcome(topic, "dropped")
 		return fmt.Errorf("producer is closed")
 	}
 	p.mu.RUnlock()
 
-	// Serialize event to JSON
-	eventJSON, err := json.Marshal(event)
+	// Serialize event (JSON unless a binary serializer was configured)
+	payload, contentType, err := p.serializer.Serialize(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		p.recordOutcome(topic, "dropped")
+		return err
 	}
 
-	// Create Kafka message
+	// Create Kafka message; an empty key is sent as no key so the partitioner spreads it
 	msg := &sarama.ProducerMessage{
 		Topic: topic,
-		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(eventJSON),
+		Value: sarama.ByteEncoder(payload),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(contentTypeHeader), Value: []byte(contentType)},
+		},
+	}
+	if key != "" {
+		msg.Key = sarama.StringEncoder(key)
 	}
 
 	// Send message (synchronous)
 	partition, offset, err := p.producer.SendMessage(msg)
 	if err != nil {
 		log.Printf("Failed to publish event to Kafka: topic=%s, key=%s, error=%v", topic, key, err)
+		p.recordOutcome(topic, "error")
 		return fmt.Errorf("failed to send message to kafka: %w", err)
 	}
 
+	p.recordOutcome(topic, "success")
+
 	log.Printf("Event published to Kafka: topic=%s, partition=%d, offset=%d, key=%s", topic, partition, offset, key)
 	return nil
 }
 
+// PartitionKey returns the message key for a deposit request under the configured strategy
+func (p *Producer) PartitionKey(accountID, operationID string) string {
+	return p.config.PartitionKey(accountID, operationID)
+}
+
+// recordOutcome updates the local counters and the Prometheus metrics for one publish attempt
+func (p *Producer) recordOutcome(topic, status string) {
+	switch status {
+	case "success":
+		p.successCount.Add(1)
+	case "error":
+		p.errorCount.Add(1)
+	case "dropped":
+		p.droppedCount.Add(1)
+	}
+
+	metrics.RecordKafkaPublish(topic, status)
+	metrics.UpdateKafkaProducerErrorRate(p.GetMetrics().ErrorRate)
+}
+
+// GetMetrics returns a snapshot of the producer's publish counters
+func (p *Producer) GetMetrics() ProducerMetrics {
+	m := ProducerMetrics{
+		SuccessCount: p.successCount.Load(),
+		ErrorCount:   p.errorCount.Load(),
+		DroppedCount: p.droppedCount.Load(),
+	}
+
+	if attempts := m.SuccessCount + m.ErrorCount + m.DroppedCount; attempts > 0 {
+		m.ErrorRate = float64(m.ErrorCount+m.DroppedCount) / float64(attempts)
+	}
+	return m
+}
+
 // Close closes the Kafka producer
 func (p *Producer) Close() error {
 	p.mu.Lock()
@@ -0,0 +1,165 @@
+package kafka
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// scramClient implements the client side of SCRAM (RFC 5802) for sarama's SASL handshake.
+// Channel binding is not supported ("n,," GS2 header), matching what Kafka brokers expect.
+type scramClient struct {
+	newHash func() hash.Hash
+
+	username string
+	password string
+	authzID  string
+
+	clientNonce     string
+	clientFirstBare string
+	serverSignature []byte
+	step            int
+	done            bool
+}
+
+func newSCRAMSHA256Client() sarama.SCRAMClient {
+	return &scramClient{newHash: sha256.New}
+}
+
+func newSCRAMSHA512Client() sarama.SCRAMClient {
+	return &scramClient{newHash: sha512.New}
+}
+
+// Begin prepares a new conversation; sarama creates a fresh client per broker connection
+func (c *scramClient) Begin(username, password, authzID string) error {
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate SCRAM nonce: %w", err)
+	}
+
+	c.username, c.password, c.authzID = username, password, authzID
+	c.clientNonce = base64.RawStdEncoding.EncodeToString(nonce)
+	c.step, c.done = 0, false
+	return nil
+}
+
+// Step returns the client-first message, then the client-final message in response to the
+// server-first challenge, and finally verifies the server's signature
+func (c *scramClient) Step(challenge string) (string, error) {
+	c.step++
+	switch c.step {
+	case 1:
+		c.clientFirstBare = "n=" + escapeSCRAMName(c.username) + ",r=" + c.clientNonce
+		return c.gs2Header() + c.clientFirstBare, nil
+	case 2:
+		return c.clientFinal(challenge)
+	case 3:
+		return "", c.verifyServerFinal(challenge)
+	default:
+		return "", errors.New("SCRAM conversation already finished")
+	}
+}
+
+// Done reports whether the server's signature has been verified
+func (c *scramClient) Done() bool {
+	return c.done
+}
+
+func (c *scramClient) clientFinal(serverFirst string) (string, error) {
+	attrs := parseSCRAMAttributes(serverFirst)
+	if msg, ok := attrs["e"]; ok {
+		return "", fmt.Errorf("SCRAM server error: %s", msg)
+	}
+
+	nonce, salt64, iterStr := attrs["r"], attrs["s"], attrs["i"]
+	if !strings.HasPrefix(nonce, c.clientNonce) || len(nonce) == len(c.clientNonce) {
+		return "", errors.New("SCRAM server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil || len(salt) == 0 {
+		return "", errors.New("SCRAM server sent an invalid salt")
+	}
+	iterations, err := strconv.Atoi(iterStr)
+	if err != nil || iterations < 1 {
+		return "", errors.New("SCRAM server sent an invalid iteration count")
+	}
+
+	saltedPassword, err := pbkdf2.Key(c.newHash, c.password, salt, iterations, c.newHash().Size())
+	if err != nil {
+		return "", fmt.Errorf("failed to derive SCRAM salted password: %w", err)
+	}
+
+	clientFinalWithoutProof := "c=" + base64.StdEncoding.EncodeToString([]byte(c.gs2Header())) + ",r=" + nonce
+	authMessage := []byte(c.clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof)
+
+	clientKey := c.hmac(saltedPassword, []byte("Client Key"))
+	storedKey := c.newHash()
+	storedKey.Write(clientKey)
+	clientSignature := c.hmac(storedKey.Sum(nil), authMessage)
+
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+
+	serverKey := c.hmac(saltedPassword, []byte("Server Key"))
+	c.serverSignature = c.hmac(serverKey, authMessage)
+
+	return clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (c *scramClient) verifyServerFinal(serverFinal string) error {
+	attrs := parseSCRAMAttributes(serverFinal)
+	if msg, ok := attrs["e"]; ok {
+		return fmt.Errorf("SCRAM server error: %s", msg)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil || subtle.ConstantTimeCompare(signature, c.serverSignature) != 1 {
+		return errors.New("SCRAM server signature does not match")
+	}
+
+	c.done = true
+	return nil
+}
+
+// gs2Header declares no channel binding and the optional authorization identity
+func (c *scramClient) gs2Header() string {
+	if c.authzID != "" {
+		return "n,a=" + escapeSCRAMName(c.authzID) + ","
+	}
+	return "n,,"
+}
+
+func (c *scramClient) hmac(key, message []byte) []byte {
+	mac := hmac.New(c.newHash, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// parseSCRAMAttributes splits "k=v,k=v" messages; values may themselves contain '='
+func parseSCRAMAttributes(message string) map[string]string {
+	attrs := make(map[string]string)
+	for _, part := range strings.Split(message, ",") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// escapeSCRAMName encodes ',' and '=' in user names as RFC 5802 requires
+func escapeSCRAMName(name string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(name)
+}
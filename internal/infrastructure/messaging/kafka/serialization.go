@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// Event serialization formats (see Config.EventSerialization). JSON is the default and what
+// external consumers expect; protobuf is smaller and cheaper to encode on the hot path.
+const (
+	SerializationJSON     = "json"
+	SerializationProtobuf = "protobuf"
+)
+
+// Content types declared in the content-type header of every published message, so consumers
+// can decode each message regardless of how the producer that sent it was configured
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+const contentTypeHeader = "content-type"
+
+// Serializer encodes an event into a message payload and reports the payload's content type
+type Serializer interface {
+	Serialize(event interface{}) (payload []byte, contentType string, err error)
+}
+
+// JSONSerializer encodes every event as JSON
+type JSONSerializer struct{}
+
+// Serialize marshals event to JSON
+func (JSONSerializer) Serialize(event interface{}) ([]byte, string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return payload, ContentTypeJSON, nil
+}
+
+// MessageContentType returns the content type a message was published with. Messages without
+// the header predate it and are JSON.
+func MessageContentType(message *sarama.ConsumerMessage) string {
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) == contentTypeHeader {
+			return string(header.Value)
+		}
+	}
+	return ContentTypeJSON
+}
@@ -3,21 +3,41 @@ package kafka
 // Topic names for banking events
 const (
 	TopicAccountCreated        = "banking.accounts.created"
+	TopicAccountClosed         = "banking.accounts.closed"
 	TopicDepositRequests       = "banking.commands.deposit-requests"
+	TopicWithdrawalRequests    = "banking.commands.withdrawal-requests"
 	TopicTransactionDeposit    = "banking.transactions.deposit"
 	TopicTransactionWithdrawal = "banking.transactions.withdrawal"
 	TopicTransactionTransfer   = "banking.transactions.transfer"
+	TopicTransactionInterest   = "banking.transactions.interest"
 	TopicTransactionFailed     = "banking.transactions.failed"
+
+	// Dead-letter topics for messages that could not be processed
+	TopicDepositRequestsDLQ    = TopicDepositRequests + DeadLetterSuffix
+	TopicWithdrawalRequestsDLQ = TopicWithdrawalRequests + DeadLetterSuffix
 )
 
+// DeadLetterSuffix is appended to a topic name to build its dead-letter topic
+const DeadLetterSuffix = ".dlq"
+
+// DeadLetterTopic returns the dead-letter topic for the given source topic
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
 // GetAllTopics returns list of all topics
 func GetAllTopics() []string {
 	return []string{
 		TopicAccountCreated,
+		TopicAccountClosed,
 		TopicDepositRequests,
+		TopicWithdrawalRequests,
 		TopicTransactionDeposit,
 		TopicTransactionWithdrawal,
 		TopicTransactionTransfer,
+		TopicTransactionInterest,
 		TopicTransactionFailed,
+		TopicDepositRequestsDLQ,
+		TopicWithdrawalRequestsDLQ,
 	}
 }
@@ -0,0 +1,73 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// offsetCommitter batches offset commits for a consumer claim. Marked messages are committed
+// every batchSize messages or every flushInterval, whichever comes first.
+//
+// AT-LEAST-ONCE: messages are only marked after they were processed (or parked in the DLQ),
+// so a crash before a commit replays at most the uncommitted batch. Processing is idempotent,
+// which absorbs the duplicates.
+type offsetCommitter struct {
+	session   sarama.ConsumerGroupSession
+	batchSize int
+	pending   int
+	ticker    *time.Ticker
+}
+
+// newOffsetCommitter creates a committer for the session. A batchSize below 1 commits every
+// message; a non-positive flushInterval disables time-based flushing.
+func newOffsetCommitter(session sarama.ConsumerGroupSession, batchSize int, flushInterval time.Duration) *offsetCommitter {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	c := &offsetCommitter{
+		session:   session,
+		batchSize: batchSize,
+	}
+	if flushInterval > 0 {
+		c.ticker = time.NewTicker(flushInterval)
+	}
+	return c
+}
+
+// Mark marks a successfully handled message and commits once the batch is full
+func (c *offsetCommitter) Mark(message *sarama.ConsumerMessage) {
+	c.session.MarkMessage(message, "")
+	c.pending++
+
+	if c.pending >= c.batchSize {
+		c.Flush()
+	}
+}
+
+// Flush commits all marked offsets, if any
+func (c *offsetCommitter) Flush() {
+	if c.pending == 0 {
+		return
+	}
+
+	c.session.Commit()
+	c.pending = 0
+}
+
+// Ticks fires on every flush interval. Returns nil (never fires) when time-based flushing is disabled.
+func (c *offsetCommitter) Ticks() <-chan time.Time {
+	if c.ticker == nil {
+		return nil
+	}
+	return c.ticker.C
+}
+
+// Close stops the flush timer and commits whatever was processed so far
+func (c *offsetCommitter) Close() {
+	if c.ticker != nil {
+		c.ticker.Stop()
+	}
+	c.Flush()
+}
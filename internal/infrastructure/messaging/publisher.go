@@ -10,11 +10,15 @@ import (
 // EventPublisher defines the interface for publishing banking events
 type EventPublisher interface {
 	PublishAccountCreated(event AccountCreatedEvent) error
+	PublishAccountClosed(event AccountClosedEvent) error
 	PublishDepositRequested(event DepositRequestedEvent) error
 	PublishDepositCompleted(event DepositCompletedEvent) error
+	PublishWithdrawalRequested(event WithdrawalRequestedEvent) error
 	PublishWithdrawalCompleted(event WithdrawalCompletedEvent) error
 	PublishTransferCompleted(event TransferCompletedEvent) error
+	PublishInterestApplied(event InterestAppliedEvent) error
 	PublishTransactionFailed(event TransactionFailedEvent) error
+	PublishDeadLetter(event DeadLetterEvent) error
 	Close() error
 	IsHealthy() bool
 }
@@ -26,48 +30,89 @@ type KafkaEventPublisher struct {
 
 // NewKafkaEventPublisher creates a new Kafka event publisher
 func NewKafkaEventPublisher(config *kafka.Config) (*KafkaEventPublisher, error) {
+	serializer, err := NewEventSerializer(config.EventSerialization)
+	if err != nil {
+		return nil, err
+	}
+
 	producer, err := kafka.NewProducer(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
+	producer.SetSerializer(serializer)
 
 	return &KafkaEventPublisher{
 		producer: producer,
 	}, nil
 }
 
+// NewKafkaEventPublisherWithProducer creates a publisher on top of an existing producer
+// (e.g. one built with kafka.NewProducerWithClient in tests)
+func NewKafkaEventPublisherWithProducer(producer *kafka.Producer) *KafkaEventPublisher {
+	return &KafkaEventPublisher{
+		producer: producer,
+	}
+}
+
 // PublishAccountCreated publishes an account created event
 func (p *KafkaEventPublisher) PublishAccountCreated(event AccountCreatedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountCreated)
 	key := strconv.Itoa(event.AccountID)
 	return p.producer.PublishEvent(kafka.TopicAccountCreated, key, event)
 }
 
-// PublishDepositRequested publishes a deposit request command
-func (p *KafkaEventPublisher) PublishDepositRequested(event DepositRequestedEvent) error {
+// PublishAccountClosed publishes an account closed event
+func (p *KafkaEventPublisher) PublishAccountClosed(event AccountClosedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeAccountClosed)
 	key := strconv.Itoa(event.AccountID)
+	return p.producer.PublishEvent(kafka.TopicAccountClosed, key, event)
+}
+
+// PublishDepositRequested publishes a deposit request command, keyed by the configured partition key strategy
+func (p *KafkaEventPublisher) PublishDepositRequested(event DepositRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositRequested)
+	key := p.producer.PartitionKey(strconv.Itoa(event.AccountID), event.OperationID)
 	return p.producer.PublishEvent(kafka.TopicDepositRequests, key, event)
 }
 
 // PublishDepositCompleted publishes a deposit completed event
 func (p *KafkaEventPublisher) PublishDepositCompleted(event DepositCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDepositCompleted)
 	key := strconv.Itoa(event.AccountID)
 	return p.producer.PublishEvent(kafka.TopicTransactionDeposit, key, event)
 }
 
+// PublishWithdrawalRequested publishes a withdrawal request command
+func (p *KafkaEventPublisher) PublishWithdrawalRequested(event WithdrawalRequestedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalRequested)
+	key := strconv.Itoa(event.AccountID)
+	return p.producer.PublishEvent(kafka.TopicWithdrawalRequests, key, event)
+}
+
 // PublishWithdrawalCompleted publishes a withdrawal completed event
 func (p *KafkaEventPublisher) PublishWithdrawalCompleted(event WithdrawalCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeWithdrawalCompleted)
 	key := strconv.Itoa(event.AccountID)
 	return p.producer.PublishEvent(kafka.TopicTransactionWithdrawal, key, event)
 }
 
 // PublishTransferCompleted publishes a transfer completed event
 func (p *KafkaEventPublisher) PublishTransferCompleted(event TransferCompletedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransferCompleted)
 	key := fmt.Sprintf("%d-%d", event.FromAccountID, event.ToAccountID)
 	return p.producer.PublishEvent(kafka.TopicTransactionTransfer, key, event)
 }
 
+// PublishInterestApplied publishes an interest applied event
+func (p *KafkaEventPublisher) PublishInterestApplied(event InterestAppliedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeInterestApplied)
+	key := strconv.Itoa(event.AccountID)
+	return p.producer.PublishEvent(kafka.TopicTransactionInterest, key, event)
+}
+
 // PublishTransactionFailed publishes a transaction failed event
 func (p *KafkaEventPublisher) PublishTransactionFailed(event TransactionFailedEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeTransactionFailed)
 	// Use account ID as key if available, otherwise use transaction type
 	key := event.TransactionType
 	if event.AccountID != 0 {
@@ -78,6 +123,13 @@ func (p *KafkaEventPublisher) PublishTransactionFailed(event TransactionFailedEv
 	return p.producer.PublishEvent(kafka.TopicTransactionFailed, key, event)
 }
 
+// PublishDeadLetter publishes an unprocessable message to the dead-letter topic
+// of the topic it was consumed from
+func (p *KafkaEventPublisher) PublishDeadLetter(event DeadLetterEvent) error {
+	event.EventMetadata = newEventMetadata(EventTypeDeadLetter)
+	return p.producer.PublishEvent(kafka.DeadLetterTopic(event.OriginalTopic), event.Key, event)
+}
+
 // Close closes the Kafka producer
 func (p *KafkaEventPublisher) Close() error {
 	return p.producer.Close()
@@ -97,12 +149,18 @@ func NewNoOpEventPublisher() *NoOpEventPublisher {
 }
 
 func (p *NoOpEventPublisher) PublishAccountCreated(event AccountCreatedEvent) error     { return nil }
+func (p *NoOpEventPublisher) PublishAccountClosed(event AccountClosedEvent) error       { return nil }
 func (p *NoOpEventPublisher) PublishDepositRequested(event DepositRequestedEvent) error { return nil }
 func (p *NoOpEventPublisher) PublishDepositCompleted(event DepositCompletedEvent) error { return nil }
+func (p *NoOpEventPublisher) PublishWithdrawalRequested(event WithdrawalRequestedEvent) error {
+	return nil
+}
 func (p *NoOpEventPublisher) PublishWithdrawalCompleted(event WithdrawalCompletedEvent) error {
 	return nil
 }
 func (p *NoOpEventPublisher) PublishTransferCompleted(event TransferCompletedEvent) error { return nil }
+func (p *NoOpEventPublisher) PublishInterestApplied(event InterestAppliedEvent) error     { return nil }
 func (p *NoOpEventPublisher) PublishTransactionFailed(event TransactionFailedEvent) error { return nil }
+func (p *NoOpEventPublisher) PublishDeadLetter(event DeadLetterEvent) error               { return nil }
 func (p *NoOpEventPublisher) Close() error                                                { return nil }
 func (p *NoOpEventPublisher) IsHealthy() bool                                             { return true }
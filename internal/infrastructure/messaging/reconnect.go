@@ -0,0 +1,92 @@
+package messaging
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/logging"
+)
+
+// ReconnectBackoff spaces out consumer group sessions while the broker is unavailable.
+// The wait doubles with every consecutive failure up to Max, and each wait is jittered
+// to between half and all of it so consumers that lost the broker together don't retry in lockstep.
+type ReconnectBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	failures int
+}
+
+// NewReconnectBackoff builds the backoff from the consumer reconnect settings
+func NewReconnectBackoff(config *kafka.Config) *ReconnectBackoff {
+	return &ReconnectBackoff{
+		Initial: config.ConsumerReconnectBackoff,
+		Max:     config.ConsumerReconnectMaxBackoff,
+	}
+}
+
+// Failure records a failed session and returns how long to wait before the next one
+func (b *ReconnectBackoff) Failure() time.Duration {
+	b.failures++
+
+	delay := b.Initial
+	for i := 1; i < b.failures && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if delay > b.Max {
+		delay = b.Max
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// Reset starts the next run of failures from Initial again
+func (b *ReconnectBackoff) Reset() {
+	b.failures = 0
+}
+
+// Failures returns the number of consecutive failed sessions
+func (b *ReconnectBackoff) Failures() int {
+	return b.failures
+}
+
+// ConsumeWithBackoff calls consume until ctx is cancelled. A session that ends without error
+// (e.g. a rebalance) is restarted right away and resets the backoff; a failed one waits first.
+func ConsumeWithBackoff(ctx context.Context, group string, backoff *ReconnectBackoff, consume func(ctx context.Context) error) {
+	for {
+		// `Consume` should be called inside an infinite loop, when a
+		// server-side rebalance happens, the consumer session will need to be
+		// recreated to get the new claims
+		err := consume(ctx)
+
+		// check if context was cancelled, signaling that the consumer should stop
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			backoff.Reset()
+			continue
+		}
+
+		delay := backoff.Failure()
+		logging.Warn("Consumer group session failed, reconnecting", map[string]interface{}{
+			"group":   group,
+			"attempt": backoff.Failures(),
+			"backoff": delay.String(),
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
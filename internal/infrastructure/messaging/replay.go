@@ -0,0 +1,160 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/logging"
+)
+
+// replayBatchSize is how many transactions are read per query while replaying
+const replayBatchSize = 500
+
+// ReplayResult counts the events a replay published (or would publish, in a dry run)
+type ReplayResult struct {
+	Deposits    int
+	Withdrawals int
+	Transfers   int
+	Interest    int
+	Skipped     int // transfer halves whose counterpart lies outside the replayed range
+	LastID      int // ID of the last transaction read; resume from LastID + 1
+}
+
+// Total is the number of events published
+func (r ReplayResult) Total() int {
+	return r.Deposits + r.Withdrawals + r.Transfers + r.Interest
+}
+
+// ReplayTransactions re-emits the completed event of every transaction with an ID of at least
+// fromID created at or after since, in ID order, so a downstream read model can be rebuilt.
+// Events carry replay=true so live consumers can ignore them. A transfer is emitted once its
+// debit and credit rows have both been read. Interest events are replayed with a zero Rate,
+// since the rate isn't stored. With dryRun the events are only counted.
+// On a publish error the result so far is returned, and LastID tells where to resume.
+func ReplayTransactions(ctx context.Context, repo database.Repository, publisher EventPublisher, fromID int, since time.Time, dryRun bool) (ReplayResult, error) {
+	var result ReplayResult
+
+	publish := func(send func() error) error {
+		if dryRun {
+			return nil
+		}
+		return send()
+	}
+
+	// Debit rows waiting for their credit, by reference ID; batch transfers write each leg's
+	// pair consecutively, so the oldest pending debit is the match
+	pendingTransfers := make(map[string][]postgres.TransactionRecord)
+
+	nextID := fromID
+	for {
+		records, err := repo.ListTransactions(ctx, nextID, since, replayBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to read transactions: %w", err)
+		}
+
+		for _, record := range records {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			var err error
+			switch record.Type {
+			case "deposit":
+				err = publish(func() error {
+					return publisher.PublishDepositCompleted(DepositCompletedEvent{
+						AccountID:    record.AccountID,
+						Amount:       record.Amount,
+						BalanceAfter: record.BalanceAfter,
+						Timestamp:    record.CreatedAt,
+						Replay:       true,
+					})
+				})
+				result.Deposits++
+			case "withdraw":
+				err = publish(func() error {
+					return publisher.PublishWithdrawalCompleted(WithdrawalCompletedEvent{
+						AccountID:    record.AccountID,
+						Amount:       -record.Amount,
+						BalanceAfter: record.BalanceAfter,
+						Timestamp:    record.CreatedAt,
+						Replay:       true,
+					})
+				})
+				result.Withdrawals++
+			case "interest":
+				err = publish(func() error {
+					return publisher.PublishInterestApplied(InterestAppliedEvent{
+						AccountID:    record.AccountID,
+						Amount:       record.Amount,
+						BalanceAfter: record.BalanceAfter,
+						Timestamp:    record.CreatedAt,
+						Replay:       true,
+					})
+				})
+				result.Interest++
+			case "transfer_out":
+				pendingTransfers[record.ReferenceID] = append(pendingTransfers[record.ReferenceID], record)
+			case "transfer_in":
+				debits := pendingTransfers[record.ReferenceID]
+				if len(debits) == 0 {
+					result.Skipped++
+					break
+				}
+				debit := debits[0]
+				if len(debits) == 1 {
+					delete(pendingTransfers, record.ReferenceID)
+				} else {
+					pendingTransfers[record.ReferenceID] = debits[1:]
+				}
+
+				err = publish(func() error {
+					return publisher.PublishTransferCompleted(TransferCompletedEvent{
+						FromAccountID:    debit.AccountID,
+						ToAccountID:      record.AccountID,
+						Amount:           record.Amount,
+						FromBalanceAfter: debit.BalanceAfter,
+						ToBalanceAfter:   record.BalanceAfter,
+						Timestamp:        record.CreatedAt,
+						Replay:           true,
+					})
+				})
+				result.Transfers++
+			default:
+				logging.Warn("Skipping transaction of unknown type during replay", map[string]interface{}{
+					"transaction_id": record.ID,
+					"type":           record.Type,
+				})
+			}
+
+			if err != nil {
+				return result, fmt.Errorf("failed to replay transaction %d: %w", record.ID, err)
+			}
+			result.LastID = record.ID
+		}
+
+		if len(records) < replayBatchSize {
+			break
+		}
+		nextID = records[len(records)-1].ID + 1
+	}
+
+	for _, debits := range pendingTransfers {
+		result.Skipped += len(debits)
+	}
+
+	logging.Info("Transactions replayed", map[string]interface{}{
+		"from_id":     fromID,
+		"since":       since,
+		"dry_run":     dryRun,
+		"deposits":    result.Deposits,
+		"withdrawals": result.Withdrawals,
+		"transfers":   result.Transfers,
+		"interest":    result.Interest,
+		"skipped":     result.Skipped,
+		"last_id":     result.LastID,
+	})
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"bank-api/internal/infrastructure/messaging/eventspb"
+	"bank-api/internal/infrastructure/messaging/kafka"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Protobuf schemas live in proto/events/v1; regenerate eventspb after changing them:
+//
+//go:generate sh -c "cd proto && buf generate"
+
+// NewEventSerializer returns the serializer for a KAFKA_EVENT_SERIALIZATION value
+func NewEventSerializer(format string) (kafka.Serializer, error) {
+	switch format {
+	case "", kafka.SerializationJSON:
+		return kafka.JSONSerializer{}, nil
+	case kafka.SerializationProtobuf:
+		return ProtobufSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("invalid event serialization: %s", format)
+	}
+}
+
+// ProtobufSerializer encodes deposit requests, the highest-volume command, as protobuf.
+// Every other event has no protobuf schema and is still sent as JSON; the content-type
+// header tells consumers which is which.
+type ProtobufSerializer struct{}
+
+// Serialize encodes DepositRequestedEvent as protobuf and anything else as JSON
+func (ProtobufSerializer) Serialize(event interface{}) ([]byte, string, error) {
+	deposit, ok := event.(DepositRequestedEvent)
+	if !ok {
+		return kafka.JSONSerializer{}.Serialize(event)
+	}
+
+	payload, err := proto.Marshal(depositRequestedToProto(deposit))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return payload, kafka.ContentTypeProtobuf, nil
+}
+
+func depositRequestedToProto(event DepositRequestedEvent) *eventspb.DepositRequested {
+	return &eventspb.DepositRequested{
+		EventVersion:   event.EventVersion,
+		EventType:      event.EventType,
+		OperationId:    event.OperationID,
+		IdempotencyKey: event.IdempotencyKey,
+		AccountId:      int64(event.AccountID),
+		Amount:         int64(event.Amount),
+		TraceId:        event.TraceID,
+		CallbackUrl:    event.CallbackURL,
+		Timestamp:      timestamppb.New(event.Timestamp),
+	}
+}
+
+func depositRequestedFromProto(msg *eventspb.DepositRequested) DepositRequestedEvent {
+	return DepositRequestedEvent{
+		EventMetadata: EventMetadata{
+			EventVersion: msg.GetEventVersion(),
+			EventType:    msg.GetEventType(),
+		},
+		OperationID:    msg.GetOperationId(),
+		IdempotencyKey: msg.GetIdempotencyKey(),
+		AccountID:      int(msg.GetAccountId()),
+		Amount:         int(msg.GetAmount()),
+		TraceID:        msg.GetTraceId(),
+		CallbackURL:    msg.GetCallbackUrl(),
+		Timestamp:      msg.GetTimestamp().AsTime(),
+	}
+}
+
+// DecodeDepositRequested decodes a deposit request in whichever format its content-type
+// header declares. Unreadable payloads and unsupported schema versions wrap errMalformedMessage.
+func DecodeDepositRequested(message *sarama.ConsumerMessage) (DepositRequestedEvent, error) {
+	var event DepositRequestedEvent
+
+	switch contentType := kafka.MessageContentType(message); contentType {
+	case kafka.ContentTypeJSON:
+		// Reject schema versions we can't read before touching the payload fields
+		if err := checkEventVersion(message.Value); err != nil {
+			return event, err
+		}
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			return event, fmt.Errorf("%w: %v", errMalformedMessage, err)
+		}
+		return event, nil
+
+	case kafka.ContentTypeProtobuf:
+		var msg eventspb.DepositRequested
+		if err := proto.Unmarshal(message.Value, &msg); err != nil {
+			return event, fmt.Errorf("%w: %v", errMalformedMessage, err)
+		}
+		event = depositRequestedFromProto(&msg)
+		if event.EventVersion != "" && event.EventVersion != CurrentEventVersion {
+			return event, fmt.Errorf("%w: %w %q", errMalformedMessage, errUnsupportedEventVersion, event.EventVersion)
+		}
+		return event, nil
+
+	default:
+		return event, fmt.Errorf("%w: unsupported content type %q", errMalformedMessage, contentType)
+	}
+}
+
+// peekDepositRequest decodes as much of a deposit request as it can, ignoring errors.
+// Used for log lines and status updates written before (or instead of) a successful decode.
+func peekDepositRequest(message *sarama.ConsumerMessage) DepositRequestedEvent {
+	var event DepositRequestedEvent
+	if kafka.MessageContentType(message) == kafka.ContentTypeProtobuf {
+		var msg eventspb.DepositRequested
+		_ = proto.Unmarshal(message.Value, &msg)
+		return depositRequestedFromProto(&msg)
+	}
+	_ = json.Unmarshal(message.Value, &event)
+	return event
+}
@@ -0,0 +1,446 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+
+	"github.com/IBM/sarama"
+)
+
+// WithdrawalConsumer processes withdrawal request events from Kafka
+type WithdrawalConsumer struct {
+	consumerGroup sarama.ConsumerGroup
+	publisher     EventPublisher
+	db            database.Repository
+	config        *kafka.Config
+	wg            sync.WaitGroup
+	ctx           context.Context
+	cancel        context.CancelFunc
+}
+
+// NewWithdrawalConsumer creates a new withdrawal consumer
+func NewWithdrawalConsumer(config *kafka.Config, publisher EventPublisher, db database.Repository) (*WithdrawalConsumer, error) {
+	saramaConfig, err := config.ToSaramaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Consumer-specific configuration for at-least-once delivery
+	saramaConfig.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaConfig.Consumer.Return.Errors = true
+
+	// At-least-once: Disable auto-commit, commit manually after successful processing
+	saramaConfig.Consumer.Offsets.AutoCommit.Enable = false
+
+	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{
+		sarama.NewBalanceStrategyRoundRobin(),
+	}
+
+	consumerGroup, err := sarama.NewConsumerGroup(config.Brokers, "withdrawal-processor-group", saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WithdrawalConsumer{
+		consumerGroup: consumerGroup,
+		publisher:     publisher,
+		db:            db,
+		config:        config,
+		ctx:           ctx,
+		cancel:        cancel,
+	}, nil
+}
+
+// NewWithdrawalConsumerHandler returns the sarama handler used by WithdrawalConsumer.
+// Exposed so the processing logic can be driven without a running broker.
+func NewWithdrawalConsumerHandler(config *kafka.Config, publisher EventPublisher, db database.Repository) sarama.ConsumerGroupHandler {
+	maxRetries := config.ConsumerMaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	return &withdrawalConsumerHandler{
+		publisher:       publisher,
+		db:              db,
+		maxRetries:      maxRetries,
+		retryBackoff:    config.ConsumerRetryBackoff,
+		commitBatchSize: config.ConsumerCommitBatchSize,
+		commitInterval:  config.ConsumerCommitInterval,
+	}
+}
+
+// Start begins consuming withdrawal request events
+func (c *WithdrawalConsumer) Start() error {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		handler := NewWithdrawalConsumerHandler(c.config, c.publisher, c.db)
+		topics := []string{kafka.TopicWithdrawalRequests}
+
+		// Back off between failed sessions so an unavailable broker doesn't busy-spin the loop
+		ConsumeWithBackoff(c.ctx, "withdrawal-processor-group", NewReconnectBackoff(c.config), func(ctx context.Context) error {
+			return c.consumerGroup.Consume(ctx, topics, handler)
+		})
+	}()
+
+	// Handle errors in a separate goroutine
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case err, ok := <-c.consumerGroup.Errors():
+				if !ok {
+					return
+				}
+				log.Printf("Consumer group error: %v", err)
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("Withdrawal consumer started: group=withdrawal-processor-group, topic=%s", kafka.TopicWithdrawalRequests)
+	return nil
+}
+
+// Stop gracefully stops the consumer
+func (c *WithdrawalConsumer) Stop() error {
+	c.cancel()
+	c.wg.Wait()
+
+	if err := c.consumerGroup.Close(); err != nil {
+		return err
+	}
+
+	log.Println("Withdrawal consumer stopped")
+	return nil
+}
+
+// withdrawalConsumerHandler implements sarama.ConsumerGroupHandler
+type withdrawalConsumerHandler struct {
+	publisher       EventPublisher
+	db              database.Repository
+	maxRetries      int
+	retryBackoff    time.Duration
+	commitBatchSize int
+	commitInterval  time.Duration
+}
+
+// Setup is run at the beginning of a new session, before ConsumeClaim
+func (h *withdrawalConsumerHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
+func (h *withdrawalConsumerHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages()
+func (h *withdrawalConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	committer := newOffsetCommitter(session, h.commitBatchSize, h.commitInterval)
+	defer committer.Close() // Commit what was handled before the claim ends
+	lag := newLagReporter(claim)
+	defer lag.Stop()
+
+	for {
+		// Draining: once the session ends, don't pick up another message even if one is buffered
+		if session.Context().Err() != nil {
+			return nil
+		}
+
+		select {
+		case message := <-claim.Messages():
+			if message == nil {
+				return nil
+			}
+
+			attempts, err := h.processWithRetries(session.Context(), message)
+			if err != nil && session.Context().Err() != nil {
+				// Session ended mid-retry - leave the message uncommitted for the next owner
+				return nil
+			}
+			if err != nil {
+				// Retries exhausted or message is malformed - park it in the DLQ
+				if err := h.parkDeadLetter(session.Context(), message, err, attempts); err != nil {
+					// AT-LEAST-ONCE: Session ended before the DLQ accepted it - leave the
+					// message uncommitted for the next owner
+					return nil
+				}
+			}
+
+			// AT-LEAST-ONCE: Mark message only after successful processing (or after it has
+			// been safely parked in the DLQ); offsets are committed in batches
+			committer.Mark(message)
+			lag.Advance(message)
+
+		case <-committer.Ticks():
+			committer.Flush()
+
+		case <-lag.Ticks():
+			lag.Report()
+
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// processWithRetries processes a message up to maxRetries times, backing off between attempts.
+// Malformed messages are not retried. Returns the number of attempts made and the last error.
+// An attempt that has started runs to completion even if ctx is cancelled (shutdown drains it);
+// cancellation only stops further attempts.
+func (h *withdrawalConsumerHandler) processWithRetries(ctx context.Context, message *sarama.ConsumerMessage) (int, error) {
+	var err error
+	for attempt := 1; attempt <= h.maxRetries; attempt++ {
+		if err = h.processWithdrawalRequest(context.WithoutCancel(ctx), message); err == nil {
+			return attempt, nil
+		}
+
+		logging.Warn("Failed to process withdrawal request", map[string]interface{}{
+			"offset":   message.Offset,
+			"attempt":  attempt,
+			"max":      h.maxRetries,
+			"trace_id": withdrawalTraceID(message.Value),
+			"error":    err.Error(),
+		})
+
+		if errors.Is(err, errMalformedMessage) || attempt == h.maxRetries {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(h.retryBackoff):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+	}
+	return h.maxRetries, err
+}
+
+// parkDeadLetter publishes the message to the withdrawal dead-letter topic, retrying until the
+// publish succeeds or ctx ends. It must not give up while the session lasts: marking any later
+// message would commit past this one and lose it.
+func (h *withdrawalConsumerHandler) parkDeadLetter(ctx context.Context, message *sarama.ConsumerMessage, cause error, attempts int) error {
+	for {
+		err := h.publisher.PublishDeadLetter(newDeadLetterEvent(message, cause, attempts))
+		if err == nil {
+			break
+		}
+
+		logging.Error("Failed to publish withdrawal request to dead-letter topic", err, map[string]interface{}{
+			"offset":   message.Offset,
+			"trace_id": withdrawalTraceID(message.Value),
+		})
+
+		select {
+		case <-time.After(h.retryBackoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	logging.Warn("Withdrawal request moved to dead-letter topic", map[string]interface{}{
+		"topic":     message.Topic,
+		"partition": message.Partition,
+		"offset":    message.Offset,
+		"attempts":  attempts,
+		"trace_id":  withdrawalTraceID(message.Value),
+		"error":     cause.Error(),
+	})
+	metrics.RecordBankingOperation("withdraw", "dead_lettered")
+
+	if operationID := withdrawalOperationID(message.Value); operationID != "" {
+		recordOutcome(h.db, operationID, models.OperationStatusFailed, FailureReasonDeadLettered)
+	}
+	return nil
+}
+
+// withdrawalTraceID returns the trace ID of a withdrawal request payload, or "" if it has none or
+// can't be read. Used for log lines written before (or instead of) a successful decode.
+func withdrawalTraceID(payload []byte) string {
+	var traced struct {
+		TraceID string `json:"trace_id"`
+	}
+	_ = json.Unmarshal(payload, &traced)
+	return traced.TraceID
+}
+
+// withdrawalOperationID returns the operation ID of a withdrawal request payload, or "" if it
+// can't be read
+func withdrawalOperationID(payload []byte) string {
+	var operation struct {
+		OperationID string `json:"operation_id"`
+	}
+	_ = json.Unmarshal(payload, &operation)
+	return operation.OperationID
+}
+
+// publishCompleted publishes the withdrawal completed event for an applied withdrawal request
+func (h *withdrawalConsumerHandler) publishCompleted(event WithdrawalRequestedEvent, balance int) error {
+	completedEvent := WithdrawalCompletedEvent{
+		OperationID:  event.OperationID,
+		AccountID:    event.AccountID,
+		Amount:       event.Amount,
+		BalanceAfter: balance,
+		TraceID:      event.TraceID,
+		Timestamp:    time.Now(),
+	}
+	if err := h.publisher.PublishWithdrawalCompleted(completedEvent); err != nil {
+		logging.Error("Failed to publish withdrawal completed event", err, map[string]interface{}{
+			"operation_id": event.OperationID,
+			"trace_id":     event.TraceID,
+			"account_id":   event.AccountID,
+		})
+		return err
+	}
+	return nil
+}
+
+// processWithdrawalRequest processes a single withdrawal request event with idempotency
+func (h *withdrawalConsumerHandler) processWithdrawalRequest(ctx context.Context, message *sarama.ConsumerMessage) error {
+	// Deserialize the event, rejecting schema versions we can't read before touching its fields
+	var event WithdrawalRequestedEvent
+	err := checkEventVersion(message.Value)
+	if err == nil {
+		if err = json.Unmarshal(message.Value, &event); err != nil {
+			err = fmt.Errorf("%w: %v", errMalformedMessage, err)
+		}
+	}
+	if err != nil {
+		logging.Error("Failed to decode withdrawal request event", err, map[string]interface{}{
+			"offset":   message.Offset,
+			"trace_id": withdrawalTraceID(message.Value),
+		})
+		return err
+	}
+
+	logging.Info("Processing withdrawal request", map[string]interface{}{
+		"operation_id":    event.OperationID,
+		"idempotency_key": event.IdempotencyKey,
+		"trace_id":        event.TraceID,
+		"account_id":      event.AccountID,
+		"amount":          event.Amount,
+	})
+
+	// Perform atomic withdrawal with idempotency check
+	acc, err := h.db.AtomicWithdrawWithIdempotency(ctx, event.AccountID, event.Amount, event.IdempotencyKey)
+
+	if err != nil {
+		// Duplicate operation (expected with at-least-once)
+		if errors.Is(err, postgres.ErrDuplicateOperation) {
+			// A redelivery of an operation already applied here - typically a retry after its
+			// completed event failed to publish - re-publishes that event from the stored result.
+			// A different operation reusing the key is only marked as a duplicate.
+			if alreadyCompleted(ctx, h.db, event.OperationID) {
+				logging.Info("Withdrawal already applied - re-publishing completed event", map[string]interface{}{
+					"operation_id":    event.OperationID,
+					"idempotency_key": event.IdempotencyKey,
+					"trace_id":        event.TraceID,
+					"account_id":      event.AccountID,
+				})
+				return h.publishCompleted(event, acc.Balance)
+			}
+
+			logging.Info("Duplicate operation detected (idempotent) - skipping", map[string]interface{}{
+				"idempotency_key": event.IdempotencyKey,
+				"trace_id":        event.TraceID,
+				"account_id":      event.AccountID,
+			})
+			metrics.RecordBankingOperation("withdraw", "duplicate")
+			recordOutcome(h.db, event.OperationID, models.OperationStatusDuplicate, "")
+			return nil
+		}
+
+		// Business failures are final - publish failure event and don't retry
+		if errors.Is(err, postgres.ErrInsufficientFunds) || errors.Is(err, postgres.ErrAccountNotFound) ||
+			errors.Is(err, postgres.ErrAccountClosed) || errors.Is(err, postgres.ErrAccountFrozen) ||
+			errors.Is(err, postgres.ErrWithdrawalLimitExceeded) {
+			errorMessage, reason := "Insufficient funds", FailureReasonInsufficientFunds
+			switch {
+			case errors.Is(err, postgres.ErrAccountNotFound):
+				errorMessage, reason = "Account not found", FailureReasonAccountNotFound
+			case errors.Is(err, postgres.ErrAccountClosed):
+				errorMessage, reason = "Account closed", FailureReasonAccountClosed
+			case errors.Is(err, postgres.ErrAccountFrozen):
+				errorMessage, reason = "Account frozen", FailureReasonAccountFrozen
+			case errors.Is(err, postgres.ErrWithdrawalLimitExceeded):
+				errorMessage, reason = "Savings withdrawal limit reached for this period", FailureReasonWithdrawalLimitExceeded
+			}
+
+			failedEvent := TransactionFailedEvent{
+				TransactionType: "withdrawal",
+				OperationID:     event.OperationID,
+				AccountID:       event.AccountID,
+				Amount:          event.Amount,
+				ErrorMessage:    errorMessage,
+				Reason:          reason,
+				TraceID:         event.TraceID,
+				Timestamp:       time.Now(),
+			}
+			if err := h.publisher.PublishTransactionFailed(failedEvent); err != nil {
+				logging.Error("Failed to publish transaction failed event", err, map[string]interface{}{
+					"operation_id": event.OperationID,
+					"trace_id":     event.TraceID,
+				})
+			}
+			logging.Warn("Withdrawal rejected", map[string]interface{}{
+				"operation_id": event.OperationID,
+				"trace_id":     event.TraceID,
+				"account_id":   event.AccountID,
+				"reason":       reason,
+			})
+			metrics.RecordBankingOperation("withdraw", "error")
+			recordOutcome(h.db, event.OperationID, models.OperationStatusFailed, reason)
+			return nil // Don't retry - retrying can't change the outcome
+		}
+
+		// Real error - log and retry
+		logging.Error("Failed to process withdrawal", err, map[string]interface{}{
+			"operation_id":    event.OperationID,
+			"idempotency_key": event.IdempotencyKey,
+			"trace_id":        event.TraceID,
+			"account_id":      event.AccountID,
+		})
+		metrics.RecordBankingOperation("withdraw", "error")
+		return err // Retry on database failure
+	}
+
+	balance := acc.Balance
+
+	// Record successful operation and metrics
+	metrics.RecordBankingOperation("withdraw", "success")
+	metrics.RecordAccountBalance(float64(balance))
+	recordOutcome(h.db, event.OperationID, models.OperationStatusCompleted, "")
+
+	if err := h.publishCompleted(event, balance); err != nil {
+		return err // Retry on publish failure; the retry re-publishes from the stored result
+	}
+
+	logging.Info("Withdrawal processed successfully", map[string]interface{}{
+		"operation_id":    event.OperationID,
+		"idempotency_key": event.IdempotencyKey,
+		"trace_id":        event.TraceID,
+		"account_id":      event.AccountID,
+		"new_balance":     balance,
+	})
+
+	return nil
+}
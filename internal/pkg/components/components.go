@@ -1,24 +1,33 @@
 package components
 
 import (
+	"bank-api/internal/api/grpcapi"
 	"bank-api/internal/api/middleware"
 	"bank-api/internal/api/routes"
 	"bank-api/internal/config"
 	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/memory"
 	"bank-api/internal/infrastructure/database/postgres"
 	"bank-api/internal/infrastructure/messaging"
 	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/gctuning"
 	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/internal/pkg/validation"
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 )
 
 // Container holds all application components and their dependencies
@@ -29,6 +38,33 @@ type Container struct {
 	EventPublisher messaging.EventPublisher
 	Router         *gin.Engine
 	Server         *http.Server
+	GRPCServer     *grpc.Server
+
+	consumers              []consumer // Started by StartConsumers, drained by Shutdown
+	stopIdempotencyCleanup context.CancelFunc
+	stopInterestAccrual    context.CancelFunc
+	stopPoolMetrics        context.CancelFunc
+	shuttingDown           atomic.Bool
+}
+
+// processedOperationsCleaner is implemented by repositories that keep idempotency records
+type processedOperationsCleaner interface {
+	CleanupProcessedOperations(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// databaseCloser is implemented by repositories that hold a connection pool
+type databaseCloser interface {
+	Close()
+}
+
+// poolStatsReporter is implemented by repositories backed by a pgx connection pool
+type poolStatsReporter interface {
+	PoolStats() map[string]*pgxpool.Stat
+}
+
+// interestApplier is implemented by repositories that support interest accrual
+type interestApplier interface {
+	ApplyInterestWithCredits(ctx context.Context, rate float64) ([]postgres.InterestCredit, error)
 }
 
 var (
@@ -66,6 +102,9 @@ func newContainer() (*Container, error) {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	// Apply GC tuning before the heap starts growing
+	container.initGCTuning()
+
 	// Initialize database
 	if err := container.initDatabase(); err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -81,6 +120,9 @@ func newContainer() (*Container, error) {
 		return nil, fmt.Errorf("failed to initialize server: %w", err)
 	}
 
+	// Start scheduled interest accrual (needs both database and publisher)
+	container.initInterestAccrual()
+
 	logging.Info("All components initialized successfully", nil)
 	return container, nil
 }
@@ -88,6 +130,12 @@ func newContainer() (*Container, error) {
 // initConfig loads the application configuration
 func (c *Container) initConfig() error {
 	c.Config = config.Load()
+
+	limits := c.Config.Limits
+	if err := validation.SetAmountLimits(limits.MinTransactionAmount, limits.MaxTransactionAmount); err != nil {
+		return fmt.Errorf("invalid transaction amount limits: %w", err)
+	}
+	validation.SetAllowPrivateCallbackHosts(c.Config.Callbacks.AllowPrivateHosts)
 	return nil
 }
 
@@ -102,11 +150,36 @@ func (c *Container) initLogger() error {
 	return nil
 }
 
+// initGCTuning sizes the GC heap ballast from GO_BALLAST_MB and reports the effective settings
+func (c *Container) initGCTuning() {
+	ballastMB := c.Config.Runtime.BallastMB
+	if ballastMB < 0 {
+		logging.Warn("Invalid GO_BALLAST_MB, heap ballast disabled", map[string]interface{}{
+			"value": ballastMB,
+		})
+		ballastMB = 0
+	}
+
+	settings := gctuning.Apply(ballastMB)
+	logging.Info("GC tuning applied", map[string]interface{}{
+		"gc_percent":    settings.GCPercent,
+		"ballast_bytes": settings.BallastBytes,
+	})
+}
+
 // initDatabase sets up the database connection
 func (c *Container) initDatabase() error {
 	// Load database configuration from environment
 	dbConfig := postgres.NewConfigFromEnv()
 
+	switch c.Config.Database.Type {
+	case "memory", "inmemory":
+		return c.initMemoryDatabase(dbConfig)
+	case "postgres", "postgresql", "":
+	default:
+		return fmt.Errorf("unknown DATABASE_TYPE %q (expected postgres or memory)", c.Config.Database.Type)
+	}
+
 	// Initialize PostgreSQL repository with configuration
 	repo, err := postgres.NewPostgresRepository(dbConfig)
 	if err != nil {
@@ -117,6 +190,9 @@ func (c *Container) initDatabase() error {
 	database.Repo = repo
 	c.Database = repo
 
+	c.initIdempotencyCleanup(dbConfig)
+	c.initPoolMetrics(dbConfig)
+
 	logging.Info("Database initialized", map[string]interface{}{
 		"type":     "postgresql",
 		"host":     dbConfig.Host,
@@ -126,19 +202,223 @@ func (c *Container) initDatabase() error {
 	return nil
 }
 
+// initMemoryDatabase sets up the in-memory repository; data is lost on restart.
+// Balance and savings withdrawal limits still come from the database configuration.
+func (c *Container) initMemoryDatabase(dbConfig *postgres.Config) error {
+	repo := memory.NewInMemoryRepository(dbConfig)
+
+	database.Repo = repo
+	c.Database = repo
+
+	c.initIdempotencyCleanup(dbConfig)
+
+	logging.Info("Database initialized", map[string]interface{}{
+		"type": "memory",
+	})
+	return nil
+}
+
+// initIdempotencyCleanup starts a background job that prunes old idempotency records
+// Disabled unless IDEMPOTENCY_RETENTION is set to a positive duration
+func (c *Container) initIdempotencyCleanup(dbConfig *postgres.Config) {
+	if dbConfig.IdempotencyRetention == "" {
+		return
+	}
+
+	retention, err := time.ParseDuration(dbConfig.IdempotencyRetention)
+	if err != nil || retention <= 0 {
+		logging.Warn("Invalid IDEMPOTENCY_RETENTION, idempotency cleanup disabled", map[string]interface{}{
+			"value": dbConfig.IdempotencyRetention,
+		})
+		return
+	}
+
+	interval, err := time.ParseDuration(dbConfig.IdempotencyCleanupInterval)
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	cleaner, ok := c.Database.(processedOperationsCleaner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopIdempotencyCleanup = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				removed, err := cleaner.CleanupProcessedOperations(ctx, retention)
+				if err != nil {
+					logging.Error("Idempotency cleanup failed", err, nil)
+					continue
+				}
+				logging.Debug("Idempotency cleanup completed", map[string]interface{}{
+					"removed": removed,
+				})
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	logging.Info("Idempotency cleanup job started", map[string]interface{}{
+		"retention": retention.String(),
+		"interval":  interval.String(),
+	})
+}
+
+// initPoolMetrics starts a background job that exports connection pool statistics to Prometheus,
+// so pool exhaustion shows up as acquire waits rather than unexplained latency
+func (c *Container) initPoolMetrics(dbConfig *postgres.Config) {
+	reporter, ok := c.Database.(poolStatsReporter)
+	if !ok {
+		return
+	}
+
+	interval, err := time.ParseDuration(dbConfig.PoolMetricsInterval)
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopPoolMetrics = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for name, stat := range reporter.PoolStats() {
+				metrics.UpdateDBPoolStats(name, stat)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// initInterestAccrual starts a background job that periodically credits interest to active accounts
+// Disabled unless INTEREST_RATE is set to a positive value
+func (c *Container) initInterestAccrual() {
+	rate := c.Config.Interest.Rate
+	if rate <= 0 {
+		return
+	}
+
+	applier, ok := c.Database.(interestApplier)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopInterestAccrual = cancel
+
+	go func() {
+		ticker := time.NewTicker(c.Config.Interest.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.applyInterest(ctx, applier, rate)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	logging.Info("Interest accrual job started", map[string]interface{}{
+		"rate":     rate,
+		"interval": c.Config.Interest.Interval.String(),
+	})
+}
+
+// applyInterest runs a single accrual and publishes one event per credited account
+func (c *Container) applyInterest(ctx context.Context, applier interestApplier, rate float64) {
+	credits, err := applier.ApplyInterestWithCredits(ctx, rate)
+	if err != nil {
+		logging.Error("Interest accrual failed", err, nil)
+		return
+	}
+
+	now := time.Now()
+	for _, credit := range credits {
+		event := messaging.InterestAppliedEvent{
+			AccountID:    credit.AccountID,
+			Rate:         rate,
+			Amount:       credit.Amount,
+			BalanceAfter: credit.BalanceAfter,
+			Timestamp:    now,
+		}
+		if err := c.EventPublisher.PublishInterestApplied(event); err != nil {
+			logging.Error("Failed to publish interest applied event", err, map[string]interface{}{
+				"account_id": credit.AccountID,
+			})
+		}
+	}
+
+	logging.Info("Interest accrual completed", map[string]interface{}{
+		"accounts_credited": len(credits),
+	})
+}
+
 // initEventPublisher sets up the Kafka event publisher
 func (c *Container) initEventPublisher() error {
+	publisher := c.newEventPublisher()
+
+	// Optionally tee every published event into a JSON lines file for debugging
+	if path := os.Getenv("EVENT_LOG_FILE"); path != "" {
+		eventLog, err := messaging.NewEventLogPublisher(publisher, path)
+		if err != nil {
+			return err
+		}
+		publisher = eventLog
+		logging.Info("Event log enabled", map[string]interface{}{
+			"path": path,
+		})
+	}
+
+	c.EventPublisher = publisher
+	return nil
+}
+
+// ensureKafkaTopics creates missing topics when KAFKA_AUTO_CREATE_TOPICS is set
+func ensureKafkaTopics(config *kafka.Config) error {
+	if !config.AutoCreateTopics {
+		return nil
+	}
+	return kafka.EnsureTopics(config)
+}
+
+// newEventPublisher returns the Kafka event publisher, or a no-op publisher when Kafka is
+// disabled or unavailable
+func (c *Container) newEventPublisher() messaging.EventPublisher {
 	// Check if Kafka is enabled (default: enabled, can be disabled for tests)
 	kafkaEnabled := os.Getenv("KAFKA_ENABLED")
 	if kafkaEnabled == "false" {
 		logging.Info("Kafka disabled, using no-op event publisher", nil)
-		c.EventPublisher = messaging.NewNoOpEventPublisher()
-		return nil
+		return messaging.NewNoOpEventPublisher()
 	}
 
 	// Load Kafka configuration from environment
 	kafkaConfig := kafka.NewConfigFromEnv()
 
+	if err := ensureKafkaTopics(kafkaConfig); err != nil {
+		logging.Warn("Failed to create Kafka topics", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	// Initialize Kafka event publisher
 	publisher, err := messaging.NewKafkaEventPublisher(kafkaConfig)
 	if err != nil {
@@ -147,15 +427,13 @@ func (c *Container) initEventPublisher() error {
 		logging.Warn("Failed to initialize Kafka, using no-op event publisher", map[string]interface{}{
 			"error": err.Error(),
 		})
-		c.EventPublisher = messaging.NewNoOpEventPublisher()
-		return nil
+		return messaging.NewNoOpEventPublisher()
 	}
 
-	c.EventPublisher = publisher
 	logging.Info("Kafka event publisher initialized", map[string]interface{}{
 		"brokers": kafkaConfig.Brokers,
 	})
-	return nil
+	return publisher
 }
 
 // initServer sets up the HTTP server with all middleware and routes
@@ -168,11 +446,17 @@ func (c *Container) initServer() error {
 
 	c.Router = gin.Default()
 
-	// Apply global middleware
-	c.Router.Use(middleware.CORS(c.Config))
+	// Shed load before any other work is done for the request; probes and scrapes always get through
+	c.Router.Use(middleware.MaxInFlight(c.Config.Server.MaxInFlightRequests, "/healthz", "/readyz", "/prometheus"))
 
-	// Register all routes with container
-	routes.RegisterRoutes(c.Router, c)
+	// Oversized bodies are refused before a handler buffers them
+	c.Router.Use(middleware.MaxBodySize(c.Config.Server.MaxRequestBodyBytes))
+
+	// Register all routes with container; CORS is applied per route group
+	routes.RegisterRoutes(c.Router, c, routes.CORSPolicies{
+		Public: c.Config.CORS,
+		Admin:  c.Config.AdminCORS,
+	})
 
 	// Create HTTP server
 	c.Server = &http.Server{
@@ -185,8 +469,18 @@ func (c *Container) initServer() error {
 	}
 
 	logging.Info("HTTP server configured", map[string]interface{}{
-		"port": c.Config.Server.Port,
+		"port":                   c.Config.Server.Port,
+		"max_in_flight_requests": c.Config.Server.MaxInFlightRequests,
+		"max_request_body_bytes": c.Config.Server.MaxRequestBodyBytes,
 	})
+
+	// gRPC transport is opt-in via GRPC_PORT
+	if c.Config.Server.GRPCPort != "" {
+		c.GRPCServer = grpcapi.NewServer(c)
+		logging.Info("gRPC server configured", map[string]interface{}{
+			"port": c.Config.Server.GRPCPort,
+		})
+	}
 	return nil
 }
 
@@ -204,6 +498,24 @@ func (c *Container) Start() error {
 		}
 	}()
 
+	if c.GRPCServer != nil {
+		listener, err := net.Listen("tcp", ":"+c.Config.Server.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("failed to listen on gRPC port: %w", err)
+		}
+
+		logging.Info("Starting gRPC server", map[string]interface{}{
+			"address": listener.Addr().String(),
+		})
+
+		go func() {
+			if err := c.GRPCServer.Serve(listener); err != nil {
+				logging.Error("gRPC server failed", err, nil)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	c.waitForShutdown()
 	return nil
@@ -215,6 +527,9 @@ func (c *Container) waitForShutdown() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Fail readiness checks right away so the load balancer stops routing traffic
+	c.shuttingDown.Store(true)
+
 	logging.Info("Shutting down server...", nil)
 
 	// Graceful shutdown with timeout
@@ -230,11 +545,36 @@ func (c *Container) waitForShutdown() {
 
 // Shutdown gracefully stops all components
 func (c *Container) Shutdown(ctx context.Context) error {
-	// Shutdown HTTP server
-	if err := c.Server.Shutdown(ctx); err != nil {
-		return fmt.Errorf("server shutdown failed: %w", err)
+	c.shuttingDown.Store(true)
+
+	// Shutdown HTTP server (absent in the standalone consumer process)
+	if c.Server != nil {
+		if err := c.Server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("server shutdown failed: %w", err)
+		}
 	}
 
+	// Shutdown gRPC server, letting in-flight RPCs finish
+	if c.GRPCServer != nil {
+		c.GRPCServer.GracefulStop()
+	}
+
+	// Stop background jobs
+	if c.stopIdempotencyCleanup != nil {
+		c.stopIdempotencyCleanup()
+	}
+	if c.stopInterestAccrual != nil {
+		c.stopInterestAccrual()
+	}
+	if c.stopPoolMetrics != nil {
+		c.stopPoolMetrics()
+	}
+
+	// Stop consumers while the publisher and database are still open, so messages in
+	// progress finish and are committed; unstarted ones stay uncommitted for redelivery
+	stopConsumers(c.consumers)
+	c.consumers = nil
+
 	// Close Kafka event publisher
 	if c.EventPublisher != nil {
 		if err := c.EventPublisher.Close(); err != nil {
@@ -242,9 +582,19 @@ func (c *Container) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Close the database last; nothing above may use it after this
+	if closer, ok := c.Database.(databaseCloser); ok {
+		closer.Close()
+	}
+
 	return nil
 }
 
+// IsShuttingDown reports whether graceful shutdown has started
+func (c *Container) IsShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
 // GetDatabase returns the database repository
 func (c *Container) GetDatabase() database.Repository {
 	return c.Database
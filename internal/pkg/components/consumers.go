@@ -0,0 +1,123 @@
+package components
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/logging"
+	"context"
+	"fmt"
+	"time"
+)
+
+// consumer is a Kafka consumer run by the standalone consumer process
+type consumer interface {
+	Start() error
+	Stop() error
+}
+
+// NewConsumerContainer initializes the components a standalone consumer process needs:
+// configuration, logger, database and the Kafka publisher for completion events, which also
+// POSTs completed deposits to their callback_url.
+// Unlike New it doesn't configure the HTTP/gRPC servers or start interest accrual,
+// which must only run in the API process.
+func NewConsumerContainer() (*Container, error) {
+	container := &Container{}
+
+	if err := container.initConfig(); err != nil {
+		return nil, fmt.Errorf("failed to initialize config: %w", err)
+	}
+
+	if err := container.initLogger(); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	container.initGCTuning()
+
+	if err := container.initDatabase(); err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	kafkaConfig := kafka.NewConfigFromEnv()
+	if err := ensureKafkaTopics(kafkaConfig); err != nil {
+		return nil, fmt.Errorf("failed to create kafka topics: %w", err)
+	}
+
+	// No no-op fallback here: without a broker there is nothing to consume, and completion
+	// events would be silently discarded
+	publisher, err := messaging.NewKafkaEventPublisher(kafkaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize event publisher: %w", err)
+	}
+	// Deposits may carry a callback_url; the consumer POSTs their completion back to it
+	container.EventPublisher = messaging.NewDepositCallbackPublisher(publisher)
+
+	logging.Info("Consumer components initialized successfully", nil)
+	return container, nil
+}
+
+// RunConsumers starts the deposit and withdrawal consumers and blocks until ctx is cancelled,
+// then shuts the container down, which drains the consumers before closing anything they use
+func (c *Container) RunConsumers(ctx context.Context) error {
+	if err := c.StartConsumers(); err != nil {
+		c.shutdownConsumerProcess()
+		return err
+	}
+
+	<-ctx.Done()
+
+	logging.Info("Shutting down consumers...", nil)
+	c.shutdownConsumerProcess()
+
+	logging.Info("Consumer shutdown complete", nil)
+	return nil
+}
+
+// StartConsumers starts the deposit and withdrawal consumers. The container owns them from
+// then on: Shutdown stops them before closing the event publisher and the database.
+func (c *Container) StartConsumers() error {
+	kafkaConfig := kafka.NewConfigFromEnv()
+
+	deposits, err := messaging.NewDepositConsumer(kafkaConfig, c.EventPublisher, c.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create deposit consumer: %w", err)
+	}
+
+	withdrawals, err := messaging.NewWithdrawalConsumer(kafkaConfig, c.EventPublisher, c.Database)
+	if err != nil {
+		stopConsumers([]consumer{deposits})
+		return fmt.Errorf("failed to create withdrawal consumer: %w", err)
+	}
+
+	consumers := []consumer{deposits, withdrawals}
+	for _, cons := range consumers {
+		if err := cons.Start(); err != nil {
+			stopConsumers(consumers)
+			return fmt.Errorf("failed to start consumer: %w", err)
+		}
+	}
+	c.consumers = append(c.consumers, consumers...)
+
+	logging.Info("Consumers started", map[string]interface{}{
+		"topics": []string{kafka.TopicDepositRequests, kafka.TopicWithdrawalRequests},
+	})
+	return nil
+}
+
+// stopConsumers stops every consumer, logging failures instead of aborting so the rest still stop
+func stopConsumers(consumers []consumer) {
+	for _, cons := range consumers {
+		if err := cons.Stop(); err != nil {
+			logging.Error("Failed to stop consumer", err, nil)
+		}
+	}
+}
+
+// shutdownConsumerProcess stops the consumers and releases the remaining components
+func (c *Container) shutdownConsumerProcess() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := c.Shutdown(ctx); err != nil {
+		logging.Error("Consumer forced to shutdown", err, nil)
+	}
+}
@@ -0,0 +1,42 @@
+package components
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/logging"
+	"fmt"
+)
+
+// NewReplayContainer initializes the components the replay tool needs: configuration, logger,
+// database and, unless this is a dry run, the Kafka publisher. A dry run only counts events,
+// so it works without a broker.
+func NewReplayContainer(dryRun bool) (*Container, error) {
+	container := &Container{}
+
+	if err := container.initConfig(); err != nil {
+		return nil, fmt.Errorf("failed to initialize config: %w", err)
+	}
+
+	if err := container.initLogger(); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	if err := container.initDatabase(); err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	if dryRun {
+		container.EventPublisher = messaging.NewNoOpEventPublisher()
+	} else {
+		publisher, err := messaging.NewKafkaEventPublisher(kafka.NewConfigFromEnv())
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize event publisher: %w", err)
+		}
+		container.EventPublisher = publisher
+	}
+
+	logging.Info("Replay components initialized successfully", map[string]interface{}{
+		"dry_run": dryRun,
+	})
+	return container, nil
+}
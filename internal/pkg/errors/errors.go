@@ -8,7 +8,7 @@ import (
 type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
-	Status  int    `json:"-"`
+	Status  int    `json:"status"`
 }
 
 func (e APIError) Error() string {
@@ -17,14 +17,27 @@ func (e APIError) Error() string {
 
 // Common error codes
 const (
-	ErrCodeValidation        = "VALIDATION_ERROR"
-	ErrCodeNotFound          = "NOT_FOUND"
-	ErrCodeInternalServer    = "INTERNAL_SERVER_ERROR"
-	ErrCodeRateLimit         = "RATE_LIMIT_EXCEEDED"
-	ErrCodeInsufficientFunds = "INSUFFICIENT_FUNDS"
-	ErrCodeInvalidAmount     = "INVALID_AMOUNT"
-	ErrCodeAccountNotFound   = "ACCOUNT_NOT_FOUND"
-	ErrCodeSelfTransfer      = "SELF_TRANSFER_NOT_ALLOWED"
+	ErrCodeValidation            = "VALIDATION_ERROR"
+	ErrCodeNotFound              = "NOT_FOUND"
+	ErrCodeInternalServer        = "INTERNAL_SERVER_ERROR"
+	ErrCodeRateLimit             = "RATE_LIMIT_EXCEEDED"
+	ErrCodeInsufficientFunds     = "INSUFFICIENT_FUNDS"
+	ErrCodeInvalidAmount         = "INVALID_AMOUNT"
+	ErrCodeAccountNotFound       = "ACCOUNT_NOT_FOUND"
+	ErrCodeSelfTransfer          = "SELF_TRANSFER_NOT_ALLOWED"
+	ErrCodeAccountClosed         = "ACCOUNT_CLOSED"
+	ErrCodeAccountFrozen         = "ACCOUNT_FROZEN"
+	ErrCodeAccountHasBalance     = "ACCOUNT_HAS_BALANCE"
+	ErrCodeOverdraftInUse        = "OVERDRAFT_IN_USE"
+	ErrCodeCurrencyMismatch      = "CURRENCY_MISMATCH"
+	ErrCodeBalanceLimitExceeded  = "BALANCE_LIMIT_EXCEEDED"
+	ErrCodeInvalidIdempotencyKey = "INVALID_IDEMPOTENCY_KEY"
+	ErrCodePublishFailed         = "EVENT_PUBLISH_FAILED"
+	ErrCodeHoldNotFound          = "HOLD_NOT_FOUND"
+	ErrCodeHoldNotActive         = "HOLD_NOT_ACTIVE"
+	ErrCodeOperationNotFound     = "OPERATION_NOT_FOUND"
+	ErrCodeServiceOverloaded     = "SERVICE_OVERLOADED"
+	ErrCodeRequestTooLarge       = "REQUEST_TOO_LARGE"
 )
 
 // Error constructors
@@ -60,6 +73,24 @@ func NewRateLimitError() APIError {
 	}
 }
 
+// NewServiceOverloadedError is returned when a request is shed because too many are in flight
+func NewServiceOverloadedError() APIError {
+	return APIError{
+		Code:    ErrCodeServiceOverloaded,
+		Message: "Server is overloaded. Please try again shortly.",
+		Status:  http.StatusServiceUnavailable,
+	}
+}
+
+// NewRequestTooLargeError is returned when a request body exceeds the configured size limit
+func NewRequestTooLargeError() APIError {
+	return APIError{
+		Code:    ErrCodeRequestTooLarge,
+		Message: "Request body too large",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+}
+
 func NewInsufficientFundsError() APIError {
 	return APIError{
 		Code:    ErrCodeInsufficientFunds,
@@ -91,3 +122,92 @@ func NewSelfTransferError() APIError {
 		Status:  http.StatusBadRequest,
 	}
 }
+
+func NewAccountClosedError() APIError {
+	return APIError{
+		Code:    ErrCodeAccountClosed,
+		Message: "Account is closed",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewAccountFrozenError() APIError {
+	return APIError{
+		Code:    ErrCodeAccountFrozen,
+		Message: "Account is frozen",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewAccountHasBalanceError() APIError {
+	return APIError{
+		Code:    ErrCodeAccountHasBalance,
+		Message: "Account balance must be zero before closing",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewOverdraftInUseError() APIError {
+	return APIError{
+		Code:    ErrCodeOverdraftInUse,
+		Message: "Account is overdrawn by more than the requested limit",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewCurrencyMismatchError() APIError {
+	return APIError{
+		Code:    ErrCodeCurrencyMismatch,
+		Message: "Accounts hold different currencies",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewBalanceLimitExceededError() APIError {
+	return APIError{
+		Code:    ErrCodeBalanceLimitExceeded,
+		Message: "Transfer would exceed the maximum account balance",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewHoldNotFoundError() APIError {
+	return APIError{
+		Code:    ErrCodeHoldNotFound,
+		Message: "Hold not found",
+		Status:  http.StatusNotFound,
+	}
+}
+
+func NewHoldNotActiveError() APIError {
+	return APIError{
+		Code:    ErrCodeHoldNotActive,
+		Message: "Hold has already been released or captured",
+		Status:  http.StatusConflict,
+	}
+}
+
+func NewOperationNotFoundError() APIError {
+	return APIError{
+		Code:    ErrCodeOperationNotFound,
+		Message: "Operation not found",
+		Status:  http.StatusNotFound,
+	}
+}
+
+func NewInvalidIdempotencyKeyError() APIError {
+	return APIError{
+		Code:    ErrCodeInvalidIdempotencyKey,
+		Message: "Invalid Idempotency-Key header",
+		Status:  http.StatusBadRequest,
+	}
+}
+
+// NewPublishFailedError is returned when an asynchronous operation couldn't be queued
+func NewPublishFailedError(operation string) APIError {
+	return APIError{
+		Code:    ErrCodePublishFailed,
+		Message: fmt.Sprintf("Failed to process %s request", operation),
+		Status:  http.StatusInternalServerError,
+	}
+}
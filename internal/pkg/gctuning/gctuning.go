@@ -0,0 +1,43 @@
+// Package gctuning applies garbage collector tuning at startup and reports the effective
+// settings, so GC behaviour under load can be correlated with the knobs that produced it.
+package gctuning
+
+import (
+	"runtime/debug"
+
+	"bank-api/internal/pkg/telemetry"
+)
+
+// ballast is a large allocation that is never read or written. It raises the live heap the
+// GC paces against, so collections run less often without the pages ever being touched.
+var ballast []byte
+
+// Settings are the GC settings in effect after Apply
+type Settings struct {
+	GCPercent    int // from GOGC, applied by the Go runtime itself; -1 means GC is off
+	BallastBytes int
+}
+
+// Apply allocates a ballast of ballastMB megabytes (0 releases any existing ballast) and
+// exports the effective settings through the GC metrics
+func Apply(ballastMB int) Settings {
+	if ballastMB > 0 {
+		ballast = make([]byte, ballastMB<<20)
+	} else {
+		ballast = nil
+	}
+
+	settings := Settings{
+		GCPercent:    currentGCPercent(),
+		BallastBytes: len(ballast),
+	}
+	metrics.UpdateGCTuning(settings.GCPercent, settings.BallastBytes)
+	return settings
+}
+
+// currentGCPercent reads the GC target percentage; the runtime only exposes it through a setter
+func currentGCPercent() int {
+	percent := debug.SetGCPercent(100)
+	debug.SetGCPercent(percent)
+	return percent
+}
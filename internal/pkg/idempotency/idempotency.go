@@ -29,6 +29,42 @@ func GenerateKey(operationType string, accountID int, amount int) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// GenerateKeyWithNonce creates a deterministic idempotency key that also includes a
+// client-supplied nonce, so two intentionally separate operations with the same account
+// and amount get different keys. An empty nonce yields the same key as GenerateKey.
+//
+// Example:
+//   - "deposit:1:1000:abc" → "9f86d081884c7d65..."
+func GenerateKeyWithNonce(operationType string, accountID int, amount int, nonce string) string {
+	if nonce == "" {
+		return GenerateKey(operationType, accountID, amount)
+	}
+
+	// Format: "operation_type:account_id:amount:nonce"
+	data := fmt.Sprintf("%s:%d:%d:%s", operationType, accountID, amount, nonce)
+
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// MaxClientKeyLength is the maximum accepted length of a client-supplied idempotency key
+const MaxClientKeyLength = 128
+
+// GenerateClientKey derives an idempotency key from a client-supplied key (e.g. the
+// Idempotency-Key header). The client key is scoped to the operation type and account so
+// the same value reused on another account can't collide, and hashed so the result always
+// fits the 64-character processed_operations column.
+//
+// Example:
+//   - "deposit:1:client:order-42" → "2c26b46b68ffc68f..."
+func GenerateClientKey(operationType string, accountID int, clientKey string) string {
+	// Format: "operation_type:account_id:client:client_key"
+	data := fmt.Sprintf("%s:%d:client:%s", operationType, accountID, clientKey)
+
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
 // GenerateTransferKey creates a deterministic idempotency key for transfer operations.
 // The key includes both source and destination accounts to ensure uniqueness.
 //
@@ -41,3 +77,17 @@ func GenerateTransferKey(fromAccountID int, toAccountID int, amount int) string
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
 }
+
+// GenerateClientTransferKey derives a transfer idempotency key from the transfer details and a
+// client-supplied key (e.g. the Idempotency-Key header). Retrying the same transfer with the same
+// client key yields the same key; a different client key marks an intentionally separate transfer.
+//
+// Example:
+//   - "transfer:1:2:500:client:order-42" → "e3b0c442..."
+func GenerateClientTransferKey(fromAccountID int, toAccountID int, amount int, clientKey string) string {
+	// Format: "transfer:from_account:to_account:amount:client:client_key"
+	data := fmt.Sprintf("transfer:%d:%d:%d:client:%s", fromAccountID, toAccountID, amount, clientKey)
+
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,29 @@
+// Package keylock provides striped mutexes: a fixed set of locks shared by all keys, so
+// locking per key needs bounded memory no matter how many distinct keys are seen.
+package keylock
+
+import "sync"
+
+// Striped maps every key onto one of a fixed number of mutexes (key mod shards). Keys that
+// share a shard also share a lock, which costs some contention but never correctness.
+type Striped struct {
+	shards []sync.Mutex
+}
+
+// NewStriped creates a lock set with the given number of shards (at least 1)
+func NewStriped(shards int) *Striped {
+	if shards < 1 {
+		shards = 1
+	}
+	return &Striped{shards: make([]sync.Mutex, shards)}
+}
+
+// For returns the mutex guarding key; the same key always gets the same mutex
+func (s *Striped) For(key int) *sync.Mutex {
+	return &s.shards[uint(key)%uint(len(s.shards))]
+}
+
+// Size returns the number of mutexes, which is fixed at construction
+func (s *Striped) Size() int {
+	return len(s.shards)
+}
@@ -4,6 +4,7 @@ import (
 	"bank-api/internal/config"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -58,6 +59,13 @@ func Init(cfg *config.Config) {
 	}
 }
 
+// SetOutput redirects log output (e.g. to a buffer in tests)
+func SetOutput(w io.Writer) {
+	if defaultLogger != nil {
+		defaultLogger.logger.SetOutput(w)
+	}
+}
+
 func parseLevel(levelStr string) Level {
 	switch strings.ToUpper(levelStr) {
 	case "DEBUG":
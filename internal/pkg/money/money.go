@@ -0,0 +1,82 @@
+// Package money converts between the integer minor units amounts are handled in (cents for
+// BRL, yen for JPY, fils for KWD) and the decimal major units they are stored in.
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// DefaultMinorUnits is the number of decimal places assumed for a currency not listed below
+const DefaultMinorUnits = 2
+
+// minorUnits maps ISO 4217 codes to the number of decimal places of their minor unit. The
+// balance_within_overdraft check (migration 000015) repeats these scales and must be kept in step.
+var minorUnits = map[string]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"JPY": 0,
+	"KWD": 3,
+}
+
+// symbols maps ISO 4217 codes to the symbol Format shows; other currencies show their code
+var symbols = map[string]string{
+	"BRL": "R$",
+	"USD": "US$",
+	"EUR": "€",
+	"JPY": "¥",
+}
+
+// MinorUnits returns how many decimal places currency has, e.g. 2 for BRL and 0 for JPY
+func MinorUnits(currency string) int {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+	return DefaultMinorUnits
+}
+
+// Factor returns how many minor units make one major unit, e.g. 100 for BRL and 1 for JPY
+func Factor(currency string) int {
+	factor := 1
+	for i := 0; i < MinorUnits(currency); i++ {
+		factor *= 10
+	}
+	return factor
+}
+
+// ToDecimal converts an amount in minor units to major units, e.g. 1050 BRL cents -> 10.50
+func ToDecimal(amount int, currency string) float64 {
+	return float64(amount) / float64(Factor(currency))
+}
+
+// FromDecimal converts an amount in major units to minor units, e.g. 10.50 BRL -> 1050.
+// It rounds rather than truncates, since 0.29*100 is 28.999... in floating point.
+func FromDecimal(value float64, currency string) int {
+	return int(math.Round(value * float64(Factor(currency))))
+}
+
+// Format renders an amount in minor units for messages, e.g. 1000000 BRL -> "R$ 10,000.00",
+// 10000 JPY -> "¥ 10,000" and 1500 KWD -> "KWD 1.500"
+func Format(amount int, currency string) string {
+	symbol, ok := symbols[currency]
+	if !ok {
+		symbol = currency
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	factor := Factor(currency)
+	units := strconv.Itoa(amount / factor)
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	if digits := MinorUnits(currency); digits > 0 {
+		units += fmt.Sprintf(".%0*d", digits, amount%factor)
+	}
+	return fmt.Sprintf("%s %s%s", symbol, sign, units)
+}
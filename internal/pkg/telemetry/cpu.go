@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicksPerSecond is USER_HZ, the unit of the utime/stime fields in /proc/self/stat.
+// It is 100 on every mainstream Linux architecture; reading it via sysconf would require cgo.
+const clockTicksPerSecond = 100
+
+// Linux process accounting and cgroup CPU limit files
+const (
+	procSelfStat   = "/proc/self/stat"
+	cgroupV2CPUMax = "/sys/fs/cgroup/cpu.max"
+	cgroupV1Quota  = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1Period = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+)
+
+// readProcessCPUTimes returns the user and system CPU time consumed by this process so far.
+// Fails where /proc is unavailable (non-Linux), in which case callers fall back to estimates.
+func readProcessCPUTimes() (user, system time.Duration, err error) {
+	data, err := os.ReadFile(procSelfStat)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseProcStat(string(data))
+}
+
+// parseProcStat extracts utime and stime (fields 14 and 15) from a /proc/<pid>/stat line.
+// The command name (field 2) is parenthesised and may contain spaces, so fields are counted
+// from the last closing parenthesis.
+func parseProcStat(stat string) (user, system time.Duration, err error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, 0, errors.New("malformed /proc stat: missing command name")
+	}
+
+	// fields[0] is field 3 (state), so utime and stime are at indices 11 and 12
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return 0, 0, fmt.Errorf("malformed /proc stat: %d fields after command name", len(fields))
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed /proc stat utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed /proc stat stime: %w", err)
+	}
+
+	return ticksToDuration(utime), ticksToDuration(stime), nil
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicksPerSecond
+}
+
+// cgroupCPUQuota returns the number of cores the container is allowed to use (e.g. 1.5 for
+// a 150000us quota per 100000us period), or 0 when no quota is set or there is no cgroup.
+// cgroup v2 is tried first, then v1.
+func cgroupCPUQuota() float64 {
+	if data, err := os.ReadFile(cgroupV2CPUMax); err == nil {
+		// Format: "<quota> <period>", where quota is "max" when unlimited
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0
+		}
+		return quotaCores(fields[0], fields[1])
+	}
+
+	quota, err := os.ReadFile(cgroupV1Quota)
+	if err != nil {
+		return 0
+	}
+	period, err := os.ReadFile(cgroupV1Period)
+	if err != nil {
+		return 0
+	}
+	// v1 reports -1 when unlimited, which quotaCores treats as no quota
+	return quotaCores(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func quotaCores(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
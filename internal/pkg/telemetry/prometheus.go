@@ -1,13 +1,22 @@
 package metrics
 
 import (
+	"math"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
 )
 
+// HTTPDurationBuckets are the HTTPDuration histogram buckets in seconds
+// Default buckets: 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10
+var HTTPDurationBuckets = prometheus.DefBuckets
+
 // Prometheus metrics for HTTP requests
 var (
 	// HTTP request duration histogram
@@ -15,7 +24,7 @@ var (
 		prometheus.HistogramOpts{
 			Name:    "http_request_duration_seconds",
 			Help:    "Duration of HTTP requests in seconds",
-			Buckets: prometheus.DefBuckets, // Default buckets: 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10
+			Buckets: HTTPDurationBuckets,
 		},
 		[]string{"method", "endpoint", "status_code"},
 	)
@@ -36,6 +45,20 @@ var (
 			Help: "Current number of HTTP requests being served",
 		},
 	)
+
+	// Requests rejected with 503 because MAX_IN_FLIGHT_REQUESTS was reached
+	RequestsShedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "requests_shed_total",
+			Help: "Total number of HTTP requests rejected by load shedding",
+		},
+	)
+)
+
+// Transfer amount bucket thresholds in centavos (upper bounds, inclusive)
+const (
+	TransferAmountSmallMax  = 10000  // R$ 100.00
+	TransferAmountMediumMax = 100000 // R$ 1,000.00
 )
 
 // Prometheus metrics for business operations
@@ -66,6 +89,16 @@ var (
 		},
 	)
 
+	// Transfer processing time by amount bucket, to spot lock contention on large transfers
+	TransferDurationHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "transfer_duration_seconds",
+			Help:    "Duration of transfer database operations in seconds, by amount bucket",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"amount_bucket"}, // small, medium, large
+	)
+
 	// Current account balances distribution
 	AccountBalancesHistogram = promauto.NewHistogram(
 		prometheus.HistogramOpts{
@@ -82,6 +115,65 @@ var (
 			Help: "Current number of active accounts in the system",
 		},
 	)
+
+	// Sum of all account balances per currency
+	TotalBalanceGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "total_balance_minor_units",
+			Help: "Sum of all account balances in the minor unit of each currency",
+		},
+		[]string{"currency"},
+	)
+)
+
+// Kafka producer metrics
+var (
+	// Messages successfully published
+	KafkaProducerMessagesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_producer_messages_total",
+			Help: "Total number of messages successfully published to Kafka",
+		},
+		[]string{"topic"},
+	)
+
+	// Publish failures reported by the broker/client
+	KafkaProducerErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_producer_errors_total",
+			Help: "Total number of Kafka publish failures",
+		},
+		[]string{"topic"},
+	)
+
+	// Messages dropped without reaching the broker (e.g. producer closed, serialization failure)
+	KafkaProducerDroppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_producer_dropped_total",
+			Help: "Total number of messages dropped before being sent to Kafka",
+		},
+		[]string{"topic"},
+	)
+
+	// Error rate since startup: (errors + dropped) / attempts
+	KafkaProducerErrorRate = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "kafka_producer_error_rate",
+			Help: "Ratio of failed or dropped Kafka publishes to total publish attempts",
+		},
+	)
+)
+
+// Kafka consumer metrics
+var (
+	// Messages between the partition's high-water mark and the consumer group's position
+	KafkaConsumerLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_consumer_lag",
+			Help: "Number of messages the consumer group has yet to process, per topic partition",
+		},
+		[]string{"topic", "partition"},
+	)
 )
 
 // System metrics
@@ -125,7 +217,7 @@ var (
 			Name: "go_gc_custom_stats",
 			Help: "Custom Go garbage collection statistics",
 		},
-		[]string{"type"}, // type: pause_total, num_gc, heap_objects
+		[]string{"type"}, // type: pause_total, num_gc, heap_objects, next_gc, gc_cpu_fraction, gc_percent, ballast_bytes
 	)
 
 	// Concurrency metrics
@@ -152,7 +244,7 @@ var (
 			Name: "banking_cpu_stats",
 			Help: "Banking application CPU usage and scheduling statistics",
 		},
-		[]string{"type"}, // type: usage_percent, goroutines_per_cpu, gc_cpu_percent
+		[]string{"type"}, // type: usage_percent, user_percent, system_percent, available_cores, cgroup_quota_cores, goroutines_per_cpu, gc_cpu_percent
 	)
 
 	// Throttling detection
@@ -165,12 +257,24 @@ var (
 	)
 )
 
-// CPU tracking variables
+// Database connection pool metrics
+var (
+	// Connection pool saturation, refreshed periodically from pgxpool.Stat
+	DBPoolMetrics = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "banking_db_pool_stats",
+			Help: "PostgreSQL connection pool statistics",
+		},
+		[]string{"pool", "type"}, // type: acquired_conns, idle_conns, total_conns, max_conns, empty_acquire_count, empty_acquire_wait_seconds, acquire_duration_seconds
+	)
+)
+
+// CPU tracking variables; scrapes may overlap, so they are guarded by cpuMu
 var (
-	lastCPUTime      time.Time
-	lastUserTime     time.Duration
-	lastSystemTime   time.Duration
-	lastRunnableTime time.Duration
+	cpuMu          sync.Mutex
+	lastCPUTime    time.Time
+	lastUserTime   time.Duration
+	lastSystemTime time.Duration
 )
 
 // UpdateSystemMetrics updates system-level metrics
@@ -207,13 +311,20 @@ func UpdateSystemMetrics() {
 	updateCPUMetrics()
 }
 
-// updateCPUMetrics collects CPU usage and throttling metrics
+// updateCPUMetrics collects CPU usage and throttling metrics.
+// usage_percent is the process CPU time (utime+stime from /proc/self/stat) consumed since the
+// previous call, relative to the cores available: the cgroup quota when one is set, otherwise
+// all CPUs. Where /proc is unavailable it falls back to a goroutine-based approximation.
 func updateCPUMetrics() {
+	cpuMu.Lock()
+	defer cpuMu.Unlock()
+
 	now := time.Now()
+	user, system, procErr := readProcessCPUTimes()
 
 	// Initialize on first run
 	if lastCPUTime.IsZero() {
-		lastCPUTime = now
+		lastCPUTime, lastUserTime, lastSystemTime = now, user, system
 		return
 	}
 
@@ -227,18 +338,37 @@ func updateCPUMetrics() {
 	var stats runtime.MemStats
 	runtime.ReadMemStats(&stats)
 
-	// Estimate CPU usage based on goroutine activity and GC
-	// Note: This is an approximation since Go doesn't expose direct CPU usage
 	activeGoroutines := float64(runtime.NumGoroutine())
 	numCPU := float64(runtime.NumCPU())
 
-	// CPU usage approximation (goroutines per CPU as utilization indicator)
-	estimatedCPUUsage := (activeGoroutines / numCPU) * 10 // Scale factor for visibility
-	if estimatedCPUUsage > 100 {
-		estimatedCPUUsage = 100 // Cap at 100%
+	availableCores := numCPU
+	if quota := cgroupCPUQuota(); quota > 0 {
+		availableCores = math.Min(quota, numCPU)
+		CPUMetrics.WithLabelValues("cgroup_quota_cores").Set(quota)
+	}
+	CPUMetrics.WithLabelValues("available_cores").Set(availableCores)
+
+	// Share of the available cores' wall time spent running this process
+	percentOfAvailable := func(cpu time.Duration) float64 {
+		return cpu.Seconds() / (timeDelta * availableCores) * 100
 	}
 
-	CPUMetrics.WithLabelValues("usage_percent").Set(estimatedCPUUsage)
+	var cpuUsage float64
+	if procErr == nil {
+		userDelta, systemDelta := user-lastUserTime, system-lastSystemTime
+		cpuUsage = percentOfAvailable(userDelta + systemDelta)
+		CPUMetrics.WithLabelValues("user_percent").Set(percentOfAvailable(userDelta))
+		CPUMetrics.WithLabelValues("system_percent").Set(percentOfAvailable(systemDelta))
+		CPUUsageGauge.Set((user + system).Seconds())
+	} else {
+		// No process accounting (non-Linux): approximate from goroutines per CPU
+		cpuUsage = (activeGoroutines / numCPU) * 10 // Scale factor for visibility
+	}
+	if cpuUsage > 100 {
+		cpuUsage = 100 // Cap at 100%
+	}
+
+	CPUMetrics.WithLabelValues("usage_percent").Set(cpuUsage)
 	CPUMetrics.WithLabelValues("goroutines_per_cpu").Set(activeGoroutines / numCPU)
 
 	// GC CPU usage as percentage
@@ -262,7 +392,7 @@ func updateCPUMetrics() {
 		ThrottlingMetrics.WithLabelValues("gc_pressure").Set(0)
 	}
 
-	lastCPUTime = now
+	lastCPUTime, lastUserTime, lastSystemTime = now, user, system
 }
 
 // updateCPUCoreMetrics collects CPU core utilization and parallel processing metrics
@@ -306,6 +436,11 @@ func updateCPUCoreMetrics(numCPU, maxProcs, goroutines float64) {
 	CPUCoreMetrics.WithLabelValues("max_parallel_capacity").Set(maxProcs * 1000) // Rough estimate
 }
 
+// RecordRequestShed counts a request rejected by load shedding
+func RecordRequestShed() {
+	RequestsShedTotal.Inc()
+}
+
 // RecordAccountCreation records a new account creation
 func RecordAccountCreation() {
 	AccountsCreatedTotal.Inc()
@@ -322,6 +457,23 @@ func RecordTransferAmount(amount float64) {
 	TransferAmountHistogram.Observe(amount)
 }
 
+// TransferAmountBucket returns the amount_bucket label for a transfer amount in centavos
+func TransferAmountBucket(amount int) string {
+	switch {
+	case amount <= TransferAmountSmallMax:
+		return "small"
+	case amount <= TransferAmountMediumMax:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// RecordTransferDuration records how long a transfer of the given amount took
+func RecordTransferDuration(amount int, duration time.Duration) {
+	TransferDurationHistogram.WithLabelValues(TransferAmountBucket(amount)).Observe(duration.Seconds())
+}
+
 // RecordAccountBalance records an account balance for distribution analysis
 func RecordAccountBalance(balance float64) {
 	AccountBalancesHistogram.Observe(balance)
@@ -331,3 +483,60 @@ func RecordAccountBalance(balance float64) {
 func UpdateActiveAccounts(count float64) {
 	ActiveAccountsGauge.Set(count)
 }
+
+// UpdateTotalBalances replaces the per-currency balance sums, dropping currencies no longer held
+func UpdateTotalBalances(balances map[string]int) {
+	TotalBalanceGauge.Reset()
+	for currency, minorUnits := range balances {
+		TotalBalanceGauge.WithLabelValues(currency).Set(float64(minorUnits))
+	}
+}
+
+// RecordKafkaPublish records the outcome of a Kafka publish (status: success, error, dropped)
+func RecordKafkaPublish(topic, status string) {
+	switch status {
+	case "success":
+		KafkaProducerMessagesTotal.WithLabelValues(topic).Inc()
+	case "error":
+		KafkaProducerErrorsTotal.WithLabelValues(topic).Inc()
+	case "dropped":
+		KafkaProducerDroppedTotal.WithLabelValues(topic).Inc()
+	}
+}
+
+// UpdateKafkaProducerErrorRate sets the producer error rate gauge
+func UpdateKafkaProducerErrorRate(rate float64) {
+	KafkaProducerErrorRate.Set(rate)
+}
+
+// UpdateKafkaConsumerLag sets the consumer lag gauge for a topic partition
+func UpdateKafkaConsumerLag(topic string, partition int32, lag int64) {
+	KafkaConsumerLag.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(lag))
+}
+
+// GetInFlightRequests returns the current value of the in-flight HTTP requests gauge
+func GetInFlightRequests() float64 {
+	var m dto.Metric
+	if err := HTTPRequestsInFlight.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetGauge().GetValue()
+}
+
+// UpdateGCTuning exports the GC target percentage (GOGC) and heap ballast size in effect
+func UpdateGCTuning(gcPercent int, ballastBytes int) {
+	GCMetrics.WithLabelValues("gc_percent").Set(float64(gcPercent))
+	GCMetrics.WithLabelValues("ballast_bytes").Set(float64(ballastBytes))
+}
+
+// UpdateDBPoolStats records a connection pool snapshot. Counts and durations are cumulative
+// since the pool was created; empty_acquire_count is how often a caller had to wait for a connection
+func UpdateDBPoolStats(pool string, stat *pgxpool.Stat) {
+	DBPoolMetrics.WithLabelValues(pool, "acquired_conns").Set(float64(stat.AcquiredConns()))
+	DBPoolMetrics.WithLabelValues(pool, "idle_conns").Set(float64(stat.IdleConns()))
+	DBPoolMetrics.WithLabelValues(pool, "total_conns").Set(float64(stat.TotalConns()))
+	DBPoolMetrics.WithLabelValues(pool, "max_conns").Set(float64(stat.MaxConns()))
+	DBPoolMetrics.WithLabelValues(pool, "empty_acquire_count").Set(float64(stat.EmptyAcquireCount()))
+	DBPoolMetrics.WithLabelValues(pool, "empty_acquire_wait_seconds").Set(stat.EmptyAcquireWaitTime().Seconds())
+	DBPoolMetrics.WithLabelValues(pool, "acquire_duration_seconds").Set(stat.AcquireDuration().Seconds())
+}
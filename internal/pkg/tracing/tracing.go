@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header a client uses to supply its trace ID; the API echoes the
+// resolved ID back in the same header
+const Header = "X-Trace-Id"
+
+// maxTraceIDLength bounds client-supplied IDs, since they end up in every log line and event
+const maxTraceIDLength = 128
+
+type contextKey struct{}
+
+// Resolve returns the client-supplied trace ID when it is usable, or a newly generated one.
+// Blank, oversized, or non-printable values are replaced rather than rejected so tracing
+// never fails a request.
+func Resolve(incoming string) string {
+	id := strings.TrimSpace(incoming)
+	if id == "" || len(id) > maxTraceIDLength || strings.IndexFunc(id, notPrintable) >= 0 {
+		return uuid.New().String()
+	}
+	return id
+}
+
+// NewContext returns a copy of ctx carrying the trace ID
+func NewContext(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, traceID)
+}
+
+// FromContext returns the trace ID carried by ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(contextKey{}).(string)
+	return traceID
+}
+
+func notPrintable(r rune) bool {
+	return r > unicode.MaxASCII || !unicode.IsPrint(r)
+}
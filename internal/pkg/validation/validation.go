@@ -1,24 +1,103 @@
 package validation
 
 import (
+	"bank-api/internal/pkg/money"
 	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
 const (
-	MinAmount   = 1
-	MaxAmount   = 1000000 // R$ 10,000.00 (in centavos)
-	MaxOwnerLen = 100
-	MinOwnerLen = 2
+	DefaultMinAmount  = 1       // 0.01 of the major unit
+	DefaultMaxAmount  = 1000000 // 10,000.00 of the major unit
+	MaxOverdraftLimit = 1000000 // 10,000.00 of the major unit
+	MaxOwnerLen       = 100
+	MinOwnerLen       = 2
 )
 
-func ValidateAmount(amount int) error {
-	if amount < MinAmount {
+// Transaction amount limits in hundredths of a major unit (centavos for BRL), configurable at
+// startup via SetAmountLimits. Every currency applies them at the same face value, so the
+// default maximum is R$ 10,000.00, ¥ 10,000 or KWD 10,000.000.
+var (
+	minAmount = DefaultMinAmount
+	maxAmount = DefaultMaxAmount
+)
+
+// limitScale is how many limit units make one major unit
+const limitScale = 100
+
+// SetAmountLimits changes the range accepted by ValidateAmount. It is meant to be
+// called once during startup, before requests are served.
+func SetAmountLimits(min, max int) error {
+	if min < 1 {
+		return errors.New("minimum amount must be at least 1")
+	}
+	if max < min {
+		return errors.New("maximum amount must not be below the minimum")
+	}
+	minAmount, maxAmount = min, max
+	return nil
+}
+
+// AmountLimits returns the range ValidateAmount accepts for currency, in its minor units.
+// The minimum rounds up, since a currency without cents can't move less than one unit.
+func AmountLimits(currency string) (min, max int) {
+	factor := money.Factor(currency)
+	return (minAmount*factor + limitScale - 1) / limitScale, maxAmount * factor / limitScale
+}
+
+// ValidateAmount checks an amount in the minor units of currency against the configured limits
+func ValidateAmount(amount int, currency string) error {
+	if amount <= 0 {
 		return errors.New("amount must be greater than zero")
 	}
-	if amount > MaxAmount {
-		return errors.New("amount exceeds maximum limit of R$ 10,000.00")
+	min, max := AmountLimits(currency)
+	if amount < min {
+		return fmt.Errorf("amount is below minimum limit of %s", money.Format(min, currency))
+	}
+	if amount > max {
+		return fmt.Errorf("amount exceeds maximum limit of %s", money.Format(max, currency))
+	}
+	return nil
+}
+
+// ParseMoney converts a decimal amount in major units, e.g. "10.50", to exact minor units
+// without going through floating point: 1050 with two minor units, 10500 with three. At most
+// minorUnits decimal places are accepted; signs, exponents and thousands separators are rejected.
+func ParseMoney(s string, minorUnits int) (int, error) {
+	units, fraction, hasPoint := strings.Cut(s, ".")
+	if units == "" || (hasPoint && fraction == "") {
+		return 0, fmt.Errorf("invalid amount %q: expected a decimal such as \"10.50\"", s)
+	}
+	if len(fraction) > minorUnits {
+		return 0, fmt.Errorf("invalid amount %q: at most %d decimal places are allowed", s, minorUnits)
+	}
+	for _, r := range units + fraction {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid amount %q: expected a decimal such as \"10.50\"", s)
+		}
+	}
+
+	// Pad "10.5" to 1050 with two minor units
+	amount, err := strconv.Atoi(units + fraction + strings.Repeat("0", minorUnits-len(fraction)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	return amount, nil
+}
+
+// ValidateOverdraftLimit checks an overdraft limit in the minor units of currency. Like the
+// amount limits, MaxOverdraftLimit applies at the same face value in every currency.
+func ValidateOverdraftLimit(limit int, currency string) error {
+	if limit < 0 {
+		return errors.New("overdraft limit cannot be negative")
+	}
+	if max := MaxOverdraftLimit * money.Factor(currency) / limitScale; limit > max {
+		return fmt.Errorf("overdraft limit exceeds maximum of %s", money.Format(max, currency))
 	}
 	return nil
 }
@@ -44,6 +123,80 @@ func ValidateOwnerName(owner string) error {
 	return nil
 }
 
+// allowPrivateCallbackHosts lets callback URLs target loopback, private and link-local
+// addresses, configurable at startup via SetAllowPrivateCallbackHosts
+var allowPrivateCallbackHosts bool
+
+// SetAllowPrivateCallbackHosts changes whether callbacks may reach internal addresses. It is
+// meant to be called once during startup, and only enabled for local development.
+func SetAllowPrivateCallbackHosts(allow bool) {
+	allowPrivateCallbackHosts = allow
+}
+
+// ValidateCallbackURL accepts absolute http(s) URLs that don't point at localhost or a
+// loopback, private or link-local IP. Hostnames are checked again once resolved, when the
+// callback is delivered (see CallbackAddressAllowed).
+func ValidateCallbackURL(callbackURL string) error {
+	u, err := url.ParseRequestURI(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("callback_url must be an absolute http or https URL")
+	}
+	if allowPrivateCallbackHosts {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("callback_url must not point at a private or loopback host")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !CallbackAddressAllowed(addr) {
+		return errors.New("callback_url must not point at a private or loopback host")
+	}
+	return nil
+}
+
+// CallbackAddressAllowed reports whether a callback may be delivered to addr: loopback,
+// private, link-local and unspecified addresses are refused so a client can't make the
+// service call into the internal network
+func CallbackAddressAllowed(addr netip.Addr) bool {
+	if allowPrivateCallbackHosts {
+		return true
+	}
+	addr = addr.Unmap()
+	return !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsUnspecified()
+}
+
+// supportedCurrencies lists the ISO 4217 codes an account may be opened in; their minor units
+// are defined in the money package
+var supportedCurrencies = map[string]bool{
+	"BRL": true,
+	"USD": true,
+	"EUR": true,
+	"JPY": true,
+	"KWD": true,
+}
+
+func ValidateCurrency(currency string) error {
+	if !supportedCurrencies[currency] {
+		return errors.New("unsupported currency (supported: BRL, USD, EUR, JPY, KWD)")
+	}
+	return nil
+}
+
+// supportedAccountTypes lists the types an account may be opened as
+var supportedAccountTypes = map[string]bool{
+	"checking": true,
+	"savings":  true,
+}
+
+func ValidateAccountType(accountType string) error {
+	if !supportedAccountTypes[accountType] {
+		return errors.New("unsupported account type (supported: checking, savings)")
+	}
+	return nil
+}
+
 func ValidateAccountID(id int) error {
 	if id <= 0 {
 		return errors.New("account ID must be positive")
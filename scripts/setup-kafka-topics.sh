@@ -48,14 +48,23 @@ create_topic() {
 create_topic "banking.accounts.created" \
     "Account creation events"
 
+create_topic "banking.accounts.closed" \
+    "Account closure events"
+
 # Deposit Command and Events
 create_topic "banking.commands.deposit-requests" \
     "Deposit request commands (fire-and-forget)"
 
+create_topic "banking.commands.deposit-requests.dlq" \
+    "Deposit request commands that exhausted retries (dead-letter)"
+
 create_topic "banking.transactions.deposit" \
     "Deposit completion events"
 
-# Withdrawal Events
+# Withdrawal Command and Events
+create_topic "banking.commands.withdrawal-requests" \
+    "Withdrawal request commands (fire-and-forget)"
+
 create_topic "banking.transactions.withdrawal" \
     "Withdrawal completion events"
 
@@ -63,6 +72,10 @@ create_topic "banking.transactions.withdrawal" \
 create_topic "banking.transactions.transfer" \
     "Transfer completion events"
 
+# Interest Events
+create_topic "banking.transactions.interest" \
+    "Scheduled interest accrual events"
+
 # Failed Transaction Events
 create_topic "banking.transactions.failed" \
     "Failed transaction events (audit trail)"
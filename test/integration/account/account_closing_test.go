@@ -0,0 +1,140 @@
+package account
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func closeAccount(router *gin.Engine, accountID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("DELETE", "/accounts/"+strconv.Itoa(accountID), nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func postJSON(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestCloseAccountWithBalanceRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	testenv.SetBalance(t, accountID, 1000)
+
+	resp := closeAccount(router, accountID)
+	require.Equal(t, http.StatusConflict, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, "ACCOUNT_HAS_BALANCE", result["code"])
+
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, models.AccountStatusActive, acc.Status)
+	assert.Empty(t, container.GetEventPublisher().GetAccountClosedEvents())
+
+	assert.ErrorIs(t, db.CloseAccount(context.Background(), accountID), postgres.ErrAccountHasBalance)
+}
+
+func TestCloseAccountAtZeroBalance(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+
+	resp := closeAccount(router, accountID)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, "closed", result["status"])
+
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, models.AccountStatusClosed, acc.Status)
+
+	events := container.GetEventPublisher().GetAccountClosedEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, accountID, events[0].AccountID)
+	assert.Equal(t, "Bob", events[0].Owner)
+
+	// Closing twice is a conflict
+	assert.Equal(t, http.StatusConflict, closeAccount(router, accountID).Code)
+	assert.Len(t, container.GetEventPublisher().GetAccountClosedEvents(), 1)
+}
+
+func TestCloseNonexistentAccount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	assert.Equal(t, http.StatusNotFound, closeAccount(router, 999).Code)
+}
+
+func TestOperationsOnClosedAccountRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	closedID := testenv.CreateAccount(t, router, "Carol")
+	openID := testenv.CreateAccount(t, router, "Dave")
+	testenv.SetBalance(t, openID, 5000)
+
+	require.Equal(t, http.StatusOK, closeAccount(router, closedID).Code)
+
+	// Deposit and withdrawal are rejected before being published
+	resp := postJSON(router, "/accounts/"+strconv.Itoa(closedID)+"/deposit", map[string]int{"amount": 100})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = postJSON(router, "/accounts/"+strconv.Itoa(closedID)+"/withdraw", map[string]int{"amount": 100})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	assert.Empty(t, container.GetEventPublisher().GetDepositRequestedEvents())
+	assert.Empty(t, container.GetEventPublisher().GetWithdrawalRequestedEvents())
+
+	// Transfers in either direction are rejected
+	resp = postJSON(router, "/accounts/transfer", map[string]int{"from": openID, "to": closedID, "amount": 100})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = postJSON(router, "/accounts/transfer", map[string]int{"from": closedID, "to": openID, "amount": 100})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, openID), "Open account balance should be untouched")
+
+	// Requests already in flight are rejected by the repository as well
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), closedID, 100, uuid.New().String())
+	assert.ErrorIs(t, err, postgres.ErrAccountClosed)
+
+	_, err = db.AtomicWithdrawWithIdempotency(context.Background(), closedID, 100, uuid.New().String())
+	assert.ErrorIs(t, err, postgres.ErrAccountClosed)
+}
@@ -0,0 +1,63 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createWithRequestID(t *testing.T, router *gin.Engine, owner, clientRequestID string) (int, int) {
+	t.Helper()
+
+	resp := postJSON(router, "/accounts", map[string]string{"owner": owner, "client_request_id": clientRequestID})
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Contains(t, result, "id", resp.Body.String())
+	return resp.Code, int(result["id"].(float64))
+}
+
+func TestCreateAccountIdempotent(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	// First create inserts a new account
+	status, firstID := createWithRequestID(t, router, "Alice", "signup-42")
+	assert.Equal(t, http.StatusCreated, status)
+
+	// Replaying the request returns the same account instead of a duplicate
+	status, replayID := createWithRequestID(t, router, "Alice", "signup-42")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, firstID, replayID)
+
+	// A different request ID for the same owner is a separate account
+	status, otherID := createWithRequestID(t, router, "Alice", "signup-43")
+	assert.Equal(t, http.StatusCreated, status)
+	assert.NotEqual(t, firstID, otherID)
+
+	// Only the two real creations are announced
+	created := eventPublisher.GetAccountCreatedEvents()
+	require.Len(t, created, 2)
+	assert.Equal(t, firstID, created[0].AccountID)
+	assert.Equal(t, otherID, created[1].AccountID)
+
+	// Requests without an ID keep creating new accounts
+	assert.NotEqual(t, testenv.CreateAccount(t, router, "Alice"), testenv.CreateAccount(t, router, "Alice"))
+}
+
+func TestCreateAccountIdempotent_OversizedRequestID(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	resp := postJSON(router, "/accounts", map[string]string{"owner": "Bruno", "client_request_id": strings.Repeat("x", 129)})
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "VALIDATION_ERROR")
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeBlocksOperationsUntilUnfrozen(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	frozenID := testenv.CreateAccount(t, router, "Alice")
+	otherID := testenv.CreateAccount(t, router, "Bruno")
+	testenv.SetBalance(t, frozenID, 5000)
+	testenv.SetBalance(t, otherID, 5000)
+
+	base := "/accounts/" + strconv.Itoa(frozenID)
+	require.Equal(t, http.StatusOK, postJSON(router, base+"/freeze", nil).Code)
+	eventPublisher.Reset()
+
+	blocked := map[string]int{
+		"deposit":        postJSON(router, base+"/deposit", map[string]int{"amount": 100}).Code,
+		"withdraw":       postJSON(router, base+"/withdraw", map[string]int{"amount": 100}).Code,
+		"transfer out":   postTransfer(router, frozenID, otherID, 100, "").Code,
+		"transfer in":    postTransfer(router, otherID, frozenID, 100, "").Code,
+		"batch transfer": postJSON(router, "/accounts/transfer/batch", map[string]interface{}{"from": otherID, "transfers": []map[string]int{{"to": frozenID, "amount": 100}}}).Code,
+	}
+	for operation, code := range blocked {
+		assert.Equal(t, http.StatusConflict, code, operation)
+	}
+
+	testenv.AssertErrorCode(t, postJSON(router, base+"/deposit", map[string]int{"amount": 100}), http.StatusConflict, "ACCOUNT_FROZEN")
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, frozenID), "Frozen account balance must not change")
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, otherID))
+	assert.Empty(t, eventPublisher.GetDepositRequestedEvents())
+	assert.Empty(t, eventPublisher.GetWithdrawalRequestedEvents())
+
+	require.Equal(t, http.StatusOK, postJSON(router, base+"/unfreeze", nil).Code)
+
+	assert.Equal(t, http.StatusAccepted, postJSON(router, base+"/deposit", map[string]int{"amount": 100}).Code)
+	assert.Equal(t, http.StatusAccepted, postJSON(router, base+"/withdraw", map[string]int{"amount": 100}).Code)
+	require.Equal(t, http.StatusOK, postTransfer(router, frozenID, otherID, 1000, "").Code)
+	assert.Equal(t, 4000, testenv.GetBalance(t, router, frozenID))
+	assert.Equal(t, 6000, testenv.GetBalance(t, router, otherID))
+}
+
+func TestFreezeAccountErrors(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	closedID := testenv.CreateAccount(t, router, "Carla")
+	require.Equal(t, http.StatusOK, closeAccount(router, closedID).Code)
+
+	testenv.AssertErrorCode(t, postJSON(router, "/accounts/999999/freeze", nil), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+	testenv.AssertErrorCode(t, postJSON(router, "/accounts/"+strconv.Itoa(closedID)+"/freeze", nil), http.StatusConflict, "ACCOUNT_CLOSED")
+	testenv.AssertErrorCode(t, postJSON(router, "/accounts/abc/unfreeze", nil), http.StatusBadRequest, "VALIDATION_ERROR")
+}
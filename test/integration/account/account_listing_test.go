@@ -0,0 +1,59 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccounts(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	id := testenv.CreateAccount(t, router, "Helena")
+
+	req := httptest.NewRequest("GET", "/accounts?limit=1000", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Accounts []map[string]interface{} `json:"accounts"`
+		Total    int                      `json:"total"`
+		Limit    int                      `json:"limit"`
+		Offset   int                      `json:"offset"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, 100, body.Limit, "Limit should be capped at 100")
+	assert.Equal(t, 0, body.Offset)
+	assert.GreaterOrEqual(t, body.Total, 1)
+	assert.LessOrEqual(t, len(body.Accounts), 100)
+
+	// Page to the newest account (accounts are ordered by ID)
+	req = httptest.NewRequest("GET", "/accounts?limit=1&offset="+strconv.Itoa(body.Total-1), nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Accounts, 1)
+	assert.Equal(t, float64(id), body.Accounts[0]["id"])
+	assert.Equal(t, "Helena", body.Accounts[0]["owner_name"])
+}
+
+func TestListAccountsInvalidParams(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	for _, query := range []string{"limit=0", "limit=abc", "offset=-1", "offset=x"} {
+		req := httptest.NewRequest("GET", "/accounts?"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
@@ -0,0 +1,114 @@
+package account
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func softDeleteAccount(router *gin.Engine, accountID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("DELETE", "/accounts/"+strconv.Itoa(accountID)+"?soft=true", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func getAccountPath(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestSoftDeleteAndRestoreAccount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	ctx := context.Background()
+
+	// Give the account some history, ending at a zero balance
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	_, err := db.AtomicDepositWithIdempotency(ctx, accountID, 1000, "soft-delete-deposit")
+	require.NoError(t, err)
+	_, err = db.AtomicWithdraw(ctx, accountID, 1000)
+	require.NoError(t, err)
+
+	resp := softDeleteAccount(router, accountID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// Gone from reads and operations
+	balancePath := "/accounts/" + strconv.Itoa(accountID) + "/balance"
+	testenv.AssertErrorCode(t, getAccountPath(router, balancePath), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+	_, ok := db.GetAccount(ctx, accountID)
+	assert.False(t, ok)
+	_, err = db.AtomicDepositWithIdempotency(ctx, accountID, 500, "soft-delete-after")
+	assert.ErrorIs(t, err, postgres.ErrAccountNotFound)
+	assert.ErrorIs(t, db.SetFrozen(ctx, accountID, true), postgres.ErrAccountNotFound)
+
+	accounts, _, err := db.ListAccounts(ctx, 100, 0)
+	require.NoError(t, err)
+	for _, acc := range accounts {
+		assert.NotEqual(t, accountID, acc.Id, "Soft-deleted accounts should not be listed")
+	}
+
+	// Deleting again finds nothing to delete
+	testenv.AssertErrorCode(t, softDeleteAccount(router, accountID), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+
+	// The transactions are untouched
+	history, err := db.GetTransactionHistory(ctx, accountID, 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	// Restoring brings back the account with its history
+	resp = postJSON(router, "/accounts/"+strconv.Itoa(accountID)+"/restore", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	assert.Equal(t, 0, testenv.GetBalance(t, router, accountID))
+	resp = getAccountPath(router, "/accounts/"+strconv.Itoa(accountID)+"/transactions")
+	require.Equal(t, http.StatusOK, resp.Code)
+	history, err = db.GetTransactionHistory(ctx, accountID, 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	_, err = db.AtomicDepositWithIdempotency(ctx, accountID, 500, "soft-delete-restored")
+	assert.NoError(t, err)
+}
+
+func TestSoftDeleteAccountWithBalanceRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+	testenv.SetBalance(t, accountID, 1000)
+
+	testenv.AssertErrorCode(t, softDeleteAccount(router, accountID), http.StatusConflict, "ACCOUNT_HAS_BALANCE")
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, accountID))
+}
+
+func TestRestoreAccount_Errors(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	testenv.AssertErrorCode(t, postJSON(router, "/accounts/999999/restore", nil), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+
+	// A live account is left as it is
+	accountID := testenv.CreateAccount(t, router, "Carol")
+	resp := postJSON(router, "/accounts/"+strconv.Itoa(accountID)+"/restore", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req := httptest.NewRequest("DELETE", "/accounts/"+strconv.Itoa(accountID)+"?soft=maybe", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "VALIDATION_ERROR")
+}
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccountType(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	resp := postJSON(router, "/accounts", map[string]string{"owner": "Vera", "type": "savings"})
+	require.Equal(t, http.StatusCreated, resp.Code)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	assert.Equal(t, "savings", created["account_type"])
+	savingsID := int(created["id"].(float64))
+
+	checkingID := testenv.CreateAccount(t, router, "Wagner")
+
+	for id, want := range map[int]string{savingsID: "savings", checkingID: "checking"} {
+		req := httptest.NewRequest("GET", "/accounts/"+strconv.Itoa(id)+"/balance", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, want, result["account_type"])
+	}
+
+	resp = postJSON(router, "/accounts", map[string]string{"owner": "Xuxa", "type": "brokerage"})
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "VALIDATION_ERROR")
+}
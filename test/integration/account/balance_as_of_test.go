@@ -0,0 +1,86 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getBalanceAsOf(t *testing.T, router *gin.Engine, accountID int, asOf string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	path := "/accounts/" + strconv.Itoa(accountID) + "/balance?as_of=" + url.QueryEscape(asOf)
+	req := httptest.NewRequest("GET", path, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestGetBalanceAsOf builds a deposit/withdraw/transfer timeline and checks the balance
+// reported at each transaction's timestamp, before the first one, and after the last
+func TestGetBalanceAsOf(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	ctx := context.Background()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	otherID := testenv.CreateAccount(t, router, "Bruno")
+
+	_, err := db.AtomicDepositWithIdempotency(ctx, accountID, 10000, uuid.New().String())
+	require.NoError(t, err)
+	_, err = db.AtomicWithdraw(ctx, accountID, 2500)
+	require.NoError(t, err)
+	_, _, err = db.AtomicTransfer(ctx, accountID, otherID, 1500)
+	require.NoError(t, err)
+	_, err = db.AtomicDepositWithIdempotency(ctx, accountID, 4000, uuid.New().String())
+	require.NoError(t, err)
+
+	history, err := db.GetTransactionHistory(ctx, accountID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	// History is most recent first; each point in time sees the balance after that transaction
+	expected := []int{10000, 7500, 6000, 10000}
+	for i, want := range expected {
+		createdAt := history[len(history)-1-i]["created_at"].(time.Time)
+
+		resp := getBalanceAsOf(t, router, accountID, createdAt.Format(time.RFC3339Nano))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, float64(want), result["balance"], "as of transaction %d", i+1)
+	}
+
+	// Before the first transaction the account held nothing
+	first := history[len(history)-1]["created_at"].(time.Time)
+	resp := getBalanceAsOf(t, router, accountID, first.Add(-time.Millisecond).Format(time.RFC3339Nano))
+	require.Equal(t, http.StatusOK, resp.Code)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, float64(0), result["balance"])
+
+	// After the last one it matches the current balance
+	resp = getBalanceAsOf(t, router, accountID, time.Now().Add(time.Hour).Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, float64(testenv.GetBalance(t, router, accountID)), result["balance"])
+
+	testenv.AssertErrorCode(t, getBalanceAsOf(t, router, accountID, "yesterday"), http.StatusBadRequest, "VALIDATION_ERROR")
+	testenv.AssertErrorCode(t, getBalanceAsOf(t, router, 999999, time.Now().Format(time.RFC3339)), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+}
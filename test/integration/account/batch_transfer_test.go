@@ -0,0 +1,142 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferSuccess(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+
+	from := testenv.CreateAccount(t, router, "Payroll")
+	alice := testenv.CreateAccount(t, router, "Alice")
+	bob := testenv.CreateAccount(t, router, "Bob")
+	testenv.SetBalance(t, from, 1000)
+
+	resp := postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from": from,
+		"transfers": []map[string]int{
+			{"to": alice, "amount": 300},
+			{"to": bob, "amount": 200},
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, float64(500), result["from_balance"])
+	assert.Equal(t, float64(500), result["total_transferred"])
+
+	assert.Equal(t, 500, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 300, testenv.GetBalance(t, router, alice))
+	assert.Equal(t, 200, testenv.GetBalance(t, router, bob))
+
+	events := container.GetEventPublisher().GetTransferCompletedEvents()
+	require.Len(t, events, 2)
+	assert.Equal(t, alice, events[0].ToAccountID)
+	assert.Equal(t, 700, events[0].FromBalanceAfter)
+	assert.Equal(t, bob, events[1].ToAccountID)
+	assert.Equal(t, 500, events[1].FromBalanceAfter)
+}
+
+// TestBatchTransferInsufficientFundsIsAllOrNothing verifies that when the batch total exceeds
+// the source balance no leg is applied, even the ones the balance alone could have covered
+func TestBatchTransferInsufficientFundsIsAllOrNothing(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+
+	from := testenv.CreateAccount(t, router, "Payroll")
+	alice := testenv.CreateAccount(t, router, "Alice")
+	bob := testenv.CreateAccount(t, router, "Bob")
+	testenv.SetBalance(t, from, 1000)
+
+	resp := postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from": from,
+		"transfers": []map[string]int{
+			{"to": alice, "amount": 600},
+			{"to": bob, "amount": 600},
+		},
+	})
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 0, testenv.GetBalance(t, router, alice))
+	assert.Equal(t, 0, testenv.GetBalance(t, router, bob))
+
+	assert.Empty(t, container.GetEventPublisher().GetTransferCompletedEvents())
+	assert.Empty(t, testenv.GetTransactionHistory(t, router, from, 50))
+	assert.Empty(t, testenv.GetTransactionHistory(t, router, alice, 50))
+}
+
+func TestBatchTransferUnknownTargetRollsBack(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+
+	from := testenv.CreateAccount(t, router, "Payroll")
+	alice := testenv.CreateAccount(t, router, "Alice")
+	testenv.SetBalance(t, from, 1000)
+
+	resp := postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from": from,
+		"transfers": []map[string]int{
+			{"to": alice, "amount": 100},
+			{"to": 999999, "amount": 100},
+		},
+	})
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 0, testenv.GetBalance(t, router, alice))
+}
+
+// TestBatchTransferDecimalAmounts verifies each leg accepts a decimal string parsed in the
+// source account's currency, like a single transfer
+func TestBatchTransferDecimalAmounts(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+
+	from := testenv.CreateAccountWithCurrency(t, router, "Payroll", "KWD")
+	alice := testenv.CreateAccountWithCurrency(t, router, "Alice", "KWD")
+	bob := testenv.CreateAccountWithCurrency(t, router, "Bob", "KWD")
+	testenv.SetBalance(t, from, 5000)
+
+	resp := postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from": from,
+		"transfers": []map[string]interface{}{
+			{"to": alice, "amount": "1.250"},
+			{"to": bob, "amount": 500},
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, 3250, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 1250, testenv.GetBalance(t, router, alice), "Dinars have three decimal places")
+	assert.Equal(t, 500, testenv.GetBalance(t, router, bob))
+
+	resp = postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from": from,
+		"transfers": []map[string]interface{}{
+			{"to": alice, "amount": "1.2505"},
+		},
+	})
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "INVALID_AMOUNT")
+	assert.Equal(t, 3250, testenv.GetBalance(t, router, from))
+}
@@ -0,0 +1,94 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCreateAccounts(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	resp := postJSON(router, "/accounts/bulk", map[string]interface{}{
+		"count":           100,
+		"initial_balance": 5000,
+		"owner_prefix":    "Seed",
+	})
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var result struct {
+		IDs   []int `json:"ids"`
+		Count int   `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Len(t, result.IDs, 100)
+	assert.Equal(t, 100, result.Count)
+
+	seen := make(map[int]bool, len(result.IDs))
+	for i, id := range result.IDs {
+		assert.False(t, seen[id], "Account ID %d returned twice", id)
+		seen[id] = true
+
+		acc, ok := db.GetAccount(context.Background(), id)
+		require.True(t, ok, "Account %d should exist", id)
+		assert.Equal(t, 5000, acc.Balance)
+		assert.Equal(t, "Seed-"+strconv.Itoa(i+1), acc.Owner)
+	}
+
+	// The opening credit is part of the history, so point-in-time reads agree with the balance
+	history, err := db.GetTransactionHistory(context.Background(), result.IDs[0], 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestBulkCreateAccounts_WithoutInitialBalance(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	resp := postJSON(router, "/accounts/bulk", map[string]interface{}{"count": 3, "owner_prefix": "Empty"})
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var result struct {
+		IDs []int `json:"ids"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Len(t, result.IDs, 3)
+	for _, id := range result.IDs {
+		assert.Equal(t, 0, testenv.GetBalance(t, router, id))
+	}
+}
+
+func TestBulkCreateAccounts_InvalidRequests(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		code string
+	}{
+		{name: "zero count", body: map[string]interface{}{"count": 0, "owner_prefix": "Seed"}, code: "VALIDATION_ERROR"},
+		{name: "count over limit", body: map[string]interface{}{"count": 1001, "owner_prefix": "Seed"}, code: "VALIDATION_ERROR"},
+		{name: "missing prefix", body: map[string]interface{}{"count": 5}, code: "VALIDATION_ERROR"},
+		{name: "invalid prefix", body: map[string]interface{}{"count": 5, "owner_prefix": "Seed!"}, code: "VALIDATION_ERROR"},
+		{name: "negative balance", body: map[string]interface{}{"count": 5, "owner_prefix": "Seed", "initial_balance": -100}, code: "INVALID_AMOUNT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postJSON(router, "/accounts/bulk", tt.body)
+			testenv.AssertErrorCode(t, resp, http.StatusBadRequest, tt.code)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package account
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessMetrics(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	alice := testenv.CreateAccount(t, router, "Alice")
+	bob := testenv.CreateAccount(t, router, "Bob")
+	closed := testenv.CreateAccount(t, router, "Carol")
+	require.NoError(t, db.CloseAccount(context.Background(), closed))
+	yen := testenv.CreateAccountWithCurrency(t, router, "Emi", "JPY")
+	deleted := testenv.CreateAccount(t, router, "Dave")
+	require.NoError(t, db.SoftDeleteAccount(context.Background(), deleted))
+
+	// Deposits write transaction rows; SetBalance doesn't
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), alice, 1250, uuid.New().String())
+	require.NoError(t, err)
+	_, err = db.AtomicDepositWithIdempotency(context.Background(), bob, 3000, uuid.New().String())
+	require.NoError(t, err)
+	testenv.SetBalance(t, bob, 5)
+	_, err = db.AtomicDepositWithIdempotency(context.Background(), yen, 700, uuid.New().String())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/metrics/business", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var aggregates postgres.Aggregates
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &aggregates))
+	assert.Equal(t, int64(3), aggregates.ActiveAccounts, "Closed and deleted accounts are not active")
+	assert.Equal(t, map[string]int{"BRL": 4255, "JPY": 700}, aggregates.TotalBalance, "Currencies are totalled separately")
+	assert.Equal(t, int64(3), aggregates.TransactionsLastHour)
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.ActiveAccountsGauge))
+	assert.Equal(t, 4255.0, testutil.ToFloat64(metrics.TotalBalanceGauge.WithLabelValues("BRL")))
+	assert.Equal(t, 700.0, testutil.ToFloat64(metrics.TotalBalanceGauge.WithLabelValues("JPY")))
+}
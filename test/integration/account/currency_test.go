@@ -0,0 +1,109 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccountCurrency(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	defaultID := testenv.CreateAccount(t, router, "Xavier")
+	usdID := testenv.CreateAccountWithCurrency(t, router, "Yara", "USD")
+
+	for id, want := range map[int]string{defaultID: "BRL", usdID: "USD"} {
+		req := httptest.NewRequest("GET", "/accounts/"+strconv.Itoa(id)+"/balance", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, want, result["currency"])
+	}
+
+	resp := postJSON(router, "/accounts", map[string]string{"owner": "Zeca", "currency": "XYZ"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "Unsupported currency should be rejected")
+}
+
+// TestCreateAccountMinorUnits checks the create response reports the currency's minor units
+// and that amounts on a JPY account are whole yen
+func TestCreateAccountMinorUnits(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	for currency, want := range map[string]float64{"BRL": 2, "JPY": 0, "KWD": 3} {
+		resp := postJSON(router, "/accounts", map[string]string{"owner": "Yuki", "currency": currency})
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, want, result["minor_units"], currency)
+	}
+
+	fromID := testenv.CreateAccountWithCurrency(t, router, "Haruto", "JPY")
+	toID := testenv.CreateAccountWithCurrency(t, router, "Sakura", "JPY")
+	testenv.SetBalance(t, fromID, 5000)
+
+	resp := postTransfer(router, fromID, toID, 1999, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, 3001, testenv.GetBalance(t, router, fromID))
+	assert.Equal(t, 1999, testenv.GetBalance(t, router, toID))
+}
+
+func TestTransferSameCurrency(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	fromID := testenv.CreateAccountWithCurrency(t, router, "Alice", "EUR")
+	toID := testenv.CreateAccountWithCurrency(t, router, "Bruno", "EUR")
+	testenv.SetBalance(t, fromID, 5000)
+
+	resp := postTransfer(router, fromID, toID, 2000, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, 3000, testenv.GetBalance(t, router, fromID))
+	assert.Equal(t, 2000, testenv.GetBalance(t, router, toID))
+}
+
+func TestTransferCrossCurrencyRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	fromID := testenv.CreateAccountWithCurrency(t, router, "Carla", "BRL")
+	toID := testenv.CreateAccountWithCurrency(t, router, "Diego", "USD")
+	testenv.SetBalance(t, fromID, 5000)
+	eventPublisher.Reset()
+
+	resp := postTransfer(router, fromID, toID, 2000, "")
+	require.Equal(t, http.StatusConflict, resp.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "CURRENCY_MISMATCH", body["code"])
+
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, fromID), "Rejected transfer must not move money")
+	assert.Equal(t, 0, testenv.GetBalance(t, router, toID))
+	assert.Empty(t, eventPublisher.GetTransferCompletedEvents())
+
+	// Batch transfers apply the same rule to every leg
+	resp = postJSON(router, "/accounts/transfer/batch", map[string]interface{}{
+		"from":      fromID,
+		"transfers": []map[string]int{{"to": toID, "amount": 1000}},
+	})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, fromID))
+}
@@ -1,12 +1,15 @@
 package account
 
 import (
+	"bank-api/internal/pkg/idempotency"
+	"bank-api/internal/pkg/validation"
 	"bank-api/test/integration/testenv"
 	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -81,3 +84,174 @@ func TestDepositNonexistentAccount(t *testing.T) {
 	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
 	testenv.AssertHasError(t, result)
 }
+
+func postDeposit(router http.Handler, accountID int, body map[string]interface{}, idempotencyKey *string) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/deposit", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != nil {
+		req.Header.Set("Idempotency-Key", *idempotencyKey)
+	}
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestDepositIdempotencyKeyHeader(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Nicolas")
+	body := map[string]interface{}{"amount": 1000}
+
+	keyA := "order-42"
+	keyB := "order-43"
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, body, &keyA).Code)
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, body, &keyA).Code) // client retry
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, body, &keyB).Code) // distinct deposit
+
+	events := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, events, 3)
+
+	expectedA := idempotency.GenerateClientKey("deposit", accountID, keyA)
+	assert.Equal(t, expectedA, events[0].IdempotencyKey)
+	assert.Equal(t, expectedA, events[1].IdempotencyKey, "Retry with the same header should reuse the key")
+	assert.Equal(t, idempotency.GenerateClientKey("deposit", accountID, keyB), events[2].IdempotencyKey)
+	assert.NotEqual(t, events[0].IdempotencyKey, events[2].IdempotencyKey)
+
+	// Header takes precedence over the deterministic (account, amount) key
+	assert.NotEqual(t, idempotency.GenerateKey("deposit", accountID, 1000), events[0].IdempotencyKey)
+	assert.Len(t, events[0].IdempotencyKey, 64)
+}
+
+func TestDepositWithoutIdempotencyKeyHeader(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Nicolas")
+
+	// No header, no nonce: deterministic key from (account, amount)
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, map[string]interface{}{"amount": 1000}, nil).Code)
+
+	// No header, with nonce: nonce is mixed into the key
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, map[string]interface{}{"amount": 1000, "nonce": "n1"}, nil).Code)
+	require.Equal(t, http.StatusAccepted, postDeposit(router, accountID, map[string]interface{}{"amount": 1000, "nonce": "n2"}, nil).Code)
+
+	events := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, idempotency.GenerateKey("deposit", accountID, 1000), events[0].IdempotencyKey)
+	assert.Equal(t, idempotency.GenerateKeyWithNonce("deposit", accountID, 1000, "n1"), events[1].IdempotencyKey)
+	assert.Equal(t, idempotency.GenerateKeyWithNonce("deposit", accountID, 1000, "n2"), events[2].IdempotencyKey)
+	assert.NotEqual(t, events[0].IdempotencyKey, events[1].IdempotencyKey)
+	assert.NotEqual(t, events[1].IdempotencyKey, events[2].IdempotencyKey)
+}
+
+func TestDepositInvalidIdempotencyKeyHeader(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	accountID := testenv.CreateAccount(t, router, "Nicolas")
+	body := map[string]interface{}{"amount": 1000}
+
+	empty := "   "
+	tooLong := strings.Repeat("k", idempotency.MaxClientKeyLength+1)
+
+	assert.Equal(t, http.StatusBadRequest, postDeposit(router, accountID, body, &empty).Code)
+	assert.Equal(t, http.StatusBadRequest, postDeposit(router, accountID, body, &tooLong).Code)
+	assert.Empty(t, container.GetEventPublisher().GetDepositRequestedEvents())
+}
+
+func TestDepositOverMaxAmountRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	accountID := testenv.CreateAccount(t, router, "Nicolas")
+
+	resp := postDeposit(router, accountID, map[string]interface{}{"amount": validation.DefaultMaxAmount + 1}, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, "INVALID_AMOUNT", result["code"])
+	assert.Contains(t, result["message"], "exceeds maximum limit")
+	assert.Empty(t, container.GetEventPublisher().GetDepositRequestedEvents(), "Rejected deposit must not be published")
+
+	// The maximum itself is accepted
+	resp = postDeposit(router, accountID, map[string]interface{}{"amount": validation.DefaultMaxAmount}, nil)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+}
+
+func TestDepositDecimalStringAmount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Nicolas")
+
+	// Decimal strings are reais, converted to exact centavos
+	resp := postDeposit(router, accountID, map[string]interface{}{"amount": "10.50"}, nil)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	resp = postDeposit(router, accountID, map[string]interface{}{"amount": "0.01"}, nil)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	events := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, events, 2)
+	assert.Equal(t, 1050, events[0].Amount)
+	assert.Equal(t, 1, events[1].Amount)
+
+	// Sub-centavo precision and floats are rejected rather than rounded
+	resp = postDeposit(router, accountID, map[string]interface{}{"amount": "10.505"}, nil)
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "INVALID_AMOUNT")
+	resp = postDeposit(router, accountID, map[string]interface{}{"amount": 10.5}, nil)
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "INVALID_AMOUNT")
+	assert.Len(t, eventPublisher.GetDepositRequestedEvents(), 2)
+}
+
+// TestDepositDecimalStringAmountPerCurrency parses decimal strings with the account currency's
+// decimal places and applies the limits at the same face value
+func TestDepositDecimalStringAmountPerCurrency(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	yenID := testenv.CreateAccountWithCurrency(t, router, "Hiro", "JPY")
+	dinarID := testenv.CreateAccountWithCurrency(t, router, "Layla", "KWD")
+
+	resp := postDeposit(router, yenID, map[string]interface{}{"amount": "1050"}, nil)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	resp = postDeposit(router, dinarID, map[string]interface{}{"amount": "10.505"}, nil)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	events := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, events, 2)
+	assert.Equal(t, 1050, events[0].Amount, "Yen have no minor unit")
+	assert.Equal(t, 10505, events[1].Amount, "Dinars have three decimal places")
+
+	// Yen can't be split, and the maximum is 10,000 of the currency rather than 10,000.00 in centavos
+	resp = postDeposit(router, yenID, map[string]interface{}{"amount": "10.5"}, nil)
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "INVALID_AMOUNT")
+	resp = postDeposit(router, yenID, map[string]interface{}{"amount": 10001}, nil)
+	testenv.AssertErrorCode(t, resp, http.StatusBadRequest, "INVALID_AMOUNT")
+	assert.Len(t, eventPublisher.GetDepositRequestedEvents(), 2)
+}
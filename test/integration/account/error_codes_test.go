@@ -0,0 +1,93 @@
+package account
+
+import (
+	"bank-api/internal/pkg/validation"
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorResponsesUseStructuredCodes checks every handler failure mode answers with
+// the {"code", "message", "status"} envelope
+func TestErrorResponsesUseStructuredCodes(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	activeID := testenv.CreateAccount(t, router, "Ana")
+	otherID := testenv.CreateAccount(t, router, "Beto")
+	closedID := testenv.CreateAccount(t, router, "Caio")
+	usdID := testenv.CreateAccountWithCurrency(t, router, "Dora", "USD")
+	testenv.SetBalance(t, activeID, 1000)
+
+	require.Equal(t, http.StatusOK, closeAccount(router, closedID).Code)
+
+	deposit := "/accounts/" + strconv.Itoa(activeID) + "/deposit"
+	withdraw := "/accounts/" + strconv.Itoa(activeID) + "/withdraw"
+
+	cases := []struct {
+		name   string
+		resp   *httptest.ResponseRecorder
+		status int
+		code   string
+	}{
+		// account.go
+		{"create: invalid owner", postJSON(router, "/accounts", map[string]string{"owner": "x"}), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"create: unsupported currency", postJSON(router, "/accounts", map[string]string{"owner": "Eva", "currency": "XYZ"}), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"close: has balance", closeAccount(router, activeID), http.StatusConflict, "ACCOUNT_HAS_BALANCE"},
+		{"close: not found", closeAccount(router, 999999), http.StatusNotFound, "ACCOUNT_NOT_FOUND"},
+
+		// deposit.go
+		{"deposit: invalid id", postJSON(router, "/accounts/abc/deposit", map[string]int{"amount": 100}), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"deposit: malformed body", postRaw(router, deposit, "{"), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"deposit: zero amount", postJSON(router, deposit, map[string]int{"amount": 0}), http.StatusBadRequest, "INVALID_AMOUNT"},
+		{"deposit: over limit", postJSON(router, deposit, map[string]int{"amount": validation.DefaultMaxAmount + 1}), http.StatusBadRequest, "INVALID_AMOUNT"},
+		{"deposit: not found", postJSON(router, "/accounts/999999/deposit", map[string]int{"amount": 100}), http.StatusNotFound, "ACCOUNT_NOT_FOUND"},
+		{"deposit: closed", postJSON(router, "/accounts/"+strconv.Itoa(closedID)+"/deposit", map[string]int{"amount": 100}), http.StatusConflict, "ACCOUNT_CLOSED"},
+		{"deposit: blank idempotency key", postWithKey(router, deposit, "   "), http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY"},
+		{"deposit: unknown field", postJSON(router, deposit, map[string]int{"amount": 100, "ammount": 100}), http.StatusBadRequest, "VALIDATION_ERROR"},
+
+		// withdraw.go
+		{"withdraw: invalid id", postJSON(router, "/accounts/abc/withdraw", map[string]int{"amount": 100}), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"withdraw: malformed body", postRaw(router, withdraw, "{"), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"withdraw: negative amount", postJSON(router, withdraw, map[string]int{"amount": -5}), http.StatusBadRequest, "INVALID_AMOUNT"},
+		{"withdraw: not found", postJSON(router, "/accounts/999999/withdraw", map[string]int{"amount": 100}), http.StatusNotFound, "ACCOUNT_NOT_FOUND"},
+		{"withdraw: closed", postJSON(router, "/accounts/"+strconv.Itoa(closedID)+"/withdraw", map[string]int{"amount": 100}), http.StatusConflict, "ACCOUNT_CLOSED"},
+
+		// transfer.go
+		{"transfer: insufficient funds", postTransfer(router, activeID, otherID, 5000, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS"},
+		{"transfer: self", postTransfer(router, activeID, activeID, 100, ""), http.StatusBadRequest, "SELF_TRANSFER_NOT_ALLOWED"},
+		{"transfer: not found", postTransfer(router, activeID, 999999, 100, ""), http.StatusNotFound, "ACCOUNT_NOT_FOUND"},
+		{"transfer: closed", postTransfer(router, activeID, closedID, 100, ""), http.StatusConflict, "ACCOUNT_CLOSED"},
+		{"transfer: currency mismatch", postTransfer(router, activeID, usdID, 100, ""), http.StatusConflict, "CURRENCY_MISMATCH"},
+		{"transfer: blank idempotency key", postTransfer(router, activeID, otherID, 100, "   "), http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY"},
+		{"transfer: unknown field", postJSON(router, "/accounts/transfer", map[string]int{"from": activeID, "to": otherID, "amount": 100, "fee": 1}), http.StatusBadRequest, "VALIDATION_ERROR"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testenv.AssertErrorCode(t, tc.resp, tc.status, tc.code)
+		})
+	}
+}
+
+func postRaw(router http.Handler, path string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func postWithKey(router http.Handler, path string, idempotencyKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(`{"amount": 100}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
@@ -105,7 +105,16 @@ func TestWithdrawalEventPublished(t *testing.T) {
 
 	router.ServeHTTP(resp, req)
 
-	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	// Verify withdrawal request was published
+	requested := eventPublisher.GetWithdrawalRequestedEvents()
+	require.Len(t, requested, 1, "Expected exactly one WithdrawalRequestedEvent")
+	assert.Equal(t, accountID, requested[0].AccountID)
+	assert.NotEmpty(t, requested[0].IdempotencyKey)
+
+	// Simulate the consumer processing the request
+	container.ProcessWithdrawalRequests(t)
 
 	// Verify withdrawal event was captured
 	events := eventPublisher.GetWithdrawalCompletedEvents()
@@ -183,6 +192,7 @@ func TestMultipleOperationsEventSequence(t *testing.T) {
 	// It directly updates the database, bypassing the async deposit mechanism
 	testenv.SetBalance(t, accountID, 1500) // Set balance directly for withdraw test
 	testenv.Withdraw(t, router, accountID, 300)
+	container.ProcessWithdrawalRequests(t)
 
 	// Verify all events were captured
 	accountEvents := eventPublisher.GetAccountCreatedEvents()
@@ -218,6 +228,7 @@ func TestEventCaptureReset(t *testing.T) {
 	assert.Len(t, eventPublisher.GetAccountCreatedEvents(), 0)
 	assert.Len(t, eventPublisher.GetDepositRequestedEvents(), 0)
 	assert.Len(t, eventPublisher.GetDepositCompletedEvents(), 0)
+	assert.Len(t, eventPublisher.GetWithdrawalRequestedEvents(), 0)
 	assert.Len(t, eventPublisher.GetWithdrawalCompletedEvents(), 0)
 	assert.Len(t, eventPublisher.GetTransferCompletedEvents(), 0)
 	assert.Len(t, eventPublisher.GetTransactionFailedEvents(), 0)
@@ -248,10 +259,16 @@ func TestFailedOperationNoEvent(t *testing.T) {
 
 	router.ServeHTTP(resp, req)
 
-	// Verify request failed
-	require.Equal(t, http.StatusBadRequest, resp.Code)
+	// Request is accepted; funds are checked asynchronously by the consumer
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	container.ProcessWithdrawalRequests(t)
 
 	// Verify no withdrawal event was published (since operation failed)
 	withdrawalEvents := eventPublisher.GetWithdrawalCompletedEvents()
 	assert.Len(t, withdrawalEvents, 0, "Failed withdrawal should not publish WithdrawalCompletedEvent")
+
+	// Verify the failure was published instead
+	failedEvents := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failedEvents, 1, "Failed withdrawal should publish TransactionFailedEvent")
+	assert.Equal(t, "withdrawal", failedEvents[0].TransactionType)
 }
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthzHealthy(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Status     string `json:"status"`
+		Components map[string]struct {
+			Status    string  `json:"status"`
+			LatencyMs float64 `json:"latency_ms"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+
+	assert.Equal(t, "healthy", body.Status)
+	for _, name := range []string{"database", "kafka"} {
+		require.Contains(t, body.Components, name)
+		assert.Equal(t, "up", body.Components[name].Status, name)
+		assert.GreaterOrEqual(t, body.Components[name].LatencyMs, 0.0, name)
+	}
+}
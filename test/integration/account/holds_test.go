@@ -0,0 +1,107 @@
+package account
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/test/integration/testenv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldReservesFundsUntilCaptured(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	otherID := testenv.CreateAccount(t, router, "Bruno")
+	testenv.SetBalance(t, accountID, 5000)
+	eventPublisher.Reset()
+
+	holdID := placeHold(t, router, accountID, 3000)
+
+	// 2000 of the 5000 balance is still available
+	require.Equal(t, http.StatusAccepted, postJSON(router, "/accounts/"+strconv.Itoa(accountID)+"/withdraw", map[string]int{"amount": 2500}).Code)
+	container.ProcessWithdrawalRequests(t)
+	failed := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failed, 1)
+	assert.Equal(t, messaging.FailureReasonInsufficientFunds, failed[0].Reason)
+
+	testenv.AssertErrorCode(t, postTransfer(router, accountID, otherID, 2500, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+	testenv.AssertErrorCode(t, postHoldRequest(router, accountID, 2500), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, accountID), "A hold must not debit the account")
+
+	require.Equal(t, http.StatusOK, postTransfer(router, accountID, otherID, 2000, "").Code)
+	assert.Equal(t, 3000, testenv.GetBalance(t, router, accountID))
+
+	resp := postJSON(router, "/holds/"+strconv.Itoa(holdID)+"/capture", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var hold map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &hold))
+	assert.Equal(t, "captured", hold["status"])
+	assert.Equal(t, float64(3000), hold["amount"])
+	assert.Equal(t, 0, testenv.GetBalance(t, router, accountID), "Capturing debits the reserved funds")
+
+	history := testenv.GetTransactionHistory(t, router, accountID, 1)
+	require.Len(t, history, 1)
+	assert.Equal(t, "withdraw", history[0]["type"])
+
+	// A resolved hold can't be captured or released again
+	testenv.AssertErrorCode(t, postJSON(router, "/holds/"+strconv.Itoa(holdID)+"/capture", nil), http.StatusConflict, "HOLD_NOT_ACTIVE")
+	testenv.AssertErrorCode(t, postJSON(router, "/holds/"+strconv.Itoa(holdID)+"/release", nil), http.StatusConflict, "HOLD_NOT_ACTIVE")
+}
+
+func TestReleasedHoldFreesFunds(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	accountID := testenv.CreateAccount(t, router, "Carla")
+	otherID := testenv.CreateAccount(t, router, "Diego")
+	testenv.SetBalance(t, accountID, 5000)
+
+	holdID := placeHold(t, router, accountID, 4000)
+	testenv.AssertErrorCode(t, postTransfer(router, accountID, otherID, 2000, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+
+	resp := postJSON(router, "/holds/"+strconv.Itoa(holdID)+"/release", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, accountID), "Releasing must not debit the account")
+
+	require.Equal(t, http.StatusOK, postTransfer(router, accountID, otherID, 2000, "").Code)
+	assert.Equal(t, 3000, testenv.GetBalance(t, router, accountID))
+}
+
+func TestHoldErrors(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	accountID := testenv.CreateAccount(t, router, "Eva")
+
+	testenv.AssertErrorCode(t, postHoldRequest(router, 999999, 100), http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+	testenv.AssertErrorCode(t, postHoldRequest(router, accountID, 0), http.StatusBadRequest, "INVALID_AMOUNT")
+	testenv.AssertErrorCode(t, postHoldRequest(router, accountID, 100), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+	testenv.AssertErrorCode(t, postJSON(router, "/holds/999999/capture", nil), http.StatusNotFound, "HOLD_NOT_FOUND")
+	testenv.AssertErrorCode(t, postJSON(router, "/holds/abc/release", nil), http.StatusBadRequest, "VALIDATION_ERROR")
+}
+
+func postHoldRequest(router *gin.Engine, accountID int, amount int) *httptest.ResponseRecorder {
+	return postJSON(router, "/accounts/"+strconv.Itoa(accountID)+"/holds", map[string]int{"amount": amount})
+}
+
+func placeHold(t *testing.T, router *gin.Engine, accountID int, amount int) int {
+	resp := postHoldRequest(router, accountID, amount)
+	require.Equal(t, http.StatusCreated, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	return int(result["hold_id"].(float64))
+}
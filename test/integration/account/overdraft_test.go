@@ -0,0 +1,109 @@
+package account
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setOverdraft(router *gin.Engine, accountID int, limit int) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(map[string]int{"limit": limit})
+	req := httptest.NewRequest("PUT", "/accounts/"+strconv.Itoa(accountID)+"/overdraft", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestWithdrawIntoOverdraft(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	testenv.SetBalance(t, accountID, 1000)
+
+	resp := setOverdraft(router, accountID, 500)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	acc, err := db.AtomicWithdraw(context.Background(), accountID, 1400)
+	require.NoError(t, err)
+	assert.Equal(t, -400, acc.Balance)
+	assert.Equal(t, -400, testenv.GetBalance(t, router, accountID))
+
+	// Exactly at the floor is allowed
+	acc, err = db.AtomicWithdraw(context.Background(), accountID, 100)
+	require.NoError(t, err)
+	assert.Equal(t, -500, acc.Balance)
+}
+
+func TestWithdrawBeyondOverdraftRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+	other := testenv.CreateAccount(t, router, "Carol")
+	testenv.SetBalance(t, accountID, 1000)
+	require.Equal(t, http.StatusOK, setOverdraft(router, accountID, 500).Code)
+
+	_, err := db.AtomicWithdraw(context.Background(), accountID, 1501)
+	assert.ErrorIs(t, err, postgres.ErrInsufficientFunds)
+
+	_, _, err = db.AtomicTransfer(context.Background(), accountID, other, 1501)
+	assert.ErrorIs(t, err, postgres.ErrInsufficientFunds)
+
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, accountID))
+	assert.Equal(t, 0, testenv.GetBalance(t, router, other))
+
+	// Transfer within the overdraft succeeds
+	from, to, err := db.AtomicTransfer(context.Background(), accountID, other, 1500)
+	require.NoError(t, err)
+	assert.Equal(t, -500, from.Balance)
+	assert.Equal(t, 1500, to.Balance)
+}
+
+func TestSetOverdraftBelowCurrentDebtRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Dave")
+	require.Equal(t, http.StatusOK, setOverdraft(router, accountID, 500).Code)
+
+	_, err := db.AtomicWithdraw(context.Background(), accountID, 300)
+	require.NoError(t, err)
+
+	resp := setOverdraft(router, accountID, 200)
+	require.Equal(t, http.StatusConflict, resp.Code)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, "OVERDRAFT_IN_USE", result["code"])
+
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, 500, acc.OverdraftLimit)
+
+	assert.Equal(t, http.StatusBadRequest, setOverdraft(router, accountID, -1).Code)
+	assert.Equal(t, http.StatusNotFound, setOverdraft(router, 999999, 100).Code)
+}
@@ -0,0 +1,242 @@
+package account
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionHistory(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Nícolas")
+
+	// Deposit 5000, withdraw 2000, deposit 1000 - each writes a transaction log row
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, 5000, uuid.New().String())
+	require.NoError(t, err)
+
+	_, err = db.AtomicWithdrawWithIdempotency(context.Background(), accountID, 2000, uuid.New().String())
+	require.NoError(t, err)
+
+	_, err = db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, uuid.New().String())
+	require.NoError(t, err)
+
+	history := testenv.GetTransactionHistory(t, router, accountID, 0)
+	require.Len(t, history, 3)
+
+	// Most recent first
+	expected := []struct {
+		txType       string
+		amount       int
+		balanceAfter int
+	}{
+		{"deposit", 1000, 4000},
+		{"withdraw", -2000, 3000},
+		{"deposit", 5000, 5000},
+	}
+	for i, exp := range expected {
+		assert.Equal(t, exp.txType, history[i]["type"], "transaction %d type", i)
+		assert.Equal(t, float64(exp.amount), history[i]["amount"], "transaction %d amount", i)
+		assert.Equal(t, float64(exp.balanceAfter), history[i]["balance_after"], "transaction %d balance_after", i)
+	}
+
+	// Limit restricts to the most recent entries
+	limited := testenv.GetTransactionHistory(t, router, accountID, 2)
+	require.Len(t, limited, 2)
+	assert.Equal(t, float64(4000), limited[0]["balance_after"])
+	assert.Equal(t, float64(3000), limited[1]["balance_after"])
+}
+
+func TestTransactionHistoryEmpty(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	accountID := testenv.CreateAccount(t, router, "Maria")
+
+	history := testenv.GetTransactionHistory(t, router, accountID, 0)
+	assert.NotNil(t, history, "Empty history should be an empty list, not null")
+	assert.Empty(t, history)
+}
+
+func TestTransactionHistoryInvalidLimit(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	accountID := testenv.CreateAccount(t, router, "Carlos")
+
+	for _, limit := range []string{"abc", "0", "-1", "501"} {
+		req := httptest.NewRequest("GET", "/accounts/"+strconv.Itoa(accountID)+"/transactions?limit="+limit, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, "limit=%s should be rejected", limit)
+	}
+}
+
+func TestTransactionHistoryNonexistentAccount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	req := httptest.NewRequest("GET", "/accounts/999/transactions", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusNotFound, resp.Code)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	testenv.AssertHasError(t, result)
+}
+
+func TestTransactionHistoryFilterByType(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Helena")
+	otherID := testenv.CreateAccount(t, router, "Igor")
+
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, 5000, uuid.New().String())
+	require.NoError(t, err)
+	_, err = db.AtomicWithdrawWithIdempotency(context.Background(), accountID, 1000, uuid.New().String())
+	require.NoError(t, err)
+	_, _, err = db.AtomicTransfer(context.Background(), accountID, otherID, 500)
+	require.NoError(t, err)
+	_, err = db.AtomicDepositWithIdempotency(context.Background(), accountID, 200, uuid.New().String())
+	require.NoError(t, err)
+
+	deposits, next := testenv.GetTransactionHistoryPage(t, router, accountID, url.Values{"type": {"deposit"}})
+	require.Len(t, deposits, 2)
+	assert.Nil(t, next)
+	assert.Equal(t, float64(200), deposits[0]["amount"])
+	assert.Equal(t, float64(5000), deposits[1]["amount"])
+
+	transfers, _ := testenv.GetTransactionHistoryPage(t, router, accountID, url.Values{"type": {"transfer_out"}})
+	require.Len(t, transfers, 1)
+	assert.Equal(t, float64(-500), transfers[0]["amount"])
+
+	interest, _ := testenv.GetTransactionHistoryPage(t, router, accountID, url.Values{"type": {"interest"}})
+	assert.NotNil(t, interest)
+	assert.Empty(t, interest)
+}
+
+func TestTransactionHistoryPagination(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Joana")
+	targets := []int{
+		testenv.CreateAccount(t, router, "Kátia"),
+		testenv.CreateAccount(t, router, "Lucas"),
+	}
+
+	// Deposits of 100..400, then a batch transfer whose two debit rows share a created_at
+	for amount := 100; amount <= 400; amount += 100 {
+		_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, amount, uuid.New().String())
+		require.NoError(t, err)
+	}
+	_, err := db.AtomicBatchTransfer(context.Background(), accountID, []postgres.Transfer{
+		{ToID: targets[0], Amount: 50},
+		{ToID: targets[1], Amount: 60},
+	})
+	require.NoError(t, err)
+
+	all := testenv.GetTransactionHistory(t, router, accountID, 0)
+	require.Len(t, all, 6)
+
+	// Walk the history two rows at a time and check it matches the unpaged listing
+	var paged []map[string]interface{}
+	query := url.Values{"limit": {"2"}}
+	pages := 0
+	for {
+		page, next := testenv.GetTransactionHistoryPage(t, router, accountID, query)
+		paged = append(paged, page...)
+		pages++
+		if next == nil {
+			break
+		}
+		require.Len(t, page, 2)
+		query.Set("before", next.Before.Format(time.RFC3339Nano))
+		query.Set("before_id", strconv.Itoa(next.BeforeID))
+	}
+
+	assert.Equal(t, 3, pages)
+	require.Len(t, paged, len(all))
+	for i := range all {
+		assert.Equal(t, all[i]["id"], paged[i]["id"], "transaction %d", i)
+	}
+}
+
+func TestTransactionHistoryEmptyFinalPage(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Mário")
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, uuid.New().String())
+	require.NoError(t, err)
+
+	first, next := testenv.GetTransactionHistoryPage(t, router, accountID, url.Values{"limit": {"1"}})
+	require.Len(t, first, 1)
+	assert.Nil(t, next, "A page holding the last transaction should have no next cursor")
+
+	// A cursor at the oldest transaction yields an empty page
+	createdAt, err := time.Parse(time.RFC3339Nano, first[0]["created_at"].(string))
+	require.NoError(t, err)
+	empty, next := testenv.GetTransactionHistoryPage(t, router, accountID, url.Values{
+		"before":    {createdAt.Format(time.RFC3339Nano)},
+		"before_id": {strconv.Itoa(int(first[0]["id"].(float64)))},
+	})
+	assert.NotNil(t, empty)
+	assert.Empty(t, empty)
+	assert.Nil(t, next)
+}
+
+func TestTransactionHistoryInvalidFilters(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	accountID := testenv.CreateAccount(t, router, "Nina")
+
+	for _, query := range []string{"type=refund", "before=yesterday", "before_id=3", "before=2024-01-01T00:00:00Z&before_id=x"} {
+		req := httptest.NewRequest("GET", "/accounts/"+strconv.Itoa(accountID)+"/transactions?"+query, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, "%s should be rejected", query)
+	}
+}
@@ -0,0 +1,118 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postTransfer(router *gin.Engine, fromID, toID, amount int, idempotencyKey string) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(map[string]int{"from": fromID, "to": toID, "amount": amount})
+
+	req := httptest.NewRequest("POST", "/accounts/transfer", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestTransferDoubleSubmitAppliedOnce(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	fromID := testenv.CreateAccount(t, router, "Otávio")
+	toID := testenv.CreateAccount(t, router, "Paula")
+	testenv.SetBalance(t, fromID, 5000)
+	eventPublisher.Reset()
+
+	first := postTransfer(router, fromID, toID, 1500, "invoice-7")
+	require.Equal(t, http.StatusOK, first.Code)
+
+	retry := postTransfer(router, fromID, toID, 1500, "invoice-7")
+	require.Equal(t, http.StatusOK, retry.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(retry.Body.Bytes(), &body))
+	assert.Equal(t, true, body["duplicate"])
+	assert.Equal(t, float64(3500), body["from_balance"], "Retry should report the original resulting balance")
+
+	assert.Equal(t, 3500, testenv.GetBalance(t, router, fromID))
+	assert.Equal(t, 1500, testenv.GetBalance(t, router, toID))
+	assert.Len(t, eventPublisher.GetTransferCompletedEvents(), 1, "Retry must not publish a second event")
+}
+
+func TestTransferConcurrentDoubleSubmitAppliedOnce(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	fromID := testenv.CreateAccount(t, router, "Quitéria")
+	toID := testenv.CreateAccount(t, router, "Rafael")
+	testenv.SetBalance(t, fromID, 10000)
+
+	var wg sync.WaitGroup
+	n := 20
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			resp := postTransfer(router, fromID, toID, 2500, "double-click")
+			if resp.Code != http.StatusOK {
+				t.Errorf("Unexpected status: %d", resp.Code)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 7500, testenv.GetBalance(t, router, fromID))
+	assert.Equal(t, 2500, testenv.GetBalance(t, router, toID))
+}
+
+func TestTransferDistinctIdempotencyKeysBothApplied(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	fromID := testenv.CreateAccount(t, router, "Sérgio")
+	toID := testenv.CreateAccount(t, router, "Tânia")
+	testenv.SetBalance(t, fromID, 5000)
+
+	require.Equal(t, http.StatusOK, postTransfer(router, fromID, toID, 1000, "rent-march").Code)
+	require.Equal(t, http.StatusOK, postTransfer(router, fromID, toID, 1000, "rent-april").Code)
+
+	// Without a header every request is a separate transfer
+	require.Equal(t, http.StatusOK, postTransfer(router, fromID, toID, 1000, "").Code)
+
+	assert.Equal(t, 2000, testenv.GetBalance(t, router, fromID))
+	assert.Equal(t, 3000, testenv.GetBalance(t, router, toID))
+}
+
+func TestTransferInvalidIdempotencyKeyRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	fromID := testenv.CreateAccount(t, router, "Ubirajara")
+	toID := testenv.CreateAccount(t, router, "Vera")
+	testenv.SetBalance(t, fromID, 5000)
+
+	resp := postTransfer(router, fromID, toID, 1000, "   ")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, 5000, testenv.GetBalance(t, router, fromID))
+}
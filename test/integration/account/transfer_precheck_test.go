@@ -0,0 +1,67 @@
+package account
+
+import (
+	"bank-api/test/integration/testenv"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransferPrecheckRejectsEarly checks a source that clearly lacks funds is rejected by the
+// read-only check, before the locked transfer would have looked up the destination
+func TestTransferPrecheckRejectsEarly(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	from := testenv.CreateAccount(t, router, "From")
+	testenv.SetBalance(t, from, 1000)
+
+	// The locked path answers 404 for a missing destination; 400 means it was never reached
+	testenv.AssertErrorCode(t, postTransfer(router, from, 999999, 5000, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, from))
+}
+
+// TestTransferPrecheckBoundary checks the early check never rejects a transfer the locked
+// check would allow
+func TestTransferPrecheckBoundary(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	from := testenv.CreateAccount(t, router, "From")
+	to := testenv.CreateAccount(t, router, "To")
+	testenv.SetBalance(t, from, 1000)
+
+	testenv.AssertErrorCode(t, postTransfer(router, from, to, 1001, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+
+	// Exactly the balance goes through
+	require.Equal(t, http.StatusOK, postTransfer(router, from, to, 1000, "").Code)
+	assert.Equal(t, 0, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, to))
+
+	// The overdraft counts towards what the source can cover
+	require.Equal(t, http.StatusOK, setOverdraft(router, to, 500).Code)
+	require.Equal(t, http.StatusOK, postTransfer(router, to, from, 1500, "").Code)
+	assert.Equal(t, -500, testenv.GetBalance(t, router, to))
+	testenv.AssertErrorCode(t, postTransfer(router, to, from, 1, ""), http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+}
+
+// TestTransferPrecheckSkipsReplays checks a retried idempotent transfer is still answered once
+// the balance no longer covers the amount
+func TestTransferPrecheckSkipsReplays(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	from := testenv.CreateAccount(t, router, "From")
+	to := testenv.CreateAccount(t, router, "To")
+	testenv.SetBalance(t, from, 1000)
+
+	require.Equal(t, http.StatusOK, postTransfer(router, from, to, 1000, "transfer-1").Code)
+
+	resp := postTransfer(router, from, to, 1000, "transfer-1")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"duplicate":true`)
+	assert.Equal(t, 0, testenv.GetBalance(t, router, from))
+}
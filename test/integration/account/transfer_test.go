@@ -66,3 +66,23 @@ func TestTransferNonexistentAccount(t *testing.T) {
 	balance := testenv.GetBalance(t, router, from)
 	assert.Equal(t, 100, balance, "Source account balance should remain unchanged after failed transfer")
 }
+
+func TestTransferDecimalStringAmount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	router := testenv.SetupRouter()
+
+	from := testenv.CreateAccount(t, router, "From")
+	to := testenv.CreateAccount(t, router, "To")
+	testenv.SetBalance(t, from, 1000)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"from": from, "to": to, "amount": "2.75"})
+	req := httptest.NewRequest("POST", "/accounts/transfer", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 725, testenv.GetBalance(t, router, from))
+	assert.Equal(t, 275, testenv.GetBalance(t, router, to))
+}
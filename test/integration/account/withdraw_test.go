@@ -3,6 +3,7 @@ package account
 import (
 	"bank-api/test/integration/testenv"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,13 +11,17 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestWithdraw(t *testing.T) {
 	testenv.SetupIntegrationTest(t)
-	router := testenv.SetupRouter()
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
 
 	accountID := testenv.CreateAccount(t, router, "Nícolas")
 	testenv.SetBalance(t, accountID, 5000)
@@ -30,11 +35,15 @@ func TestWithdraw(t *testing.T) {
 
 	router.ServeHTTP(resp, req)
 
-	require.Equal(t, http.StatusOK, resp.Code)
+	// Expects 202 Accepted for async processing
+	require.Equal(t, http.StatusAccepted, resp.Code)
 	var result map[string]interface{}
 	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
-	assert.Equal(t, float64(accountID), result["id"])
-	assert.Equal(t, float64(2000), result["balance"])
+	assert.Equal(t, "accepted", result["status"])
+	assert.NotEmpty(t, result["operation_id"])
+
+	// Simulate the consumer processing the request
+	container.ProcessWithdrawalRequests(t)
 
 	balance := testenv.GetBalance(t, router, accountID)
 	assert.Equal(t, 2000, balance)
@@ -64,24 +73,23 @@ func TestWithdrawInvalidAmount(t *testing.T) {
 
 func TestWithdrawInsufficientBalance(t *testing.T) {
 	testenv.SetupIntegrationTest(t)
-	router := testenv.SetupRouter()
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
 
 	accountID := testenv.CreateAccount(t, router, "Nícolas")
 	testenv.SetBalance(t, accountID, 100)
 
-	body := map[string]int{"amount": 500}
-	jsonBody, _ := json.Marshal(body)
+	// Request is accepted; funds are checked by the consumer
+	testenv.Withdraw(t, router, accountID, 500)
+	container.ProcessWithdrawalRequests(t)
 
-	req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/withdraw", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	resp := httptest.NewRecorder()
-
-	router.ServeHTTP(resp, req)
-
-	require.Equal(t, http.StatusBadRequest, resp.Code)
-	var result map[string]interface{}
-	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
-	testenv.AssertHasError(t, result)
+	failedEvents := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failedEvents, 1)
+	assert.Equal(t, accountID, failedEvents[0].AccountID)
+	assert.Equal(t, 500, failedEvents[0].Amount)
 
 	// Verify balance unchanged in database after failed withdrawal
 	balance := testenv.GetBalance(t, router, accountID)
@@ -109,7 +117,11 @@ func TestWithdrawNonexistentAccount(t *testing.T) {
 
 func TestConcurrentWithdraw(t *testing.T) {
 	testenv.SetupIntegrationTest(t)
-	router := testenv.SetupRouter()
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
 
 	accountID := testenv.CreateAccount(t, router, "ConcurrentWithdraw")
 	testenv.SetBalance(t, accountID, 10000) // R$ 100,00
@@ -119,21 +131,13 @@ func TestConcurrentWithdraw(t *testing.T) {
 	amount := 100 // R$ 1,00 por saque
 	wg.Add(n)
 
+	// Simulate many consumer instances processing distinct withdrawal requests
 	for i := 0; i < n; i++ {
 		go func() {
 			defer wg.Done()
 
-			body := map[string]int{"amount": amount}
-			jsonBody, _ := json.Marshal(body)
-
-			req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/withdraw", bytes.NewBuffer(jsonBody))
-			req.Header.Set("Content-Type", "application/json")
-			resp := httptest.NewRecorder()
-
-			router.ServeHTTP(resp, req)
-
-			if resp.Code != http.StatusOK {
-				t.Errorf("Erro no saque: %d", resp.Code)
+			if _, err := db.AtomicWithdrawWithIdempotency(context.Background(), accountID, amount, uuid.New().String()); err != nil {
+				t.Errorf("Erro no saque: %v", err)
 			}
 		}()
 	}
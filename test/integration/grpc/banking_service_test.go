@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"bank-api/internal/api/grpcapi/bankingpb"
+	"bank-api/internal/domain/models"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCCreateAccountAndGetBalance(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	client := container.NewGRPCClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateAccount(ctx, &bankingpb.CreateAccountRequest{Owner: "Alice"})
+	require.NoError(t, err)
+	assert.Positive(t, created.GetId())
+	assert.Equal(t, "Alice", created.GetOwner())
+	require.Len(t, container.GetEventPublisher().GetAccountCreatedEvents(), 1)
+
+	testenv.SetBalance(t, int(created.GetId()), 2500)
+
+	balance, err := client.GetBalance(ctx, &bankingpb.GetBalanceRequest{AccountId: created.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), balance.GetBalance())
+
+	_, err = client.GetBalance(ctx, &bankingpb.GetBalanceRequest{AccountId: 999999})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCDepositPublishesRequest(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	client := container.NewGRPCClient(t)
+	ctx := context.Background()
+
+	accountID := testenv.CreateAccount(t, container.GetRouter(), "Bob")
+
+	accepted, err := client.Deposit(ctx, &bankingpb.DepositRequest{AccountId: int64(accountID), Amount: 1000})
+	require.NoError(t, err)
+	assert.NotEmpty(t, accepted.GetOperationId())
+	assert.Equal(t, "accepted", accepted.GetStatus())
+
+	events := container.GetEventPublisher().GetDepositRequestedEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, accepted.GetOperationId(), events[0].OperationID)
+	assert.Equal(t, accountID, events[0].AccountID)
+	assert.Equal(t, 1000, events[0].Amount)
+
+	_, err = client.Deposit(ctx, &bankingpb.DepositRequest{AccountId: int64(accountID), Amount: 0})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCTransfer(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	client := container.NewGRPCClient(t)
+	ctx := context.Background()
+
+	router := container.GetRouter()
+	fromID := testenv.CreateAccount(t, router, "Carol")
+	toID := testenv.CreateAccount(t, router, "Dave")
+	testenv.SetBalance(t, fromID, 1000)
+
+	resp, err := client.Transfer(ctx, &bankingpb.TransferRequest{FromId: int64(fromID), ToId: int64(toID), Amount: 400})
+	require.NoError(t, err)
+	assert.Equal(t, int64(600), resp.GetFromBalance())
+	assert.Equal(t, int64(400), resp.GetToBalance())
+	require.Len(t, container.GetEventPublisher().GetTransferCompletedEvents(), 1)
+
+	_, err = client.Transfer(ctx, &bankingpb.TransferRequest{FromId: int64(fromID), ToId: int64(toID), Amount: 5000})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGRPCWithdrawRecordsOperation(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	client := container.NewGRPCClient(t)
+	ctx := context.Background()
+
+	accountID := testenv.CreateAccount(t, container.GetRouter(), "Erin")
+	testenv.SetBalance(t, accountID, 1000)
+
+	accepted, err := client.Withdraw(ctx, &bankingpb.WithdrawRequest{AccountId: int64(accountID), Amount: 400})
+	require.NoError(t, err)
+
+	operation, err := container.GetDatabase().GetOperation(ctx, accepted.GetOperationId())
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusPending, operation.Status)
+	assert.Equal(t, "withdraw", operation.Type)
+
+	require.NoError(t, container.GetDatabase().SetFrozen(ctx, accountID, true))
+	_, err = client.Withdraw(ctx, &bankingpb.WithdrawRequest{AccountId: int64(accountID), Amount: 400})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.Deposit(ctx, &bankingpb.DepositRequest{AccountId: int64(accountID), Amount: 400})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Len(t, container.GetEventPublisher().GetWithdrawalRequestedEvents(), 1)
+}
@@ -0,0 +1,74 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsumers_FrozenAccount verifies that deposits and withdrawals already queued when an
+// account is frozen fail with an account_frozen reason, and go through once it is unfrozen
+func TestConsumers_FrozenAccount(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	testenv.SetBalance(t, accountID, 5000)
+	require.NoError(t, db.SetFrozen(context.Background(), accountID, true))
+	eventPublisher.Reset()
+
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, db)
+	withdrawalHandler := messaging.NewWithdrawalConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, db)
+
+	session := testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, depositRequest(accountID, 1000))
+	assert.Len(t, session.MarkedMessages(), 1, "Frozen account is a final outcome, not a retry")
+	session = testenv.ConsumeEvents(t, withdrawalHandler, kafka.TopicWithdrawalRequests, withdrawalRequest(accountID, 1000))
+	assert.Len(t, session.MarkedMessages(), 1)
+
+	failed := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failed, 2)
+	assert.Equal(t, "deposit", failed[0].TransactionType)
+	assert.Equal(t, messaging.FailureReasonAccountFrozen, failed[0].Reason)
+	assert.Equal(t, "withdrawal", failed[1].TransactionType)
+	assert.Equal(t, messaging.FailureReasonAccountFrozen, failed[1].Reason)
+
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.True(t, acc.Frozen)
+	assert.Equal(t, 5000, acc.Balance)
+
+	// Lifting the hold lets the same kinds of operation through
+	require.NoError(t, db.SetFrozen(context.Background(), accountID, false))
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, depositRequest(accountID, 1000))
+	testenv.ConsumeEvents(t, withdrawalHandler, kafka.TopicWithdrawalRequests, withdrawalRequest(accountID, 500))
+
+	assert.Len(t, eventPublisher.GetDepositCompletedEvents(), 1)
+	assert.Len(t, eventPublisher.GetWithdrawalCompletedEvents(), 1)
+
+	acc, ok = db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.False(t, acc.Frozen)
+	assert.Equal(t, 5500, acc.Balance)
+}
+
+func withdrawalRequest(accountID, amount int) messaging.WithdrawalRequestedEvent {
+	return messaging.WithdrawalRequestedEvent{
+		OperationID:    uuid.New().String(),
+		IdempotencyKey: uuid.New().String(),
+		AccountID:      accountID,
+		Amount:         amount,
+		Timestamp:      time.Now(),
+	}
+}
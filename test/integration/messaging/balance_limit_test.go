@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func depositRequest(accountID, amount int) messaging.DepositRequestedEvent {
+	return messaging.DepositRequestedEvent{
+		OperationID:    uuid.New().String(),
+		IdempotencyKey: uuid.New().String(),
+		AccountID:      accountID,
+		Amount:         amount,
+		Timestamp:      time.Now(),
+	}
+}
+
+// TestDepositConsumer_BalanceLimit verifies that a deposit taking the account above
+// MAX_ACCOUNT_BALANCE is rejected with a balance_limit_exceeded failure, while deposits
+// up to the cap go through
+func TestDepositConsumer_BalanceLimit(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+	cfg.MaxAccountBalance = 5000
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	eventPublisher := messaging.NewEventCapture()
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+
+	handler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, repo)
+	session := testenv.ConsumeEvents(t, handler, kafka.TopicDepositRequests,
+		depositRequest(accountID, 3000), // normal path
+		depositRequest(accountID, 2500), // would reach 5500
+		depositRequest(accountID, 2000), // exactly at the cap
+	)
+
+	// All three are final outcomes, so every offset is acknowledged
+	assert.Len(t, session.MarkedMessages(), 3)
+
+	account, ok := repo.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, 5000, account.Balance)
+
+	completed := eventPublisher.GetDepositCompletedEvents()
+	require.Len(t, completed, 2)
+	assert.Equal(t, 3000, completed[0].BalanceAfter)
+	assert.Equal(t, 5000, completed[1].BalanceAfter)
+
+	failed := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "deposit", failed[0].TransactionType)
+	assert.Equal(t, accountID, failed[0].AccountID)
+	assert.Equal(t, 2500, failed[0].Amount)
+	assert.Equal(t, messaging.FailureReasonBalanceLimitExceeded, failed[0].Reason)
+}
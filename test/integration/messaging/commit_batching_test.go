@@ -0,0 +1,130 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// succeedingDepositRepository accepts every deposit
+type succeedingDepositRepository struct {
+	database.Repository
+	calls atomic.Int32
+}
+
+func (r *succeedingDepositRepository) AtomicDepositWithIdempotency(ctx context.Context, accountID, amount int, idempotencyKey string) (*models.Account, error) {
+	r.calls.Add(1)
+	return &models.Account{Id: accountID, Balance: amount}, nil
+}
+
+func (r *succeedingDepositRepository) SetOperationStatus(ctx context.Context, operationID, status, reason string) error {
+	return nil
+}
+
+func commitBatchingTestConfig(batchSize int, interval time.Duration) *kafka.Config {
+	config := kafka.NewConfigFromEnv()
+	config.ConsumerCommitBatchSize = batchSize
+	config.ConsumerCommitInterval = interval
+	return config
+}
+
+func depositPayloads(t *testing.T, n int) [][]byte {
+	payloads := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		payload, err := json.Marshal(messaging.DepositRequestedEvent{
+			OperationID:    fmt.Sprintf("op-%d", i),
+			IdempotencyKey: fmt.Sprintf("key-%d", i),
+			AccountID:      1,
+			Amount:         100,
+			Timestamp:      time.Now(),
+		})
+		require.NoError(t, err)
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+// TestDepositConsumer_CommitsInBatches verifies offsets are committed every N messages,
+// with the remainder committed when the claim ends
+func TestDepositConsumer_CommitsInBatches(t *testing.T) {
+	repo := &succeedingDepositRepository{}
+	handler := messaging.NewDepositConsumerHandler(commitBatchingTestConfig(3, time.Hour), messaging.NewEventCapture(), repo)
+
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicDepositRequests, depositPayloads(t, 7)...)
+
+	assert.Equal(t, int32(7), repo.calls.Load())
+	assert.Len(t, session.MarkedMessages(), 7)
+	assert.Equal(t, []int{3, 6, 7}, session.CommitPoints())
+}
+
+// TestDepositConsumer_CommitsOnFlushInterval verifies a partial batch is committed once the
+// flush interval elapses, without waiting for more messages
+func TestDepositConsumer_CommitsOnFlushInterval(t *testing.T) {
+	repo := &succeedingDepositRepository{}
+	handler := messaging.NewDepositConsumerHandler(commitBatchingTestConfig(100, 20*time.Millisecond), messaging.NewEventCapture(), repo)
+
+	claim := testenv.NewFakeConsumerGroupClaim(kafka.TopicDepositRequests, 2)
+	session := testenv.NewFakeConsumerGroupSession(context.Background())
+
+	done := make(chan error, 1)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	for _, payload := range depositPayloads(t, 2) {
+		claim.Send(payload)
+	}
+
+	require.Eventually(t, func() bool {
+		return session.CommittedMessages() == 2
+	}, time.Second, 5*time.Millisecond)
+
+	claim.Close()
+	require.NoError(t, <-done)
+}
+
+// TestDepositConsumer_CrashBeforeCommitReprocesses verifies that messages processed but not yet
+// committed when the consumer dies are delivered again to the next session
+func TestDepositConsumer_CrashBeforeCommitReprocesses(t *testing.T) {
+	repo := &succeedingDepositRepository{}
+	config := commitBatchingTestConfig(3, time.Hour)
+	payloads := depositPayloads(t, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claim := testenv.NewFakeConsumerGroupClaim(kafka.TopicDepositRequests, len(payloads))
+	session := testenv.NewFakeConsumerGroupSession(ctx)
+
+	done := make(chan error, 1)
+	handler := messaging.NewDepositConsumerHandler(config, messaging.NewEventCapture(), repo)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	for _, payload := range payloads {
+		claim.Send(payload)
+	}
+	require.Eventually(t, func() bool {
+		return repo.calls.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// Both messages were processed but the batch isn't full - nothing is committed yet,
+	// so a crash at this point leaves the committed offset before them
+	committed := session.CommittedMessages()
+	assert.Zero(t, committed)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	// The next session resumes from the committed offset and sees the messages again
+	restarted := messaging.NewDepositConsumerHandler(config, messaging.NewEventCapture(), repo)
+	testenv.ConsumeMessages(t, restarted, kafka.TopicDepositRequests, payloads[committed:]...)
+
+	assert.Equal(t, int32(4), repo.calls.Load(), "Uncommitted messages should be processed again")
+}
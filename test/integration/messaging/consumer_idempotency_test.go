@@ -5,6 +5,7 @@ import (
 	"bank-api/internal/pkg/idempotency"
 	"bank-api/test/integration/testenv"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -27,7 +28,7 @@ func TestConsumerIdempotency_SameKeyTwice(t *testing.T) {
 	accountID := testenv.CreateAccount(t, router, "Alice")
 
 	// Get initial balance
-	initialAcc, ok := db.GetAccount(accountID)
+	initialAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok, "Account should exist")
 	initialBalance := initialAcc.Balance
 
@@ -35,19 +36,19 @@ func TestConsumerIdempotency_SameKeyTwice(t *testing.T) {
 	idempotencyKey := idempotency.GenerateKey("deposit", accountID, 1000)
 
 	// First deposit with idempotency key
-	acc1, err1 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	acc1, err1 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.NoError(t, err1, "First deposit should succeed")
 	require.NotNil(t, acc1)
 	assert.Equal(t, initialBalance+1000, acc1.Balance, "Balance should increase by 1000")
 
 	// Second deposit with SAME idempotency key (simulating duplicate message)
-	acc2, err2 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	acc2, err2 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.Error(t, err2, "Second deposit should return error")
 	require.ErrorIs(t, err2, postgres.ErrDuplicateOperation, "Error should be ErrDuplicateOperation")
 	require.NotNil(t, acc2, "Account should still be returned")
 
 	// Verify balance only increased ONCE
-	finalAcc, ok := db.GetAccount(accountID)
+	finalAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	assert.Equal(t, initialBalance+1000, finalAcc.Balance, "Balance should only increase once")
 }
@@ -65,24 +66,24 @@ func TestConsumerIdempotency_DifferentKeysTwice(t *testing.T) {
 	accountID := testenv.CreateAccount(t, router, "Bob")
 
 	// Get initial balance
-	initialAcc, ok := db.GetAccount(accountID)
+	initialAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	initialBalance := initialAcc.Balance
 
 	// First deposit with key1 (amount: 1000)
 	key1 := idempotency.GenerateKey("deposit", accountID, 1000)
-	acc1, err1 := db.AtomicDepositWithIdempotency(accountID, 1000, key1)
+	acc1, err1 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, key1)
 	require.NoError(t, err1)
 	assert.Equal(t, initialBalance+1000, acc1.Balance)
 
 	// Second deposit with key2 (amount: 2000) - different amount = different key
 	key2 := idempotency.GenerateKey("deposit", accountID, 2000)
-	acc2, err2 := db.AtomicDepositWithIdempotency(accountID, 2000, key2)
+	acc2, err2 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 2000, key2)
 	require.NoError(t, err2)
 	assert.Equal(t, initialBalance+1000+2000, acc2.Balance)
 
 	// Verify both deposits processed
-	finalAcc, ok := db.GetAccount(accountID)
+	finalAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	assert.Equal(t, initialBalance+3000, finalAcc.Balance, "Both deposits should process")
 }
@@ -238,7 +239,7 @@ func TestEndToEnd_IdempotentDeposit(t *testing.T) {
 	accountID := testenv.CreateAccount(t, router, "Frank")
 
 	// Get initial balance
-	initialAcc, ok := db.GetAccount(accountID)
+	initialAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	initialBalance := initialAcc.Balance
 
@@ -259,18 +260,18 @@ func TestEndToEnd_IdempotentDeposit(t *testing.T) {
 	idempotencyKey := idempotency.GenerateKey("deposit", accountID, 1000)
 
 	// First processing
-	acc1, err1 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	acc1, err1 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.NoError(t, err1)
 	assert.Equal(t, initialBalance+1000, acc1.Balance)
 
 	// Simulate consumer crash and restart (message redelivered)
 	// Second processing with SAME idempotency key
-	_, err2 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	_, err2 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.Error(t, err2)
 	require.ErrorIs(t, err2, postgres.ErrDuplicateOperation)
 
 	// Final balance check
-	finalAcc, ok := db.GetAccount(accountID)
+	finalAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	assert.Equal(t, initialBalance+1000, finalAcc.Balance,
 		"Balance should only increase once despite redelivery")
@@ -293,7 +294,7 @@ func TestProcessedOperationsTable_Schema(t *testing.T) {
 
 	// Insert operation via AtomicDepositWithIdempotency
 	idempotencyKey := idempotency.GenerateKey("deposit", accountID, 500)
-	_, err := db.AtomicDepositWithIdempotency(accountID, 500, idempotencyKey)
+	_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, 500, idempotencyKey)
 	require.NoError(t, err)
 
 	// Verify the processed_operations table has the record
@@ -301,7 +302,7 @@ func TestProcessedOperationsTable_Schema(t *testing.T) {
 	// function will fail if the table doesn't exist)
 
 	// Try duplicate - should detect existing record
-	_, err2 := db.AtomicDepositWithIdempotency(accountID, 500, idempotencyKey)
+	_, err2 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 500, idempotencyKey)
 	require.Error(t, err2)
 	require.ErrorIs(t, err2, postgres.ErrDuplicateOperation)
 }
@@ -319,7 +320,7 @@ func TestRealWorldScenario_UserDoubleClick(t *testing.T) {
 	accountID := testenv.CreateAccount(t, router, "Henry")
 
 	// Get initial balance
-	initialAcc, ok := db.GetAccount(accountID)
+	initialAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	initialBalance := initialAcc.Balance
 
@@ -345,15 +346,15 @@ func TestRealWorldScenario_UserDoubleClick(t *testing.T) {
 	idempotencyKey := idempotency.GenerateKey("deposit", accountID, 1000)
 
 	// Process first message
-	_, err1 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	_, err1 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.NoError(t, err1)
 
 	// Process second message (duplicate!)
-	_, err2 := db.AtomicDepositWithIdempotency(accountID, 1000, idempotencyKey)
+	_, err2 := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, idempotencyKey)
 	require.ErrorIs(t, err2, postgres.ErrDuplicateOperation)
 
 	// Verify balance only increased ONCE
-	finalAcc, ok := db.GetAccount(accountID)
+	finalAcc, ok := db.GetAccount(context.Background(), accountID)
 	require.True(t, ok)
 	assert.Equal(t, initialBalance+1000, finalAcc.Balance,
 		"User's double-click should only result in one deposit")
@@ -379,14 +380,14 @@ func BenchmarkIdempotencyCheck(b *testing.B) {
 
 	// Warm-up: insert one processed operation
 	warmupKey := idempotency.GenerateKey("deposit", accountID, 1)
-	db.AtomicDepositWithIdempotency(accountID, 1, warmupKey)
+	db.AtomicDepositWithIdempotency(context.Background(), accountID, 1, warmupKey)
 
 	b.StartTimer()
 
 	// Benchmark: Check if operation already processed (cache hit scenario)
 	for i := 0; i < b.N; i++ {
 		key := idempotency.GenerateKey("deposit", accountID, 1)
-		_, err := db.AtomicDepositWithIdempotency(accountID, 1, key)
+		_, err := db.AtomicDepositWithIdempotency(context.Background(), accountID, 1, key)
 		if err != postgres.ErrDuplicateOperation {
 			b.Fatal("Expected duplicate operation")
 		}
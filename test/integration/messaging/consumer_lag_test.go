@@ -0,0 +1,45 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consumerLag(t *testing.T, topic string) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, metrics.KafkaConsumerLag.WithLabelValues(topic, "0").Write(m))
+	return m.GetGauge().GetValue()
+}
+
+// TestDepositConsumer_ReportsLag verifies kafka_consumer_lag tracks the distance between the
+// partition's high-water mark and the last message marked for commit
+func TestDepositConsumer_ReportsLag(t *testing.T) {
+	handler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), messaging.NewEventCapture(), &succeedingDepositRepository{})
+
+	// The fake claim's high-water mark is its capacity: 10 messages exist on the partition,
+	// but only the first 3 are delivered before the claim ends
+	claim := testenv.NewFakeConsumerGroupClaim(kafka.TopicDepositRequests, 10)
+	for _, payload := range depositPayloads(t, 3) {
+		claim.Send(payload)
+	}
+	claim.Close()
+
+	session := testenv.NewFakeConsumerGroupSession(context.Background())
+	require.NoError(t, handler.ConsumeClaim(session, claim))
+
+	assert.Len(t, session.MarkedMessages(), 3)
+	assert.Equal(t, 7.0, consumerLag(t, kafka.TopicDepositRequests), "7 messages should still be waiting")
+
+	// Catching up on the whole partition brings the lag to zero
+	handler = messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), messaging.NewEventCapture(), &succeedingDepositRepository{})
+	testenv.ConsumeMessages(t, handler, kafka.TopicDepositRequests, depositPayloads(t, 10)...)
+	assert.Equal(t, 0.0, consumerLag(t, kafka.TopicDepositRequests))
+}
@@ -0,0 +1,56 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/pkg/components"
+	"bank-api/test/integration/testenv"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsumerProcess_StartupAndShutdown runs the standalone consumer process (cmd/consumer)
+// against a PostgreSQL testcontainer and a fake broker. Both consumer groups must join, and
+// cancelling the context, as SIGTERM does, must stop everything cleanly.
+func TestConsumerProcess_StartupAndShutdown(t *testing.T) {
+	dbConfig := testenv.SetupMigratedPostgresContainer(t)
+	t.Setenv("DB_HOST", dbConfig.Host)
+	t.Setenv("DB_PORT", strconv.Itoa(dbConfig.Port))
+	t.Setenv("DB_NAME", dbConfig.Database)
+	t.Setenv("DB_USER", dbConfig.User)
+	t.Setenv("DB_PASSWORD", dbConfig.Password)
+	broker := testenv.SetupFakeKafkaBroker(t)
+
+	// Initialization replaces the global repository shared by the other tests in this package
+	previousRepo := database.Repo
+	t.Cleanup(func() { database.Repo = previousRepo })
+
+	container, err := components.NewConsumerContainer()
+	require.NoError(t, err)
+	assert.Nil(t, container.Server, "Consumer process must not configure the HTTP server")
+	assert.Nil(t, container.Router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- container.RunConsumers(ctx) }()
+
+	require.Eventually(t, func() bool {
+		joined := testenv.JoinedConsumerGroups(broker)
+		return joined["deposit-processor-group"] && joined["withdrawal-processor-group"]
+	}, 10*time.Second, 50*time.Millisecond, "Both consumer groups should join the broker")
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Consumer process did not shut down")
+	}
+	assert.True(t, container.IsShuttingDown())
+	assert.Error(t, container.Database.Ping(context.Background()), "Shutdown should close the database after the consumers stop")
+}
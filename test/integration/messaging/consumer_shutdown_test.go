@@ -0,0 +1,123 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDepositRepository holds every deposit until release is closed
+type blockingDepositRepository struct {
+	database.Repository
+	started   chan struct{}
+	release   chan struct{}
+	calls     atomic.Int32
+	cancelled atomic.Bool // Whether a deposit saw its context cancelled
+}
+
+func newBlockingDepositRepository() *blockingDepositRepository {
+	return &blockingDepositRepository{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+}
+
+func (r *blockingDepositRepository) AtomicDepositWithIdempotency(ctx context.Context, accountID, amount int, idempotencyKey string) (*models.Account, error) {
+	r.calls.Add(1)
+	select {
+	case r.started <- struct{}{}:
+	default:
+	}
+
+	<-r.release
+	if ctx.Err() != nil {
+		r.cancelled.Store(true)
+		return nil, ctx.Err()
+	}
+	return &models.Account{Id: accountID, Balance: amount}, nil
+}
+
+func (r *blockingDepositRepository) SetOperationStatus(ctx context.Context, operationID, status, reason string) error {
+	return nil
+}
+
+// TestDepositConsumer_ShutdownDrainsInFlightMessage stops the session while a deposit is being
+// processed: that deposit must finish and be committed, and the buffered ones must not be started
+func TestDepositConsumer_ShutdownDrainsInFlightMessage(t *testing.T) {
+	repo := newBlockingDepositRepository()
+	eventPublisher := messaging.NewEventCapture()
+	payloads := depositPayloads(t, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claim := testenv.NewFakeConsumerGroupClaim(kafka.TopicDepositRequests, len(payloads))
+	session := testenv.NewFakeConsumerGroupSession(ctx)
+	for _, payload := range payloads {
+		claim.Send(payload)
+	}
+
+	done := make(chan error, 1)
+	handler := messaging.NewDepositConsumerHandler(commitBatchingTestConfig(10, time.Hour), eventPublisher, repo)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	<-repo.started
+	cancel() // Shutdown begins mid-consumption
+	close(repo.release)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consumer did not stop after the session ended")
+	}
+
+	assert.False(t, repo.cancelled.Load(), "The in-flight deposit should not be cut off")
+	assert.Equal(t, int32(1), repo.calls.Load(), "No new message should be started after shutdown")
+	assert.Len(t, session.MarkedMessages(), 1)
+	assert.Equal(t, 1, session.CommittedMessages(), "Only the finished deposit should be committed")
+	assert.Len(t, eventPublisher.GetDepositCompletedEvents(), 1)
+}
+
+// TestDepositConsumer_ShutdownDuringRetryLeavesMessageUncommitted stops the session while a
+// failed deposit waits to be retried: the message is unfinished, so it is neither committed
+// nor dead-lettered, and the next owner of the partition processes it again
+func TestDepositConsumer_ShutdownDuringRetryLeavesMessageUncommitted(t *testing.T) {
+	repo := &failingDepositRepository{}
+	eventPublisher := messaging.NewEventCapture()
+	config := deadLetterTestConfig(3)
+	config.ConsumerRetryBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claim := testenv.NewFakeConsumerGroupClaim(kafka.TopicDepositRequests, 1)
+	session := testenv.NewFakeConsumerGroupSession(ctx)
+	claim.Send(depositPayloads(t, 1)[0])
+
+	done := make(chan error, 1)
+	handler := messaging.NewDepositConsumerHandler(config, eventPublisher, repo)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	require.Eventually(t, func() bool {
+		return repo.calls.Load() == 1
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consumer did not stop during the retry backoff")
+	}
+
+	assert.Equal(t, int32(1), repo.calls.Load())
+	assert.Empty(t, session.MarkedMessages())
+	assert.Zero(t, session.CommittedMessages())
+	assert.Empty(t, eventPublisher.GetDeadLetterEvents())
+}
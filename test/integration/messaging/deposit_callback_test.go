@@ -0,0 +1,158 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/memory"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/validation"
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postDeposit(router http.Handler, accountID int, body map[string]interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/deposit", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// allowLoopbackCallbacks lets callbacks reach the httptest stub, which listens on 127.0.0.1
+func allowLoopbackCallbacks(t *testing.T) {
+	validation.SetAllowPrivateCallbackHosts(true)
+	t.Cleanup(func() { validation.SetAllowPrivateCallbackHosts(false) })
+}
+
+// TestDepositCallback_ReceivesCompletion deposits with a callback_url and checks the stub
+// receives the completion once the consumer has processed the deposit, after one failed attempt.
+// As in the deployed setup, the API only forwards the URL; the consumer's publisher delivers it.
+func TestDepositCallback_ReceivesCompletion(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	defer database.Repo.Reset(context.Background())
+	allowLoopbackCallbacks(t)
+
+	var attempts atomic.Int32
+	received := make(chan messaging.DepositCallbackPayload, 1)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// First delivery fails so the retry path is exercised
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var payload messaging.DepositCallbackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer stub.Close()
+
+	capture := messaging.NewEventCapture()
+	router := testenv.SetupTestRouterWithEventPublisher(capture)
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+
+	resp := postDeposit(router, accountID, map[string]interface{}{"amount": 2500, "callback_url": stub.URL + "/deposits"})
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	var accepted map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &accepted))
+	operationID := accepted["operation_id"].(string)
+
+	requested := capture.GetDepositRequestedEvents()
+	require.Len(t, requested, 1)
+	assert.Equal(t, stub.URL+"/deposits", requested[0].CallbackURL)
+
+	publisher := messaging.NewDepositCallbackPublisher(capture)
+	handler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), publisher, database.Repo)
+	testenv.ConsumeEvents(t, handler, kafka.TopicDepositRequests, requested...)
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, operationID, payload.OperationID)
+		assert.Equal(t, accountID, payload.AccountID)
+		assert.Equal(t, 2500, payload.Amount)
+		assert.Equal(t, 2500, payload.BalanceAfter)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Callback was not delivered")
+	}
+
+	require.NoError(t, publisher.Close())
+	assert.Equal(t, int32(2), attempts.Load(), "Delivery should succeed on the retry and stop")
+}
+
+func TestDepositCallback_InvalidURLRejected(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	defer database.Repo.Reset(context.Background())
+
+	capture := messaging.NewEventCapture()
+	router := testenv.SetupTestRouterWithEventPublisher(capture)
+
+	accountID := testenv.CreateAccount(t, router, "Bruno")
+
+	invalid := []string{
+		"not a url",
+		"ftp://example.com/hook",
+		"/relative/path",
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+	}
+	for _, callbackURL := range invalid {
+		resp := postDeposit(router, accountID, map[string]interface{}{"amount": 100, "callback_url": callbackURL})
+		assert.Equal(t, http.StatusBadRequest, resp.Code, callbackURL)
+	}
+	assert.Empty(t, capture.GetDepositRequestedEvents(), "Rejected deposits must not be published")
+
+	resp := postDeposit(router, accountID, map[string]interface{}{"amount": 100, "callback_url": "https://example.com/hook"})
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+}
+
+// TestDepositConsumer_CallbackRefusesPrivateHosts checks the consumer re-validates callback URLs
+// that arrive over Kafka instead of trusting whichever producer published them
+func TestDepositConsumer_CallbackRefusesPrivateHosts(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(nil)
+	accountID := repo.CreateAccount(ctx, "Alice")
+
+	var requests atomic.Int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer stub.Close()
+
+	capture := messaging.NewEventCapture()
+	publisher := messaging.NewDepositCallbackPublisher(capture)
+	handler := messaging.NewDepositConsumerHandler(deadLetterTestConfig(1), publisher, repo)
+
+	event := messaging.DepositRequestedEvent{
+		OperationID:    "op-1",
+		IdempotencyKey: "deposit-key-1",
+		AccountID:      accountID,
+		Amount:         1000,
+		CallbackURL:    stub.URL + "/deposits",
+		Timestamp:      time.Now(),
+	}
+	testenv.ConsumeEvents(t, handler, kafka.TopicDepositRequests, event)
+	require.NoError(t, publisher.Close())
+
+	assert.Len(t, capture.GetDepositCompletedEvents(), 1, "The deposit completes regardless of its callback")
+	assert.Zero(t, requests.Load(), "The stub listens on loopback and must not be called")
+}
@@ -0,0 +1,177 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/memory"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDepositRepository fails every deposit with a transient error
+type failingDepositRepository struct {
+	database.Repository
+	calls atomic.Int32
+}
+
+func (r *failingDepositRepository) AtomicDepositWithIdempotency(ctx context.Context, accountID, amount int, idempotencyKey string) (*models.Account, error) {
+	r.calls.Add(1)
+	return nil, errors.New("connection refused")
+}
+
+func (r *failingDepositRepository) SetOperationStatus(ctx context.Context, operationID, status, reason string) error {
+	return nil
+}
+
+// flakyCompletedPublisher fails the first failures deposit completed publishes
+type flakyCompletedPublisher struct {
+	*messaging.EventCapture
+	failures atomic.Int32
+}
+
+func (p *flakyCompletedPublisher) PublishDepositCompleted(event messaging.DepositCompletedEvent) error {
+	if p.failures.Add(-1) >= 0 {
+		return errors.New("broker unavailable")
+	}
+	return p.EventCapture.PublishDepositCompleted(event)
+}
+
+func deadLetterTestConfig(maxRetries int) *kafka.Config {
+	config := kafka.NewConfigFromEnv()
+	config.ConsumerMaxRetries = maxRetries
+	config.ConsumerRetryBackoff = time.Millisecond
+	return config
+}
+
+// TestDepositConsumer_DeadLetterAfterRetries verifies that a message failing on every
+// attempt lands in the DLQ once retries are exhausted and its offset is committed
+func TestDepositConsumer_DeadLetterAfterRetries(t *testing.T) {
+	eventPublisher := messaging.NewEventCapture()
+	repo := &failingDepositRepository{}
+
+	event := messaging.DepositRequestedEvent{
+		OperationID:    "op-1",
+		IdempotencyKey: "deposit-key-1",
+		AccountID:      1,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	handler := messaging.NewDepositConsumerHandler(deadLetterTestConfig(3), eventPublisher, repo)
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicDepositRequests, payload)
+
+	assert.Equal(t, int32(3), repo.calls.Load(), "Should attempt processing maxRetries times")
+
+	deadLetters := eventPublisher.GetDeadLetterEvents()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, kafka.TopicDepositRequests, deadLetters[0].OriginalTopic)
+	assert.Equal(t, kafka.TopicDepositRequestsDLQ, kafka.DeadLetterTopic(deadLetters[0].OriginalTopic))
+	assert.Equal(t, string(payload), deadLetters[0].Payload)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Contains(t, deadLetters[0].ErrorMessage, "connection refused")
+
+	// Offset committed so the partition is no longer blocked
+	assert.Len(t, session.MarkedMessages(), 1)
+	assert.Equal(t, 1, session.Commits())
+	assert.Empty(t, eventPublisher.GetDepositCompletedEvents())
+}
+
+// TestDepositConsumer_MalformedMessageSkipsRetries verifies that messages which cannot be
+// deserialized go straight to the DLQ without retrying
+func TestDepositConsumer_MalformedMessageSkipsRetries(t *testing.T) {
+	eventPublisher := messaging.NewEventCapture()
+	repo := &failingDepositRepository{}
+
+	payload := []byte(`{"account_id": "not-a-number"`)
+
+	handler := messaging.NewDepositConsumerHandler(deadLetterTestConfig(5), eventPublisher, repo)
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicDepositRequests, payload)
+
+	assert.Zero(t, repo.calls.Load(), "Repository should never be called for a malformed message")
+
+	deadLetters := eventPublisher.GetDeadLetterEvents()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, string(payload), deadLetters[0].Payload)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+
+	assert.Len(t, session.MarkedMessages(), 1)
+	assert.Equal(t, 1, session.Commits())
+}
+
+// TestDepositConsumer_UnknownEventVersionDeadLettered verifies that an event from a newer schema,
+// whose fields no longer match DepositRequestedEvent, is parked in the DLQ without retries
+func TestDepositConsumer_UnknownEventVersionDeadLettered(t *testing.T) {
+	eventPublisher := messaging.NewEventCapture()
+	repo := &failingDepositRepository{}
+
+	payload := []byte(`{"event_version": "2", "event_type": "deposit.requested", "account_id": "acc-1", "amount": {"value": 1000}}`)
+
+	handler := messaging.NewDepositConsumerHandler(deadLetterTestConfig(5), eventPublisher, repo)
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicDepositRequests, payload)
+
+	assert.Zero(t, repo.calls.Load(), "Repository should never be called for an unknown version")
+
+	deadLetters := eventPublisher.GetDeadLetterEvents()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+	assert.Contains(t, deadLetters[0].ErrorMessage, "unsupported event version")
+
+	assert.Len(t, session.MarkedMessages(), 1)
+	assert.Equal(t, 1, session.Commits())
+}
+
+// TestDepositConsumer_RepublishesCompletedAfterPublishFailure verifies that when the completed
+// event fails to publish after the deposit committed, the retry (which finds the idempotency key
+// already used) publishes it from the stored result instead of dropping it
+func TestDepositConsumer_RepublishesCompletedAfterPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(nil)
+	accountID := repo.CreateAccount(ctx, "Alice")
+	require.NoError(t, repo.CreatePendingOperation(ctx, "op-1", "deposit", accountID, 1000))
+	require.NoError(t, repo.CreatePendingOperation(ctx, "op-2", "deposit", accountID, 1000))
+
+	eventPublisher := &flakyCompletedPublisher{EventCapture: messaging.NewEventCapture()}
+	eventPublisher.failures.Store(1)
+
+	first := messaging.DepositRequestedEvent{
+		OperationID:    "op-1",
+		IdempotencyKey: "deposit-key-1",
+		AccountID:      accountID,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+	handler := messaging.NewDepositConsumerHandler(deadLetterTestConfig(3), eventPublisher, repo)
+	testenv.ConsumeEvents(t, handler, kafka.TopicDepositRequests, first)
+
+	completed := eventPublisher.GetDepositCompletedEvents()
+	require.Len(t, completed, 1, "the retry must publish the completed event")
+	assert.Equal(t, "op-1", completed[0].OperationID)
+	assert.Equal(t, 1000, completed[0].BalanceAfter)
+	assert.Empty(t, eventPublisher.GetDeadLetterEvents())
+
+	account, ok := repo.GetAccount(ctx, accountID)
+	require.True(t, ok)
+	assert.Equal(t, 1000, account.Balance, "the deposit is applied once")
+
+	// A different operation reusing the key is a duplicate and publishes nothing
+	second := first
+	second.OperationID = "op-2"
+	testenv.ConsumeEvents(t, handler, kafka.TopicDepositRequests, second)
+
+	assert.Len(t, eventPublisher.GetDepositCompletedEvents(), 1)
+	operation, err := repo.GetOperation(ctx, "op-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusDuplicate, operation.Status)
+}
@@ -0,0 +1,188 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getOperation(t *testing.T, router http.Handler, operationID string) models.Operation {
+	req := httptest.NewRequest("GET", "/operations/"+operationID, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var operation models.Operation
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &operation))
+	return operation
+}
+
+func acceptedOperationID(t *testing.T, resp *httptest.ResponseRecorder) string {
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	var body struct {
+		OperationID string `json:"operation_id"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.NotEmpty(t, body.OperationID)
+	return body.OperationID
+}
+
+// TestOperationStatus_PendingUntilConsumed deposits through the API and follows the operation
+// from pending (published, not yet consumed) to completed once the consumer has applied it
+func TestOperationStatus_PendingUntilConsumed(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	eventPublisher.Reset()
+
+	operationID := acceptedOperationID(t, postDeposit(router, accountID, map[string]interface{}{"amount": 1000}))
+
+	operation := getOperation(t, router, operationID)
+	assert.Equal(t, models.OperationStatusPending, operation.Status)
+	assert.Equal(t, "deposit", operation.Type)
+	assert.Equal(t, accountID, operation.AccountID)
+	assert.Equal(t, 1000, operation.Amount)
+	assert.Empty(t, operation.Reason)
+
+	requested := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, requested, 1)
+
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, container.GetDatabase())
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, requested[0])
+
+	operation = getOperation(t, router, operationID)
+	assert.Equal(t, models.OperationStatusCompleted, operation.Status)
+	assert.Empty(t, operation.Reason)
+	assert.Equal(t, 1000, testenv.GetBalance(t, router, accountID))
+
+	// A redelivery is a duplicate and leaves the final status as it is
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, requested[0])
+	assert.Equal(t, models.OperationStatusCompleted, getOperation(t, router, operationID).Status)
+}
+
+// TestOperationStatus_DuplicateDeposit sends the same deposit twice: both responses echo the
+// same idempotency key, and the second operation ends as duplicate without moving money
+func TestOperationStatus_DuplicateDeposit(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Carol")
+	eventPublisher.Reset()
+
+	accept := func() (operationID string, idempotencyKey string) {
+		resp := postDeposit(router, accountID, map[string]interface{}{"amount": 700, "nonce": "order-42"})
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		var body struct {
+			OperationID    string `json:"operation_id"`
+			IdempotencyKey string `json:"idempotency_key"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		return body.OperationID, body.IdempotencyKey
+	}
+	firstID, firstKey := accept()
+	secondID, secondKey := accept()
+
+	require.NotEmpty(t, firstKey)
+	assert.Equal(t, firstKey, secondKey, "identical requests must share an idempotency key")
+	assert.NotEqual(t, firstID, secondID)
+
+	requested := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, requested, 2)
+	assert.Equal(t, firstKey, requested[0].IdempotencyKey, "the echoed key is the one the consumer deduplicates on")
+
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, container.GetDatabase())
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, requested...)
+
+	assert.Equal(t, models.OperationStatusCompleted, getOperation(t, router, firstID).Status)
+	assert.Equal(t, models.OperationStatusDuplicate, getOperation(t, router, secondID).Status)
+	assert.Equal(t, 700, testenv.GetBalance(t, router, accountID))
+}
+
+// TestOperationStatus_FailedWithReason freezes the account after the deposit was accepted:
+// the consumer rejects it and the operation records why
+func TestOperationStatus_FailedWithReason(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+	eventPublisher.Reset()
+
+	operationID := acceptedOperationID(t, postDeposit(router, accountID, map[string]interface{}{"amount": 500}))
+	require.NoError(t, db.SetFrozen(context.Background(), accountID, true))
+
+	requested := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, requested, 1)
+
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, db)
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, requested[0])
+
+	operation := getOperation(t, router, operationID)
+	assert.Equal(t, models.OperationStatusFailed, operation.Status)
+	assert.Equal(t, messaging.FailureReasonAccountFrozen, operation.Reason)
+}
+
+// TestOperationStatus_Withdrawal follows withdrawals through the same statuses as deposits:
+// pending until consumed, then completed, or failed with the reason it was rejected
+func TestOperationStatus_Withdrawal(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Dave")
+	testenv.SetBalance(t, accountID, 1000)
+	eventPublisher.Reset()
+
+	completedID := testenv.Withdraw(t, router, accountID, 600)
+	operation := getOperation(t, router, completedID)
+	assert.Equal(t, models.OperationStatusPending, operation.Status)
+	assert.Equal(t, "withdraw", operation.Type)
+	assert.Equal(t, 600, operation.Amount)
+
+	failedID := testenv.Withdraw(t, router, accountID, 700)
+	container.ProcessWithdrawalRequests(t)
+
+	assert.Equal(t, models.OperationStatusCompleted, getOperation(t, router, completedID).Status)
+	operation = getOperation(t, router, failedID)
+	assert.Equal(t, models.OperationStatusFailed, operation.Status)
+	assert.Equal(t, messaging.FailureReasonInsufficientFunds, operation.Reason)
+	assert.Equal(t, 400, testenv.GetBalance(t, router, accountID))
+}
+
+// TestOperationStatus_UnknownOperation returns OPERATION_NOT_FOUND for an ID never issued
+func TestOperationStatus_UnknownOperation(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	req := httptest.NewRequest("GET", "/operations/does-not-exist", nil)
+	resp := httptest.NewRecorder()
+	container.GetRouter().ServeHTTP(resp, req)
+
+	testenv.AssertErrorCode(t, resp, http.StatusNotFound, "OPERATION_NOT_FOUND")
+}
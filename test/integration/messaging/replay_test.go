@@ -0,0 +1,180 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplayTransactions seeds every kind of transaction and checks each one is republished as
+// the matching completed event, flagged as a replay
+func TestReplayTransactions(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	ctx := context.Background()
+
+	alice := testenv.CreateAccount(t, router, "Alice")
+	bob := testenv.CreateAccount(t, router, "Bob")
+	carol := testenv.CreateAccount(t, router, "Carol")
+
+	_, err := db.AtomicDepositWithIdempotency(ctx, alice, 10000, "replay-deposit")
+	require.NoError(t, err)
+	_, err = db.AtomicWithdraw(ctx, alice, 1000)
+	require.NoError(t, err)
+	_, _, err = db.AtomicTransfer(ctx, alice, bob, 2000)
+	require.NoError(t, err)
+	_, err = db.AtomicBatchTransfer(ctx, alice, []postgres.Transfer{{ToID: bob, Amount: 500}, {ToID: carol, Amount: 700}})
+	require.NoError(t, err)
+	repo, ok := db.(*postgres.PostgresRepository)
+	require.True(t, ok)
+	_, err = repo.ApplyInterest(ctx, 0.01)
+	require.NoError(t, err)
+
+	// Nothing is published before the replay
+	publisher := messaging.NewEventCapture()
+
+	// A dry run only counts
+	dryRun, err := messaging.ReplayTransactions(ctx, db, publisher, 0, time.Time{}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dryRun.Deposits)
+	assert.Equal(t, 1, dryRun.Withdrawals)
+	assert.Equal(t, 3, dryRun.Transfers)
+	assert.Equal(t, 3, dryRun.Interest)
+	assert.Zero(t, dryRun.Skipped)
+	assert.Zero(t, publisher.GetEventCount())
+
+	result, err := messaging.ReplayTransactions(ctx, db, publisher, 0, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, dryRun, result, "A real run should publish what the dry run counted")
+
+	deposits := publisher.GetDepositCompletedEvents()
+	require.Len(t, deposits, 1)
+	assert.Equal(t, alice, deposits[0].AccountID)
+	assert.Equal(t, 10000, deposits[0].Amount)
+	assert.Equal(t, 10000, deposits[0].BalanceAfter)
+	assert.True(t, deposits[0].Replay)
+
+	withdrawals := publisher.GetWithdrawalCompletedEvents()
+	require.Len(t, withdrawals, 1)
+	assert.Equal(t, 1000, withdrawals[0].Amount, "Debits are replayed with a positive amount")
+	assert.Equal(t, 9000, withdrawals[0].BalanceAfter)
+	assert.True(t, withdrawals[0].Replay)
+
+	transfers := publisher.GetTransferCompletedEvents()
+	require.Len(t, transfers, 3)
+	assert.Equal(t, []int{alice, bob, 2000, 7000, 2000}, []int{transfers[0].FromAccountID, transfers[0].ToAccountID, transfers[0].Amount, transfers[0].FromBalanceAfter, transfers[0].ToBalanceAfter})
+	assert.Equal(t, []int{alice, bob, 500}, []int{transfers[1].FromAccountID, transfers[1].ToAccountID, transfers[1].Amount})
+	assert.Equal(t, []int{alice, carol, 700}, []int{transfers[2].FromAccountID, transfers[2].ToAccountID, transfers[2].Amount})
+	for _, transfer := range transfers {
+		assert.True(t, transfer.Replay)
+	}
+
+	interest := publisher.GetInterestAppliedEvents()
+	require.Len(t, interest, 3)
+	for _, event := range interest {
+		assert.True(t, event.Replay)
+		assert.Positive(t, event.Amount)
+	}
+}
+
+// TestReplayTransactions_FromID starts the replay in the middle of a transfer: its credit has
+// no debit to pair with, so it is skipped rather than published half-known
+func TestReplayTransactions_FromID(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	ctx := context.Background()
+
+	alice := testenv.CreateAccount(t, router, "Alice")
+	bob := testenv.CreateAccount(t, router, "Bob")
+
+	_, err := db.AtomicDepositWithIdempotency(ctx, alice, 5000, "replay-from-deposit")
+	require.NoError(t, err)
+	_, _, err = db.AtomicTransfer(ctx, alice, bob, 1000)
+	require.NoError(t, err)
+	_, err = db.AtomicWithdraw(ctx, bob, 400)
+	require.NoError(t, err)
+
+	records, err := db.ListTransactions(ctx, 0, time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, "transfer_in", records[2].Type)
+
+	publisher := messaging.NewEventCapture()
+	result, err := messaging.ReplayTransactions(ctx, db, publisher, records[2].ID, time.Time{}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Skipped)
+	assert.Zero(t, result.Transfers)
+	assert.Empty(t, publisher.GetDepositCompletedEvents())
+	require.Len(t, publisher.GetWithdrawalCompletedEvents(), 1)
+	assert.Equal(t, bob, publisher.GetWithdrawalCompletedEvents()[0].AccountID)
+	assert.Equal(t, records[3].ID, result.LastID)
+
+	// Nothing was created after now
+	result, err = messaging.ReplayTransactions(ctx, db, publisher, 0, time.Now().Add(time.Minute), true)
+	require.NoError(t, err)
+	assert.Zero(t, result.Total())
+}
+
+// pagedTransactionRepository serves a fixed transaction log through ListTransactions
+type pagedTransactionRepository struct {
+	database.Repository
+	records []postgres.TransactionRecord
+	queries int
+}
+
+func (r *pagedTransactionRepository) ListTransactions(ctx context.Context, fromID int, since time.Time, limit int) ([]postgres.TransactionRecord, error) {
+	r.queries++
+	page := make([]postgres.TransactionRecord, 0, limit)
+	for _, record := range r.records {
+		if record.ID >= fromID && !record.CreatedAt.Before(since) && len(page) < limit {
+			page = append(page, record)
+		}
+	}
+	return page, nil
+}
+
+// TestReplayTransactions_PairsTransfersAcrossPages puts a transfer's debit and credit in
+// different pages of the log
+func TestReplayTransactions_PairsTransfersAcrossPages(t *testing.T) {
+	repo := &pagedTransactionRepository{}
+	now := time.Now()
+	for id := 1; id <= 499; id++ {
+		repo.records = append(repo.records, postgres.TransactionRecord{ID: id, AccountID: 1, Type: "deposit", Amount: 100, BalanceAfter: id * 100, CreatedAt: now})
+	}
+	repo.records = append(repo.records,
+		postgres.TransactionRecord{ID: 500, AccountID: 1, Type: "transfer_out", Amount: -300, BalanceAfter: 49600, ReferenceID: "ref-1", CreatedAt: now},
+		postgres.TransactionRecord{ID: 501, AccountID: 2, Type: "transfer_in", Amount: 300, BalanceAfter: 300, ReferenceID: "ref-1", CreatedAt: now},
+	)
+
+	publisher := messaging.NewEventCapture()
+	result, err := messaging.ReplayTransactions(context.Background(), repo, publisher, 0, time.Time{}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, repo.queries)
+	assert.Equal(t, 499, result.Deposits)
+	assert.Equal(t, 1, result.Transfers)
+	assert.Equal(t, 501, result.LastID)
+
+	transfers := publisher.GetTransferCompletedEvents()
+	require.Len(t, transfers, 1)
+	assert.Equal(t, 1, transfers[0].FromAccountID)
+	assert.Equal(t, 2, transfers[0].ToAccountID)
+	assert.Equal(t, 300, transfers[0].Amount)
+	assert.Equal(t, 49600, transfers[0].FromBalanceAfter)
+}
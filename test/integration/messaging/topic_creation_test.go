@@ -0,0 +1,92 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTopicCreationBroker starts a mock controller that accepts the first CreateTopics request
+// for each banking topic and answers TOPIC_ALREADY_EXISTS to every later one, the way a real
+// broker does once the topics exist
+func setupTopicCreationBroker(t *testing.T, config *kafka.Config) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+
+	saramaConfig, err := config.ToSaramaConfig()
+	require.NoError(t, err)
+	version := sarama.NewCreateTopicsRequest(saramaConfig.Version, nil, 0, false).Version
+
+	responses := make([]interface{}, 0, 2*len(kafka.GetAllTopics()))
+	for _, topicErr := range []sarama.KError{sarama.ErrNoError, sarama.ErrTopicAlreadyExists} {
+		for _, topic := range kafka.GetAllTopics() {
+			responses = append(responses, &sarama.CreateTopicsResponse{
+				Version:      version,
+				TopicErrors:  map[string]*sarama.TopicError{topic: {Err: topicErr}},
+				TopicResults: map[string]*sarama.CreatableTopicResult{topic: {}},
+			})
+		}
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()),
+		"CreateTopicsRequest": sarama.NewMockSequence(responses...),
+	})
+
+	config.Brokers = []string{broker.Addr()}
+	return broker
+}
+
+// createTopicsRequests returns the CreateTopics requests broker has received
+func createTopicsRequests(broker *sarama.MockBroker) []*sarama.CreateTopicsRequest {
+	var requests []*sarama.CreateTopicsRequest
+	for _, exchange := range broker.History() {
+		if req, ok := exchange.Request.(*sarama.CreateTopicsRequest); ok {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+func TestEnsureTopics_CreatesTopicsIdempotently(t *testing.T) {
+	config := kafka.NewConfigFromEnv()
+	config.TopicPartitions = 6
+	config.TopicReplicationFactor = 1
+	broker := setupTopicCreationBroker(t, config)
+
+	require.NoError(t, kafka.EnsureTopics(config))
+
+	requested := make(map[string]*sarama.TopicDetail)
+	for _, req := range createTopicsRequests(broker) {
+		for topic, detail := range req.TopicDetails {
+			requested[topic] = detail
+		}
+	}
+	require.Len(t, requested, len(kafka.GetAllTopics()))
+	for _, topic := range kafka.GetAllTopics() {
+		require.Contains(t, requested, topic)
+		assert.Equal(t, int32(6), requested[topic].NumPartitions, topic)
+		assert.Equal(t, int16(1), requested[topic].ReplicationFactor, topic)
+	}
+	assert.Contains(t, requested, kafka.TopicDepositRequestsDLQ, "dead-letter topics are created too")
+
+	// A second startup finds every topic in place
+	require.NoError(t, kafka.EnsureTopics(config))
+	assert.Len(t, createTopicsRequests(broker), 2*len(kafka.GetAllTopics()))
+}
+
+func TestEnsureTopics_InvalidLayout(t *testing.T) {
+	config := kafka.NewConfigFromEnv()
+	config.TopicPartitions = 0
+	assert.Error(t, kafka.EnsureTopics(config))
+
+	config = kafka.NewConfigFromEnv()
+	config.TopicReplicationFactor = 0
+	assert.Error(t, kafka.EnsureTopics(config))
+}
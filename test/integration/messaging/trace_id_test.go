@@ -0,0 +1,115 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/tracing"
+	"bank-api/test/integration/testenv"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTraceID_FlowsFromRequestToDepositEvents sends a deposit with an X-Trace-Id header and
+// follows the ID through the requested event, the consumer, and the completed event
+func TestTraceID_FlowsFromRequestToDepositEvents(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	eventPublisher.Reset()
+
+	const traceID = "checkout-7f3a9c"
+	body, _ := json.Marshal(map[string]interface{}{"amount": 1000})
+	req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/deposit", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tracing.Header, traceID)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, traceID, resp.Header().Get(tracing.Header))
+
+	requested := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, requested, 1)
+	assert.Equal(t, traceID, requested[0].TraceID)
+
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, container.GetDatabase())
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, requested[0])
+
+	completed := eventPublisher.GetDepositCompletedEvents()
+	require.Len(t, completed, 1)
+	assert.Equal(t, traceID, completed[0].TraceID)
+	assert.Equal(t, requested[0].OperationID, completed[0].OperationID)
+}
+
+// TestTraceID_GeneratedWhenAbsent checks that a request without the header still gets a trace ID,
+// and that the one returned to the client is the one carried by the event
+func TestTraceID_GeneratedWhenAbsent(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+	eventPublisher.Reset()
+
+	resp := postDeposit(router, accountID, map[string]interface{}{"amount": 500})
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	traceID := resp.Header().Get(tracing.Header)
+	require.NotEmpty(t, traceID, "A trace ID should be generated when the client doesn't send one")
+
+	requested := eventPublisher.GetDepositRequestedEvents()
+	require.Len(t, requested, 1)
+	assert.Equal(t, traceID, requested[0].TraceID)
+}
+
+// TestTraceID_FlowsFromRequestToWithdrawalEvents follows an X-Trace-Id through the withdrawal
+// path the same way as for deposits
+func TestTraceID_FlowsFromRequestToWithdrawalEvents(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Carla")
+	testenv.Deposit(t, router, accountID, 5000)
+	depositHandler := messaging.NewDepositConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, container.GetDatabase())
+	testenv.ConsumeEvents(t, depositHandler, kafka.TopicDepositRequests, eventPublisher.GetDepositRequestedEvents()...)
+	eventPublisher.Reset()
+
+	const traceID = "payout-2b8e41"
+	body, _ := json.Marshal(map[string]interface{}{"amount": 1000})
+	req := httptest.NewRequest("POST", "/accounts/"+strconv.Itoa(accountID)+"/withdraw", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tracing.Header, traceID)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	requested := eventPublisher.GetWithdrawalRequestedEvents()
+	require.Len(t, requested, 1)
+	assert.Equal(t, traceID, requested[0].TraceID)
+
+	container.ProcessWithdrawalRequests(t)
+
+	completed := eventPublisher.GetWithdrawalCompletedEvents()
+	require.Len(t, completed, 1)
+	assert.Equal(t, traceID, completed[0].TraceID)
+}
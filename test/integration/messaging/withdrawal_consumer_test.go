@@ -0,0 +1,137 @@
+package messaging
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/idempotency"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithdrawalConsumer_DuplicateRedelivery verifies that a redelivered withdrawal
+// request only debits the account once
+func TestWithdrawalConsumer_DuplicateRedelivery(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Alice")
+	testenv.SetBalance(t, accountID, 5000)
+	eventPublisher.Reset()
+
+	event := messaging.WithdrawalRequestedEvent{
+		OperationID:    uuid.New().String(),
+		IdempotencyKey: idempotency.GenerateKey("withdraw", accountID, 1000),
+		AccountID:      accountID,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+
+	// Same message delivered twice (consumer crashed before committing)
+	config := kafka.NewConfigFromEnv()
+	config.ConsumerCommitBatchSize = 1
+	handler := messaging.NewWithdrawalConsumerHandler(config, eventPublisher, db)
+	session := testenv.ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests, event, event)
+
+	// Both deliveries are acknowledged (the duplicate is a successful no-op)
+	assert.Len(t, session.MarkedMessages(), 2)
+	assert.Equal(t, 2, session.Commits())
+
+	// Balance debited exactly once
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, 4000, acc.Balance, "Balance should only decrease once")
+
+	// Only one completion event
+	completed := eventPublisher.GetWithdrawalCompletedEvents()
+	require.Len(t, completed, 1)
+	assert.Equal(t, 4000, completed[0].BalanceAfter)
+	assert.Equal(t, event.OperationID, completed[0].OperationID)
+}
+
+// TestWithdrawalConsumer_InsufficientFunds verifies that insufficient funds publishes a
+// TransactionFailedEvent and commits the message instead of retrying forever
+func TestWithdrawalConsumer_InsufficientFunds(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+	eventPublisher := container.GetEventPublisher()
+
+	accountID := testenv.CreateAccount(t, router, "Bob")
+	testenv.SetBalance(t, accountID, 500)
+	eventPublisher.Reset()
+
+	event := messaging.WithdrawalRequestedEvent{
+		OperationID:    uuid.New().String(),
+		IdempotencyKey: idempotency.GenerateKey("withdraw", accountID, 1000),
+		AccountID:      accountID,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+
+	handler := messaging.NewWithdrawalConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, db)
+	session := testenv.ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests, event)
+
+	// Business failure is final - message is committed
+	assert.Len(t, session.MarkedMessages(), 1)
+
+	failed := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "withdrawal", failed[0].TransactionType)
+	assert.Equal(t, event.OperationID, failed[0].OperationID, "the failure echoes the 202's operation_id")
+	assert.Equal(t, accountID, failed[0].AccountID)
+	assert.Equal(t, 1000, failed[0].Amount)
+	assert.Empty(t, eventPublisher.GetWithdrawalCompletedEvents())
+
+	// Balance untouched
+	acc, ok := db.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, 500, acc.Balance)
+
+	// Failed withdrawals are not recorded as processed, so a later retry with funds succeeds
+	testenv.SetBalance(t, accountID, 1000)
+	acc, err := db.AtomicWithdrawWithIdempotency(context.Background(), accountID, 1000, event.IdempotencyKey)
+	require.NoError(t, err)
+	assert.Equal(t, 500, acc.Balance)
+}
+
+// TestWithdrawalConsumer_RepositoryDuplicate verifies the repository-level idempotency check
+func TestWithdrawalConsumer_RepositoryDuplicate(t *testing.T) {
+	testenv.SetupIntegrationTest(t)
+	container := testenv.NewTestContainer()
+	defer container.Reset()
+
+	router := container.GetRouter()
+	db := container.GetDatabase()
+
+	accountID := testenv.CreateAccount(t, router, "Carol")
+	testenv.SetBalance(t, accountID, 3000)
+
+	key := idempotency.GenerateKey("withdraw", accountID, 1000)
+
+	acc1, err1 := db.AtomicWithdrawWithIdempotency(context.Background(), accountID, 1000, key)
+	require.NoError(t, err1)
+	assert.Equal(t, 2000, acc1.Balance)
+
+	acc2, err2 := db.AtomicWithdrawWithIdempotency(context.Background(), accountID, 1000, key)
+	require.ErrorIs(t, err2, postgres.ErrDuplicateOperation)
+	require.NotNil(t, acc2)
+	assert.Equal(t, 2000, acc2.Balance, "Duplicate should return the original result balance")
+
+	// Deposit and withdrawal keys for the same account/amount never collide
+	assert.NotEqual(t, idempotency.GenerateKey("deposit", accountID, 1000), key)
+}
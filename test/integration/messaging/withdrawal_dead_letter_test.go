@@ -0,0 +1,159 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/memory"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWithdrawalRepository fails every withdrawal with a transient error
+type failingWithdrawalRepository struct {
+	database.Repository
+	calls atomic.Int32
+}
+
+func (r *failingWithdrawalRepository) AtomicWithdrawWithIdempotency(ctx context.Context, accountID, amount int, idempotencyKey string) (*models.Account, error) {
+	r.calls.Add(1)
+	return nil, errors.New("connection refused")
+}
+
+func (r *failingWithdrawalRepository) SetOperationStatus(ctx context.Context, operationID, status, reason string) error {
+	return nil
+}
+
+// flakyWithdrawalPublisher fails the first completedFailures withdrawal completed publishes
+// and the first deadLetterFailures dead-letter publishes
+type flakyWithdrawalPublisher struct {
+	*messaging.EventCapture
+	completedFailures  atomic.Int32
+	deadLetterFailures atomic.Int32
+}
+
+func (p *flakyWithdrawalPublisher) PublishWithdrawalCompleted(event messaging.WithdrawalCompletedEvent) error {
+	if p.completedFailures.Add(-1) >= 0 {
+		return errors.New("broker unavailable")
+	}
+	return p.EventCapture.PublishWithdrawalCompleted(event)
+}
+
+func (p *flakyWithdrawalPublisher) PublishDeadLetter(event messaging.DeadLetterEvent) error {
+	if p.deadLetterFailures.Add(-1) >= 0 {
+		return errors.New("broker unavailable")
+	}
+	return p.EventCapture.PublishDeadLetter(event)
+}
+
+// TestWithdrawalConsumer_DeadLetterAfterRetries verifies that a withdrawal failing on every
+// attempt is parked in the DLQ, retrying the DLQ publish itself, before its offset is committed
+func TestWithdrawalConsumer_DeadLetterAfterRetries(t *testing.T) {
+	eventPublisher := &flakyWithdrawalPublisher{EventCapture: messaging.NewEventCapture()}
+	eventPublisher.deadLetterFailures.Store(2)
+	repo := &failingWithdrawalRepository{}
+
+	event := messaging.WithdrawalRequestedEvent{
+		OperationID:    "op-1",
+		IdempotencyKey: "withdraw-key-1",
+		AccountID:      1,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	handler := messaging.NewWithdrawalConsumerHandler(deadLetterTestConfig(3), eventPublisher, repo)
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicWithdrawalRequests, payload)
+
+	assert.Equal(t, int32(3), repo.calls.Load(), "Should attempt processing maxRetries times")
+
+	deadLetters := eventPublisher.GetDeadLetterEvents()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, kafka.TopicWithdrawalRequestsDLQ, kafka.DeadLetterTopic(deadLetters[0].OriginalTopic))
+	assert.Equal(t, string(payload), deadLetters[0].Payload)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Contains(t, deadLetters[0].ErrorMessage, "connection refused")
+
+	assert.Len(t, session.MarkedMessages(), 1)
+	assert.Empty(t, eventPublisher.GetWithdrawalCompletedEvents())
+}
+
+// TestWithdrawalConsumer_MalformedMessageSkipsRetries verifies that an undecodable withdrawal
+// request goes straight to the DLQ instead of being skipped by the next commit
+func TestWithdrawalConsumer_MalformedMessageSkipsRetries(t *testing.T) {
+	eventPublisher := messaging.NewEventCapture()
+	repo := &failingWithdrawalRepository{}
+
+	payload := []byte(`{"account_id": "not-a-number"`)
+
+	handler := messaging.NewWithdrawalConsumerHandler(deadLetterTestConfig(5), eventPublisher, repo)
+	session := testenv.ConsumeMessages(t, handler, kafka.TopicWithdrawalRequests, payload)
+
+	assert.Zero(t, repo.calls.Load(), "Repository should never be called for a malformed message")
+
+	deadLetters := eventPublisher.GetDeadLetterEvents()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, string(payload), deadLetters[0].Payload)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+
+	assert.Len(t, session.MarkedMessages(), 1)
+}
+
+// TestWithdrawalConsumer_RepublishesCompletedAfterPublishFailure verifies that a withdrawal whose
+// completed event failed to publish after the debit committed still publishes it on retry
+func TestWithdrawalConsumer_RepublishesCompletedAfterPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(nil)
+	accountID := repo.CreateAccount(ctx, "Alice")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 5000, "deposit-key-1")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePendingOperation(ctx, "op-1", "withdraw", accountID, 1000))
+	require.NoError(t, repo.CreatePendingOperation(ctx, "op-2", "withdraw", accountID, 1000))
+
+	eventPublisher := &flakyWithdrawalPublisher{EventCapture: messaging.NewEventCapture()}
+	eventPublisher.completedFailures.Store(1)
+
+	first := messaging.WithdrawalRequestedEvent{
+		OperationID:    "op-1",
+		IdempotencyKey: "withdraw-key-1",
+		AccountID:      accountID,
+		Amount:         1000,
+		Timestamp:      time.Now(),
+	}
+	handler := messaging.NewWithdrawalConsumerHandler(deadLetterTestConfig(3), eventPublisher, repo)
+	testenv.ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests, first)
+
+	completed := eventPublisher.GetWithdrawalCompletedEvents()
+	require.Len(t, completed, 1, "the retry must publish the completed event")
+	assert.Equal(t, "op-1", completed[0].OperationID)
+	assert.Equal(t, 4000, completed[0].BalanceAfter)
+	assert.Empty(t, eventPublisher.GetDeadLetterEvents())
+
+	account, ok := repo.GetAccount(ctx, accountID)
+	require.True(t, ok)
+	assert.Equal(t, 4000, account.Balance, "the withdrawal is applied once")
+
+	operation, err := repo.GetOperation(ctx, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusCompleted, operation.Status)
+
+	// A different operation reusing the key is a duplicate and publishes nothing
+	second := first
+	second.OperationID = "op-2"
+	testenv.ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests, second)
+
+	assert.Len(t, eventPublisher.GetWithdrawalCompletedEvents(), 1)
+	operation, err = repo.GetOperation(ctx, "op-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusDuplicate, operation.Status)
+}
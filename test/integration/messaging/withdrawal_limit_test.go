@@ -0,0 +1,73 @@
+package messaging
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithdrawalConsumer_SavingsWithdrawalLimit verifies that a savings account rejects
+// withdrawals past SAVINGS_WITHDRAWAL_LIMIT with a withdrawal_limit_exceeded failure,
+// while a checking account making the same withdrawals is unaffected
+func TestWithdrawalConsumer_SavingsWithdrawalLimit(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+	cfg.SavingsWithdrawalLimit = 2
+	cfg.SavingsWithdrawalPeriod = "24h"
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	ctx := context.Background()
+	savingsID := repo.CreateAccountWithType(ctx, "Alice", models.DefaultCurrency, models.AccountTypeSavings)
+	checkingID := repo.CreateAccountWithType(ctx, "Bruno", models.DefaultCurrency, models.AccountTypeChecking)
+	for _, id := range []int{savingsID, checkingID} {
+		_, err := repo.AtomicDepositWithIdempotency(ctx, id, 10000, uuid.New().String())
+		require.NoError(t, err)
+	}
+
+	eventPublisher := messaging.NewEventCapture()
+	handler := messaging.NewWithdrawalConsumerHandler(kafka.NewConfigFromEnv(), eventPublisher, repo)
+
+	session := testenv.ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests,
+		withdrawalRequest(savingsID, 1000),
+		withdrawalRequest(savingsID, 1000),
+		withdrawalRequest(savingsID, 1000), // third in the period - over the limit
+		withdrawalRequest(checkingID, 1000),
+		withdrawalRequest(checkingID, 1000),
+		withdrawalRequest(checkingID, 1000),
+	)
+
+	// Hitting the limit is a final outcome, so every offset is acknowledged
+	assert.Len(t, session.MarkedMessages(), 6)
+
+	savings, ok := repo.GetAccount(ctx, savingsID)
+	require.True(t, ok)
+	assert.Equal(t, models.AccountTypeSavings, savings.AccountType)
+	assert.Equal(t, 8000, savings.Balance)
+
+	checking, ok := repo.GetAccount(ctx, checkingID)
+	require.True(t, ok)
+	assert.Equal(t, models.AccountTypeChecking, checking.AccountType)
+	assert.Equal(t, 7000, checking.Balance)
+
+	assert.Len(t, eventPublisher.GetWithdrawalCompletedEvents(), 5)
+
+	failed := eventPublisher.GetTransactionFailedEvents()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "withdrawal", failed[0].TransactionType)
+	assert.Equal(t, savingsID, failed[0].AccountID)
+	assert.Equal(t, messaging.FailureReasonWithdrawalLimitExceeded, failed[0].Reason)
+
+	// The rejected withdrawal is not logged, so the count stays at the limit
+	_, err = repo.AtomicWithdraw(ctx, savingsID, 500)
+	assert.ErrorIs(t, err, postgres.ErrWithdrawalLimitExceeded)
+}
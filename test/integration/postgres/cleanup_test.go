@@ -0,0 +1,63 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCleanupProcessedOperations verifies only idempotency records older than the
+// retention window are removed
+func TestCleanupProcessedOperations(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+
+	for _, key := range []string{"stale-1", "stale-2", "fresh-1"} {
+		_, err := repo.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, key)
+		require.NoError(t, err)
+	}
+
+	// Backdate the stale records directly
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, postgres.NewConfigFromEnv().ConnectionString())
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, `
+		UPDATE processed_operations
+		SET processed_at = NOW() - INTERVAL '8 days'
+		WHERE idempotency_key IN ('stale-1', 'stale-2')
+	`)
+	require.NoError(t, err)
+
+	removed, err := repo.CleanupProcessedOperations(context.Background(), 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+
+	var remaining []string
+	rows, err := conn.Query(ctx, "SELECT idempotency_key FROM processed_operations ORDER BY idempotency_key")
+	require.NoError(t, err)
+	for rows.Next() {
+		var key string
+		require.NoError(t, rows.Scan(&key))
+		remaining = append(remaining, key)
+	}
+	rows.Close()
+	assert.Equal(t, []string{"fresh-1"}, remaining)
+
+	// Fresh record still deduplicates
+	_, err = repo.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, "fresh-1")
+	assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+
+	// Nothing left to remove
+	removed, err = repo.CleanupProcessedOperations(context.Background(), 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
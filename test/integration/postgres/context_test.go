@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockAccount holds a row lock on the account from a separate connection until release is called,
+// so repository calls that need the row block mid-query
+func lockAccount(t *testing.T, cfg *postgres.Config, accountID int) (release func()) {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, cfg.ConnectionString())
+	require.NoError(t, err)
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+
+	_, err = tx.Exec(ctx, "SELECT id FROM accounts WHERE id = $1 FOR UPDATE", accountID)
+	require.NoError(t, err)
+
+	return func() {
+		_ = tx.Rollback(ctx)
+		_ = conn.Close(ctx)
+	}
+}
+
+func TestCancelledContextAbortsQuery(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+	_, err = repo.AtomicDepositWithIdempotency(context.Background(), accountID, 1000, "seed")
+	require.NoError(t, err)
+
+	release := lockAccount(t, cfg, accountID)
+	defer release()
+
+	// Cancel while the withdrawal is waiting for the row lock
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = repo.AtomicWithdraw(ctx, accountID, 500)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second, "Cancellation should abort the query promptly")
+
+	release()
+
+	account, ok := repo.GetAccount(context.Background(), accountID)
+	require.True(t, ok)
+	assert.Equal(t, 1000, account.Balance, "Cancelled withdrawal must not be applied")
+}
+
+func TestStatementTimeoutAbortsQuery(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+	cfg.StatementTimeout = "200ms"
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	accountID := repo.CreateAccount(context.Background(), "Bob")
+
+	release := lockAccount(t, cfg, accountID)
+	defer release()
+
+	// No deadline from the caller - the repository's statement timeout applies
+	_, err = repo.AtomicDepositWithIdempotency(context.Background(), accountID, 500, "timeout")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, postgres.ErrAccountNotFound)
+}
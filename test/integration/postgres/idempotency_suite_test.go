@@ -0,0 +1,19 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+)
+
+// TestPostgresRepository_Idempotency runs the suite shared with the in-memory repository
+func TestPostgresRepository_Idempotency(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	testenv.RunIdempotencySuite(t, func(t *testing.T) database.Repository {
+		repo.Reset(context.Background())
+		return repo
+	})
+}
@@ -0,0 +1,93 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyInterest verifies interest is floored to whole cents and that accounts
+// earning nothing (zero balance or sub-cent interest) get no transaction row
+func TestApplyInterest(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	deposit := func(owner string, amount int) int {
+		id := repo.CreateAccount(context.Background(), owner)
+		if amount > 0 {
+			_, err := repo.AtomicDepositWithIdempotency(context.Background(), id, amount, uuid.New().String())
+			require.NoError(t, err)
+		}
+		return id
+	}
+
+	even := deposit("Even", 10000)   // 10000 * 0.015 = 150
+	floored := deposit("Floor", 999) // 999 * 0.015 = 14.985 -> 14
+	tiny := deposit("Tiny", 50)      // 50 * 0.015 = 0.75 -> 0, skipped
+	empty := deposit("Empty", 0)
+
+	credits, err := repo.ApplyInterestWithCredits(context.Background(), 0.015)
+	require.NoError(t, err)
+	require.Len(t, credits, 2)
+	assert.Equal(t, even, credits[0].AccountID)
+	assert.Equal(t, 150, credits[0].Amount)
+	assert.Equal(t, 10150, credits[0].BalanceAfter)
+	assert.Equal(t, floored, credits[1].AccountID)
+	assert.Equal(t, 14, credits[1].Amount)
+	assert.Equal(t, 1013, credits[1].BalanceAfter)
+
+	for id, expected := range map[int]int{even: 10150, floored: 1013, tiny: 50, empty: 0} {
+		acc, ok := repo.GetAccount(context.Background(), id)
+		require.True(t, ok)
+		assert.Equal(t, expected, acc.Balance, "account %d", id)
+	}
+
+	history, err := repo.GetTransactionHistory(context.Background(), floored, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "interest", history[0]["type"])
+	assert.Equal(t, 14, history[0]["amount"])
+	assert.Equal(t, 1013, history[0]["balance_after"])
+
+	history, err = repo.GetTransactionHistory(context.Background(), tiny, 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 1, "Only the deposit row, no interest row")
+
+	history, err = repo.GetTransactionHistory(context.Background(), empty, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	count, err := repo.ApplyInterest(context.Background(), 0.015)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+// TestApplyInterestSkipsFrozenAccounts verifies a frozen account earns no interest
+func TestApplyInterestSkipsFrozenAccounts(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	id := repo.CreateAccount(context.Background(), "Frozen")
+	_, err := repo.AtomicDepositWithIdempotency(context.Background(), id, 10000, uuid.New().String())
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFrozen(context.Background(), id, true))
+
+	credits, err := repo.ApplyInterestWithCredits(context.Background(), 0.015)
+	require.NoError(t, err)
+	assert.Empty(t, credits)
+
+	acc, ok := repo.GetAccount(context.Background(), id)
+	require.True(t, ok)
+	assert.Equal(t, 10000, acc.Balance)
+}
+
+func TestApplyInterestRejectsNonPositiveRate(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	_, err := repo.ApplyInterest(context.Background(), 0)
+	assert.Error(t, err)
+}
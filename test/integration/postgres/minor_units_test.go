@@ -0,0 +1,148 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedBalance reads the DECIMAL balance column as text, bypassing the repository conversion
+func storedBalance(t *testing.T, accountID int) string {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, postgres.NewConfigFromEnv().ConnectionString())
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	var balance string
+	require.NoError(t, conn.QueryRow(ctx, "SELECT balance::text FROM accounts WHERE id = $1", accountID).Scan(&balance))
+	return balance
+}
+
+// TestMinorUnits_ZeroDecimalCurrency stores JPY amounts as whole yen: 1500 minor units is
+// 1500 yen, not 15.00
+func TestMinorUnits_ZeroDecimalCurrency(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+	ctx := context.Background()
+
+	accountID := repo.CreateAccountWithCurrency(ctx, "Akira", "JPY")
+
+	_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1500, uuid.New().String())
+	require.NoError(t, err)
+	account, err := repo.AtomicWithdraw(ctx, accountID, 499)
+	require.NoError(t, err)
+	assert.Equal(t, 1001, account.Balance)
+
+	assert.Equal(t, "1001.000", storedBalance(t, accountID))
+
+	stored, found := repo.GetAccount(ctx, accountID)
+	require.True(t, found)
+	assert.Equal(t, "JPY", stored.Currency)
+	assert.Equal(t, 1001, stored.Balance)
+
+	history, err := repo.GetTransactionHistory(ctx, accountID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, -499, history[0]["amount"])
+	assert.Equal(t, 1001, history[0]["balance_after"])
+	assert.Equal(t, 1500, history[1]["amount"])
+}
+
+// TestMinorUnits_ThreeDecimalCurrency stores KWD amounts with three decimal places: 1234 minor
+// units is 1.234 dinars, and single fils survive the round trip
+func TestMinorUnits_ThreeDecimalCurrency(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+	ctx := context.Background()
+
+	fromID := repo.CreateAccountWithCurrency(ctx, "Fatima", "KWD")
+	toID := repo.CreateAccountWithCurrency(ctx, "Omar", "KWD")
+
+	key := uuid.New().String()
+	_, err := repo.AtomicDepositWithIdempotency(ctx, fromID, 1234, key)
+	require.NoError(t, err)
+	assert.Equal(t, "1.234", storedBalance(t, fromID))
+
+	// The replayed deposit reports the balance recorded the first time
+	replayed, err := repo.AtomicDepositWithIdempotency(ctx, fromID, 1234, key)
+	assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+	assert.Equal(t, 1234, replayed.Balance)
+
+	from, to, err := repo.AtomicTransfer(ctx, fromID, toID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1233, from.Balance)
+	assert.Equal(t, 1, to.Balance)
+	assert.Equal(t, "0.001", storedBalance(t, toID))
+
+	holdID, err := repo.PlaceHold(ctx, fromID, 233)
+	require.NoError(t, err)
+	hold, err := repo.CaptureHold(ctx, holdID)
+	require.NoError(t, err)
+	assert.Equal(t, 233, hold.Amount)
+
+	stored, found := repo.GetAccount(ctx, fromID)
+	require.True(t, found)
+	assert.Equal(t, 1000, stored.Balance)
+	assert.Equal(t, "1.000", storedBalance(t, fromID))
+
+	records, err := repo.ListTransactions(ctx, 0, time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, 1234, records[0].Amount)
+	assert.Equal(t, -1, records[1].Amount)
+	assert.Equal(t, 1, records[2].Amount)
+	assert.Equal(t, -233, records[3].Amount)
+	assert.Equal(t, 1000, records[3].BalanceAfter)
+}
+
+// TestMinorUnits_OverdraftZeroDecimalCurrency lets a JPY account go as far below zero as its
+// overdraft limit in yen: a limit of 10000 is ¥10,000, not ¥100
+func TestMinorUnits_OverdraftZeroDecimalCurrency(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+	ctx := context.Background()
+
+	accountID := repo.CreateAccountWithCurrency(ctx, "Akira", "JPY")
+	require.NoError(t, repo.SetOverdraftLimit(ctx, accountID, 10000))
+
+	_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, uuid.New().String())
+	require.NoError(t, err)
+	account, err := repo.AtomicWithdraw(ctx, accountID, 11000)
+	require.NoError(t, err)
+	assert.Equal(t, -10000, account.Balance)
+	assert.Equal(t, "-10000.000", storedBalance(t, accountID))
+
+	_, err = repo.AtomicWithdraw(ctx, accountID, 1)
+	assert.ErrorIs(t, err, postgres.ErrInsufficientFunds)
+}
+
+// TestMinorUnits_OverdraftThreeDecimalCurrency reads a KWD overdraft limit in fils, and the
+// balance check still backstops it when the repository is bypassed
+func TestMinorUnits_OverdraftThreeDecimalCurrency(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+	ctx := context.Background()
+
+	accountID := repo.CreateAccountWithCurrency(ctx, "Fatima", "KWD")
+	require.NoError(t, repo.SetOverdraftLimit(ctx, accountID, 1500))
+
+	account, err := repo.AtomicWithdraw(ctx, accountID, 1500)
+	require.NoError(t, err)
+	assert.Equal(t, -1500, account.Balance)
+	assert.Equal(t, "-1.500", storedBalance(t, accountID))
+
+	_, err = repo.AtomicWithdraw(ctx, accountID, 1)
+	assert.ErrorIs(t, err, postgres.ErrInsufficientFunds)
+
+	conn, err := pgx.Connect(ctx, postgres.NewConfigFromEnv().ConnectionString())
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, "UPDATE accounts SET balance = -1.501 WHERE id = $1", accountID)
+	assert.ErrorContains(t, err, "balance_within_overdraft")
+}
@@ -0,0 +1,104 @@
+package postgres_test
+
+import (
+	"bank-api/internal/domain/models"
+	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateAccountVersionedConflict verifies a stale write is rejected instead of
+// silently overwriting a concurrent update
+func TestUpdateAccountVersionedConflict(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+
+	// Two writers read the same version
+	first, found := repo.GetAccount(context.Background(), accountID)
+	require.True(t, found)
+	second, found := repo.GetAccount(context.Background(), accountID)
+	require.True(t, found)
+	require.Equal(t, first.Version, second.Version)
+
+	first.Balance += 1000
+	require.NoError(t, repo.UpdateAccountVersioned(context.Background(), first, first.Version))
+	assert.Equal(t, second.Version+1, first.Version)
+
+	// Second writer is now stale
+	second.Balance += 5000
+	err := repo.UpdateAccountVersioned(context.Background(), second, second.Version)
+	require.ErrorIs(t, err, postgres.ErrVersionConflict)
+
+	// First write survives; version visible through GetAccount
+	current, found := repo.GetAccount(context.Background(), accountID)
+	require.True(t, found)
+	assert.Equal(t, 1000, current.Balance)
+	assert.Equal(t, first.Version, current.Version)
+}
+
+// TestUpdateAccountVersionedNotFound verifies a missing account isn't reported as a conflict
+func TestUpdateAccountVersionedNotFound(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	err := repo.UpdateAccountVersioned(context.Background(), &models.Account{Id: 99999, Balance: 100}, 1)
+	assert.ErrorIs(t, err, postgres.ErrAccountNotFound)
+}
+
+// TestConcurrentVersionedUpdates is the optimistic-locking counterpart of
+// TestConcurrentAccountUpdates: writers retry on conflict, so no update is lost
+func TestConcurrentVersionedUpdates(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Charlie")
+
+	const numUpdates = 50
+	const amountPerUpdate = 1000
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	conflicts := 0
+
+	for i := 0; i < numUpdates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				account, found := repo.GetAccount(context.Background(), accountID)
+				if !found {
+					t.Error("Account not found")
+					return
+				}
+
+				account.Balance += amountPerUpdate
+				err := repo.UpdateAccountVersioned(context.Background(), account, account.Version)
+				if err == nil {
+					return
+				}
+				if !errors.Is(err, postgres.ErrVersionConflict) {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+
+				mu.Lock()
+				conflicts++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	finalAccount, found := repo.GetAccount(context.Background(), accountID)
+	require.True(t, found)
+	assert.Equal(t, numUpdates*amountPerUpdate, finalAccount.Balance, "No update should be lost")
+	t.Logf("Resolved %d version conflicts across %d concurrent updates", conflicts, numUpdates)
+}
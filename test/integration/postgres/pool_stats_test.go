@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/internal/pkg/telemetry"
+	"bank-api/test/integration/testenv"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPoolStatsShowAcquireWaits runs more concurrent operations than the pool has connections
+// and checks the saturation shows up in the stats and the exported gauges
+func TestPoolStatsShowAcquireWaits(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+	cfg.MaxOpenConns = 2
+	cfg.MaxIdleConns = 1
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	stats := repo.PoolStats()
+	require.Contains(t, stats, "primary")
+	assert.NotContains(t, stats, "replica", "No replica is configured")
+	assert.Equal(t, int32(2), stats["primary"].MaxConns())
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+
+	var wg sync.WaitGroup
+	n := 50
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			_, err := repo.AtomicDepositWithIdempotency(context.Background(), accountID, 100, uuid.New().String())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	stat := repo.PoolStats()["primary"]
+	assert.Greater(t, stat.EmptyAcquireCount(), int64(0), "Callers should have waited for a connection")
+	assert.LessOrEqual(t, stat.TotalConns(), int32(2))
+
+	metrics.UpdateDBPoolStats("primary", stat)
+	assert.Equal(t, float64(stat.EmptyAcquireCount()),
+		testutil.ToFloat64(metrics.DBPoolMetrics.WithLabelValues("primary", "empty_acquire_count")))
+	assert.Greater(t, testutil.ToFloat64(metrics.DBPoolMetrics.WithLabelValues("primary", "empty_acquire_wait_seconds")), 0.0)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.DBPoolMetrics.WithLabelValues("primary", "max_conns")))
+}
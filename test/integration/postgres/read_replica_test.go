@@ -0,0 +1,72 @@
+package postgres_test
+
+import (
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadReplicaRouting uses two independent databases as primary and "replica" so it can
+// tell which pool served each query: writes land on the primary, reads come from the replica
+func TestReadReplicaRouting(t *testing.T) {
+	primaryCfg := testenv.SetupMigratedPostgresContainer(t)
+	replicaCfg := testenv.SetupMigratedPostgresContainer(t)
+
+	primaryCfg.ReadReplicaConnectionString = replicaCfg.ConnectionString()
+
+	repo, err := postgres.NewPostgresRepository(primaryCfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// Direct handle on the replica database to seed data the primary doesn't have
+	replica, err := postgres.NewPostgresRepository(replicaCfg)
+	require.NoError(t, err)
+	defer replica.Close()
+
+	// Write goes to the primary, so the replica-backed read can't see it
+	primaryID := repo.CreateAccount(context.Background(), "Alice")
+	_, found := repo.GetAccount(context.Background(), primaryID)
+	assert.False(t, found, "GetAccount should read from the replica, which hasn't seen the write")
+
+	// Data present only on the replica is visible through the repository
+	replicaID := replica.CreateAccount(context.Background(), "Bob")
+	require.Equal(t, primaryID, replicaID, "Both fresh databases should hand out the same first ID")
+
+	_, err = replica.AtomicDepositWithIdempotency(context.Background(), replicaID, 1500, uuid.New().String())
+	require.NoError(t, err)
+
+	acc, found := repo.GetAccount(context.Background(), replicaID)
+	require.True(t, found)
+	assert.Equal(t, "Bob", acc.Owner)
+	assert.Equal(t, 1500, acc.Balance)
+
+	history, err := repo.GetTransactionHistory(context.Background(), replicaID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 1500, history[0]["balance_after"])
+
+	// Atomic writes still run on the primary against Alice's row
+	updated, err := repo.AtomicDepositWithIdempotency(context.Background(), primaryID, 700, uuid.New().String())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", updated.Owner)
+	assert.Equal(t, 700, updated.Balance)
+}
+
+// TestReadReplicaFallback verifies reads use the primary when no replica is configured
+func TestReadReplicaFallback(t *testing.T) {
+	cfg := testenv.SetupMigratedPostgresContainer(t)
+
+	repo, err := postgres.NewPostgresRepository(cfg)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	id := repo.CreateAccount(context.Background(), "Carol")
+	acc, found := repo.GetAccount(context.Background(), id)
+	require.True(t, found)
+	assert.Equal(t, "Carol", acc.Owner)
+}
@@ -0,0 +1,133 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionLogDeposit verifies a deposit writes a single positive row
+func TestTransactionLogDeposit(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+
+	_, err := repo.AtomicDepositWithIdempotency(context.Background(), accountID, 2500, uuid.New().String())
+	require.NoError(t, err)
+
+	history, err := repo.GetTransactionHistory(context.Background(), accountID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "deposit", history[0]["type"])
+	assert.Equal(t, 2500, history[0]["amount"])
+	assert.Equal(t, 2500, history[0]["balance_after"])
+}
+
+// TestTransactionLogDuplicateDeposit verifies a duplicate idempotency key doesn't add a row
+func TestTransactionLogDuplicateDeposit(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Alice")
+	key := uuid.New().String()
+
+	_, err := repo.AtomicDepositWithIdempotency(context.Background(), accountID, 2500, key)
+	require.NoError(t, err)
+	_, err = repo.AtomicDepositWithIdempotency(context.Background(), accountID, 2500, key)
+	require.Error(t, err)
+
+	history, err := repo.GetTransactionHistory(context.Background(), accountID, 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+// TestTransactionLogWithdraw verifies both withdrawal paths write negative rows
+func TestTransactionLogWithdraw(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Bob")
+
+	_, err := repo.AtomicDepositWithIdempotency(context.Background(), accountID, 10000, uuid.New().String())
+	require.NoError(t, err)
+
+	_, err = repo.AtomicWithdraw(context.Background(), accountID, 3000)
+	require.NoError(t, err)
+
+	_, err = repo.AtomicWithdrawWithIdempotency(context.Background(), accountID, 2000, uuid.New().String())
+	require.NoError(t, err)
+
+	history, err := repo.GetTransactionHistory(context.Background(), accountID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	assert.Equal(t, "withdraw", history[0]["type"])
+	assert.Equal(t, -2000, history[0]["amount"])
+	assert.Equal(t, 5000, history[0]["balance_after"])
+
+	assert.Equal(t, "withdraw", history[1]["type"])
+	assert.Equal(t, -3000, history[1]["amount"])
+	assert.Equal(t, 7000, history[1]["balance_after"])
+
+	// Signed amounts sum to the current balance
+	sum := 0
+	for _, tx := range history {
+		sum += tx["amount"].(int)
+	}
+	assert.Equal(t, 5000, sum)
+}
+
+// TestTransactionLogFailedWithdraw verifies a rejected withdrawal leaves no row behind
+func TestTransactionLogFailedWithdraw(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	accountID := repo.CreateAccount(context.Background(), "Bob")
+
+	_, err := repo.AtomicWithdraw(context.Background(), accountID, 1000)
+	require.Error(t, err)
+
+	history, err := repo.GetTransactionHistory(context.Background(), accountID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+// TestTransactionLogTransfer verifies a transfer writes a debit and a credit sharing a reference_id
+func TestTransactionLogTransfer(t *testing.T) {
+	repo := getTestRepository(t)
+	defer repo.Reset(context.Background())
+
+	fromID := repo.CreateAccount(context.Background(), "Carol")
+	toID := repo.CreateAccount(context.Background(), "Dave")
+
+	_, err := repo.AtomicDepositWithIdempotency(context.Background(), fromID, 10000, uuid.New().String())
+	require.NoError(t, err)
+
+	_, _, err = repo.AtomicTransfer(context.Background(), fromID, toID, 4000)
+	require.NoError(t, err)
+
+	fromHistory, err := repo.GetTransactionHistory(context.Background(), fromID, 10)
+	require.NoError(t, err)
+	require.Len(t, fromHistory, 2)
+
+	debit := fromHistory[0]
+	assert.Equal(t, "transfer_out", debit["type"])
+	assert.Equal(t, -4000, debit["amount"])
+	assert.Equal(t, 6000, debit["balance_after"])
+
+	toHistory, err := repo.GetTransactionHistory(context.Background(), toID, 10)
+	require.NoError(t, err)
+	require.Len(t, toHistory, 1)
+
+	credit := toHistory[0]
+	assert.Equal(t, "transfer_in", credit["type"])
+	assert.Equal(t, 4000, credit["amount"])
+	assert.Equal(t, 4000, credit["balance_after"])
+
+	require.NotEmpty(t, debit["reference_id"])
+	assert.Equal(t, debit["reference_id"], credit["reference_id"], "Both legs should share the reference_id")
+}
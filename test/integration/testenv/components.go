@@ -5,6 +5,7 @@ import (
 	"bank-api/internal/infrastructure/database"
 	"bank-api/internal/infrastructure/messaging"
 	"bank-api/internal/pkg/logging"
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -73,7 +74,7 @@ func NewTestContainer() *TestContainer {
 // Reset clears all data in the test container
 func (tc *TestContainer) Reset() {
 	if tc.Database != nil {
-		tc.Database.Reset()
+		tc.Database.Reset(context.Background())
 	}
 	if tc.EventPublisher != nil {
 		tc.EventPublisher.Reset()
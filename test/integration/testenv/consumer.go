@@ -0,0 +1,157 @@
+package testenv
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeConsumerGroupSession is an in-memory sarama.ConsumerGroupSession
+// It records marked messages and commits so tests can verify offset handling
+type FakeConsumerGroupSession struct {
+	ctx          context.Context
+	mu           sync.Mutex
+	marked       []*sarama.ConsumerMessage
+	commitPoints []int
+}
+
+// NewFakeConsumerGroupSession creates a fake session bound to the given context
+func NewFakeConsumerGroupSession(ctx context.Context) *FakeConsumerGroupSession {
+	return &FakeConsumerGroupSession{ctx: ctx}
+}
+
+func (s *FakeConsumerGroupSession) Claims() map[string][]int32 { return nil }
+func (s *FakeConsumerGroupSession) MemberID() string           { return "test-member" }
+func (s *FakeConsumerGroupSession) GenerationID() int32        { return 1 }
+func (s *FakeConsumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (s *FakeConsumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (s *FakeConsumerGroupSession) Context() context.Context { return s.ctx }
+
+// MarkMessage records the message as processed
+func (s *FakeConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg)
+}
+
+// Commit records a commit of everything marked so far
+func (s *FakeConsumerGroupSession) Commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commitPoints = append(s.commitPoints, len(s.marked))
+}
+
+// MarkedMessages returns the messages marked as processed
+func (s *FakeConsumerGroupSession) MarkedMessages() []*sarama.ConsumerMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	marked := make([]*sarama.ConsumerMessage, len(s.marked))
+	copy(marked, s.marked)
+	return marked
+}
+
+// Commits returns how many times Commit was called
+func (s *FakeConsumerGroupSession) Commits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.commitPoints)
+}
+
+// CommitPoints returns, for each commit, how many messages had been marked at that point
+func (s *FakeConsumerGroupSession) CommitPoints() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	points := make([]int, len(s.commitPoints))
+	copy(points, s.commitPoints)
+	return points
+}
+
+// CommittedMessages returns how many marked messages are covered by the last commit.
+// Anything marked after it would be redelivered if the consumer crashed now.
+func (s *FakeConsumerGroupSession) CommittedMessages() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.commitPoints) == 0 {
+		return 0
+	}
+	return s.commitPoints[len(s.commitPoints)-1]
+}
+
+// FakeConsumerGroupClaim is an in-memory sarama.ConsumerGroupClaim for a single partition
+type FakeConsumerGroupClaim struct {
+	topic    string
+	messages chan *sarama.ConsumerMessage
+	next     int64
+}
+
+// NewFakeConsumerGroupClaim creates a claim buffering up to capacity messages
+func NewFakeConsumerGroupClaim(topic string, capacity int) *FakeConsumerGroupClaim {
+	return &FakeConsumerGroupClaim{
+		topic:    topic,
+		messages: make(chan *sarama.ConsumerMessage, capacity),
+	}
+}
+
+// Send delivers a payload on the claim at the next offset
+func (c *FakeConsumerGroupClaim) Send(payload []byte) {
+	c.messages <- &sarama.ConsumerMessage{
+		Topic:  c.topic,
+		Offset: c.next,
+		Value:  payload,
+	}
+	c.next++
+}
+
+// Close ends the claim, as when the partition is revoked
+func (c *FakeConsumerGroupClaim) Close() {
+	close(c.messages)
+}
+
+func (c *FakeConsumerGroupClaim) Topic() string                            { return c.topic }
+func (c *FakeConsumerGroupClaim) Partition() int32                         { return 0 }
+func (c *FakeConsumerGroupClaim) InitialOffset() int64                     { return 0 }
+func (c *FakeConsumerGroupClaim) HighWaterMarkOffset() int64               { return int64(cap(c.messages)) }
+func (c *FakeConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// ConsumeMessages feeds raw payloads through handler.ConsumeClaim as a single partition
+// claim and returns the session so tests can inspect marked messages and commits
+func ConsumeMessages(t *testing.T, handler sarama.ConsumerGroupHandler, topic string, payloads ...[]byte) *FakeConsumerGroupSession {
+	claim := NewFakeConsumerGroupClaim(topic, len(payloads))
+	for _, payload := range payloads {
+		claim.Send(payload)
+	}
+	claim.Close()
+
+	session := NewFakeConsumerGroupSession(context.Background())
+	require.NoError(t, handler.Setup(session))
+	require.NoError(t, handler.ConsumeClaim(session, claim))
+	require.NoError(t, handler.Cleanup(session))
+
+	return session
+}
+
+// ConsumeEvents marshals events to JSON and feeds them through the handler
+func ConsumeEvents[T any](t *testing.T, handler sarama.ConsumerGroupHandler, topic string, events ...T) *FakeConsumerGroupSession {
+	payloads := make([][]byte, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		require.NoError(t, err)
+		payloads = append(payloads, payload)
+	}
+	return ConsumeMessages(t, handler, topic, payloads...)
+}
+
+// ProcessWithdrawalRequests drives all captured WithdrawalRequestedEvents through the
+// withdrawal consumer handler, simulating the consumer reading them from Kafka
+func (tc *TestContainer) ProcessWithdrawalRequests(t *testing.T) *FakeConsumerGroupSession {
+	handler := messaging.NewWithdrawalConsumerHandler(kafka.NewConfigFromEnv(), tc.EventPublisher, tc.Database)
+	return ConsumeEvents(t, handler, kafka.TopicWithdrawalRequests, tc.EventPublisher.GetWithdrawalRequestedEvents()...)
+}
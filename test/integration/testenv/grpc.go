@@ -0,0 +1,44 @@
+package testenv
+
+import (
+	"bank-api/internal/api/grpcapi"
+	"bank-api/internal/api/grpcapi/bankingpb"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// NewGRPCClient serves the banking gRPC service over an in-memory listener backed by
+// the container's database and event capture, and returns a connected client
+func (tc *TestContainer) NewGRPCClient(t *testing.T) bankingpb.BankingServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpcapi.NewServer(&handlerContainer{
+		db:        tc.Database,
+		publisher: tc.EventPublisher,
+	})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return bankingpb.NewBankingServiceClient(conn)
+}
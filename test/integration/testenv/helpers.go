@@ -2,21 +2,32 @@ package testenv
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
 
 	"bank-api/internal/domain/account"
 	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
 func CreateAccount(t *testing.T, r *gin.Engine, owner string) int {
+	return CreateAccountWithCurrency(t, r, owner, "")
+}
+
+// CreateAccountWithCurrency opens an account in the given currency; an empty currency uses the default
+func CreateAccountWithCurrency(t *testing.T, r *gin.Engine, owner string, currency string) int {
 	body := map[string]interface{}{"owner": owner}
+	if currency != "" {
+		body["currency"] = currency
+	}
 	jsonBody, _ := json.Marshal(body)
 
 	req := httptest.NewRequest("POST", "/accounts", bytes.NewBuffer(jsonBody))
@@ -73,7 +84,7 @@ func Deposit(t *testing.T, r *gin.Engine, id int, amount int) string {
 	return ""
 }
 
-func Withdraw(t *testing.T, r *gin.Engine, id int, amount int) {
+func Withdraw(t *testing.T, r *gin.Engine, id int, amount int) string {
 	body := map[string]int{"amount": amount}
 	jsonBody, _ := json.Marshal(body)
 
@@ -83,9 +94,18 @@ func Withdraw(t *testing.T, r *gin.Engine, id int, amount int) {
 
 	r.ServeHTTP(resp, req)
 
-	if resp.Code != http.StatusOK {
+	// Expects 202 Accepted for async processing (same as deposits)
+	if resp.Code != http.StatusAccepted {
 		t.Fatalf("erro no saque: %d", resp.Code)
 	}
+
+	// Return operation ID for tracking
+	var result map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	if opID, ok := result["operation_id"].(string); ok {
+		return opID
+	}
+	return ""
 }
 
 // AssertHasError checks if the response has an error message in either the new format (message) or old format (error)
@@ -102,7 +122,7 @@ func AssertHasError(t *testing.T, result map[string]interface{}) {
 // SetBalance directly sets an account balance for test setup purposes
 // This bypasses the async deposit mechanism and is only for test fixtures
 func SetBalance(t *testing.T, accountID int, amount int) {
-	acc, ok := database.Repo.GetAccount(accountID)
+	acc, ok := database.Repo.GetAccount(context.Background(), accountID)
 	if !ok {
 		t.Fatalf("account not found: %d", accountID)
 	}
@@ -112,5 +132,68 @@ func SetBalance(t *testing.T, accountID int, amount int) {
 		t.Fatalf("failed to add amount: %v", err)
 	}
 
-	database.Repo.UpdateAccount(acc)
+	database.Repo.UpdateAccount(context.Background(), acc)
+}
+
+// GetTransactionHistory fetches the transaction history for an account (most recent first)
+func GetTransactionHistory(t *testing.T, r *gin.Engine, id int, limit int) []map[string]interface{} {
+	url := "/accounts/" + strconv.Itoa(id) + "/transactions"
+	if limit > 0 {
+		url += "?limit=" + strconv.Itoa(limit)
+	}
+
+	req := httptest.NewRequest("GET", url, nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("erro ao consultar extrato: %d", resp.Code)
+	}
+
+	var result struct {
+		Transactions []map[string]interface{} `json:"transactions"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode transaction history: %v", err)
+	}
+	return result.Transactions
+}
+
+// GetTransactionHistoryPage fetches one page of transaction history with the given query params
+// (limit, type, before, before_id). The returned cursor is nil on the last page
+func GetTransactionHistoryPage(t *testing.T, r *gin.Engine, id int, query url.Values) ([]map[string]interface{}, *postgres.HistoryCursor) {
+	req := httptest.NewRequest("GET", "/accounts/"+strconv.Itoa(id)+"/transactions?"+query.Encode(), nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("erro ao consultar extrato: %d", resp.Code)
+	}
+
+	var result struct {
+		Transactions []map[string]interface{} `json:"transactions"`
+		NextCursor   *postgres.HistoryCursor  `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode transaction history: %v", err)
+	}
+	return result.Transactions, result.NextCursor
+}
+
+// AssertErrorCode checks the response is a structured API error with the given status and code
+func AssertErrorCode(t *testing.T, resp *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	assert.Equal(t, status, resp.Code)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Errorf("Response is not JSON: %s", resp.Body.String())
+		return
+	}
+	assert.Equal(t, code, result["code"])
+	assert.NotEmpty(t, result["message"])
+	assert.Equal(t, float64(status), result["status"])
 }
@@ -0,0 +1,162 @@
+package testenv
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/postgres"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunIdempotencySuite checks the idempotent operations of a repository implementation.
+// newRepo must return an empty repository; it is called once per subtest.
+func RunIdempotencySuite(t *testing.T, newRepo func(t *testing.T) database.Repository) {
+	ctx := context.Background()
+
+	balanceOf := func(t *testing.T, repo database.Repository, id int) int {
+		account, found := repo.GetAccount(ctx, id)
+		require.True(t, found)
+		return account.Balance
+	}
+
+	t.Run("DepositReplayReturnsRecordedBalance", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+
+		account, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1000, account.Balance)
+
+		account, err = repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+		assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+		require.NotNil(t, account)
+		assert.Equal(t, 1000, account.Balance, "replay should report the balance recorded by the original deposit")
+
+		assert.Equal(t, 1000, balanceOf(t, repo, accountID))
+
+		history, err := repo.GetTransactionHistory(ctx, accountID, 10)
+		require.NoError(t, err)
+		assert.Len(t, history, 1, "replay must not add a transaction")
+	})
+
+	t.Run("DistinctKeysAreApplied", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+
+		for _, key := range []string{"deposit-1", "deposit-2", "deposit-3"} {
+			_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 500, key)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 1500, balanceOf(t, repo, accountID))
+	})
+
+	t.Run("FailedDepositDoesNotConsumeKey", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+		require.NoError(t, repo.SetFrozen(ctx, accountID, true))
+
+		_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+		assert.ErrorIs(t, err, postgres.ErrAccountFrozen)
+
+		require.NoError(t, repo.SetFrozen(ctx, accountID, false))
+		account, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+		require.NoError(t, err, "the key should be usable once the deposit can succeed")
+		assert.Equal(t, 1000, account.Balance)
+	})
+
+	t.Run("DepositToMissingAccount", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.AtomicDepositWithIdempotency(ctx, 999999, 1000, "deposit-1")
+		assert.ErrorIs(t, err, postgres.ErrAccountNotFound)
+	})
+
+	t.Run("WithdrawReplay", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+		_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+		require.NoError(t, err)
+
+		// Insufficient funds leave the key unused
+		_, err = repo.AtomicWithdrawWithIdempotency(ctx, accountID, 5000, "withdraw-1")
+		assert.ErrorIs(t, err, postgres.ErrInsufficientFunds)
+
+		account, err := repo.AtomicWithdrawWithIdempotency(ctx, accountID, 300, "withdraw-1")
+		require.NoError(t, err)
+		assert.Equal(t, 700, account.Balance)
+
+		account, err = repo.AtomicWithdrawWithIdempotency(ctx, accountID, 300, "withdraw-1")
+		assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+		require.NotNil(t, account)
+		assert.Equal(t, 700, account.Balance)
+
+		assert.Equal(t, 700, balanceOf(t, repo, accountID))
+	})
+
+	t.Run("TransferReplay", func(t *testing.T) {
+		repo := newRepo(t)
+		fromID := repo.CreateAccount(ctx, "Alice")
+		toID := repo.CreateAccount(ctx, "Bob")
+		_, err := repo.AtomicDepositWithIdempotency(ctx, fromID, 1000, "deposit-1")
+		require.NoError(t, err)
+
+		from, to, err := repo.AtomicTransferWithIdempotency(ctx, fromID, toID, 400, "transfer-1")
+		require.NoError(t, err)
+		assert.Equal(t, 600, from.Balance)
+		assert.Equal(t, 400, to.Balance)
+
+		from, to, err = repo.AtomicTransferWithIdempotency(ctx, fromID, toID, 400, "transfer-1")
+		assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+		require.NotNil(t, from)
+		require.NotNil(t, to)
+		assert.Equal(t, 600, from.Balance)
+		assert.Equal(t, toID, to.Id)
+
+		assert.Equal(t, 600, balanceOf(t, repo, fromID))
+		assert.Equal(t, 400, balanceOf(t, repo, toID))
+	})
+
+	t.Run("KeysAreSharedAcrossOperationTypes", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+		_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "shared-key")
+		require.NoError(t, err)
+
+		_, err = repo.AtomicWithdrawWithIdempotency(ctx, accountID, 100, "shared-key")
+		assert.ErrorIs(t, err, postgres.ErrDuplicateOperation)
+		assert.Equal(t, 1000, balanceOf(t, repo, accountID))
+	})
+
+	t.Run("ConcurrentDuplicatesApplyOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		accountID := repo.CreateAccount(ctx, "Alice")
+
+		const attempts = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "concurrent-1")
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		// Losers may see ErrDuplicateOperation or a conflict error, depending on timing
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded, "exactly one deposit should be applied")
+		assert.Equal(t, 1000, balanceOf(t, repo, accountID))
+	})
+}
@@ -0,0 +1,63 @@
+package testenv
+
+import (
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+// consumerGroups are the groups joined by the deposit and withdrawal consumers
+var consumerGroups = []string{"deposit-processor-group", "withdrawal-processor-group"}
+
+// SetupFakeKafkaBroker starts an in-process sarama mock broker that serves metadata, lets the
+// consumer groups join with no partitions assigned, and accepts produced messages. KAFKA_BROKERS
+// points at it for the rest of the test; idempotence is disabled since the mock can't grant
+// producer IDs.
+func SetupFakeKafkaBroker(t *testing.T) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+
+	metadata := sarama.NewMockMetadataResponse(t).
+		SetBroker(broker.Addr(), broker.BrokerID()).
+		SetController(broker.BrokerID())
+	coordinator := sarama.NewMockFindCoordinatorResponse(t)
+	for _, topic := range kafka.GetAllTopics() {
+		metadata.SetLeader(topic, 0, broker.BrokerID())
+	}
+	for _, group := range consumerGroups {
+		coordinator.SetCoordinator(sarama.CoordinatorGroup, group, broker)
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest":     sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest":        metadata,
+		"FindCoordinatorRequest": coordinator,
+		// Another member leads the group, so the consumers just wait for their (empty) assignment
+		"JoinGroupRequest": sarama.NewMockJoinGroupResponse(t).
+			SetGroupProtocol(sarama.RoundRobinBalanceStrategyName).
+			SetMemberId("test-member").
+			SetLeaderId("other-member"),
+		"SyncGroupRequest": sarama.NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&sarama.ConsumerGroupMemberAssignment{Topics: map[string][]int32{}},
+		),
+		"HeartbeatRequest":  sarama.NewMockHeartbeatResponse(t),
+		"LeaveGroupRequest": sarama.NewMockLeaveGroupResponse(t),
+		"ProduceRequest":    sarama.NewMockProduceResponse(t),
+	})
+
+	t.Setenv("KAFKA_BROKERS", broker.Addr())
+	t.Setenv("KAFKA_ENABLE_IDEMPOTENCE", "false")
+	return broker
+}
+
+// JoinedConsumerGroups returns the consumer groups that have sent a JoinGroup request to broker
+func JoinedConsumerGroups(broker *sarama.MockBroker) map[string]bool {
+	joined := make(map[string]bool)
+	for _, exchange := range broker.History() {
+		if req, ok := exchange.Request.(*sarama.JoinGroupRequest); ok {
+			joined[req.GroupId] = true
+		}
+	}
+	return joined
+}
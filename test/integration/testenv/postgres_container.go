@@ -22,6 +22,25 @@ var (
 	testContainerErr  error
 )
 
+// migrationScripts are applied in order when the test container starts
+var migrationScripts = []string{
+	"../../../internal/infrastructure/database/postgres/migrations/000001_init_schema.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000002_create_processed_operations.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000003_signed_transaction_amounts.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000004_add_account_status.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000005_add_interest_transaction_type.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000006_add_overdraft_limit.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000007_add_account_currency.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000008_add_account_frozen.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000009_create_account_holds.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000010_add_account_type.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000011_add_account_client_request_id.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000012_add_account_deleted_at.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000013_create_operation_status.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000014_add_duplicate_operation_status.up.sql",
+	"../../../internal/infrastructure/database/postgres/migrations/000015_widen_money_scale.up.sql",
+}
+
 // PostgresContainerConfig holds configuration for the test container
 type PostgresContainerConfig struct {
 	Database string
@@ -87,10 +106,7 @@ func SetupPostgresContainerWithEnv(t *testing.T) *postgres.PostgresContainer {
 		postgres.WithDatabase(cfg.Database),
 		postgres.WithUsername(cfg.Username),
 		postgres.WithPassword(cfg.Password),
-		postgres.WithInitScripts(
-			"../../../internal/infrastructure/database/postgres/migrations/000001_init_schema.up.sql",
-			"../../../internal/infrastructure/database/postgres/migrations/000002_create_processed_operations.up.sql",
-		),
+		postgres.WithInitScripts(migrationScripts...),
 		testcontainers.WithWaitStrategy(
 			wait.ForLog("database system is ready to accept connections").
 				WithOccurrence(2).
@@ -141,10 +157,7 @@ func SetupIntegrationTest(t *testing.T) {
 			postgres.WithDatabase(cfg.Database),
 			postgres.WithUsername(cfg.Username),
 			postgres.WithPassword(cfg.Password),
-			postgres.WithInitScripts(
-				"../../../internal/infrastructure/database/postgres/migrations/000001_init_schema.up.sql",
-				"../../../internal/infrastructure/database/postgres/migrations/000002_create_processed_operations.up.sql",
-			),
+			postgres.WithInitScripts(migrationScripts...),
 			testcontainers.WithWaitStrategy(
 				wait.ForLog("database system is ready to accept connections").
 					WithOccurrence(2).
@@ -207,6 +220,54 @@ func SetupIntegrationTest(t *testing.T) {
 
 	// Reset database before each test
 	if database.Repo != nil {
-		database.Repo.Reset()
+		database.Repo.Reset(context.Background())
+	}
+}
+
+// SetupMigratedPostgresContainer starts a dedicated PostgreSQL testcontainer with all migrations
+// applied and returns a repository config pointing at it. Unlike SetupIntegrationTest the
+// container is not shared, so tests can run several independent databases side by side.
+func SetupMigratedPostgresContainer(t *testing.T) *dbpostgres.Config {
+	ctx := context.Background()
+	cfg := DefaultPostgresConfig()
+
+	container, err := postgres.Run(ctx,
+		cfg.Image,
+		postgres.WithDatabase(cfg.Database),
+		postgres.WithUsername(cfg.Username),
+		postgres.WithPassword(cfg.Password),
+		postgres.WithInitScripts(migrationScripts...),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60*time.Second),
+		),
+	)
+	require.NoError(t, err, "Failed to start PostgreSQL testcontainer")
+
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("Failed to terminate PostgreSQL testcontainer: %v", err)
+		}
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+
+	return &dbpostgres.Config{
+		Host:              host,
+		Port:              port.Int(),
+		Database:          cfg.Database,
+		User:              cfg.Username,
+		Password:          cfg.Password,
+		SSLMode:           "disable",
+		MaxOpenConns:      25,
+		MaxIdleConns:      5,
+		ConnMaxLifetime:   "30m",
+		ConnMaxIdleTime:   "5m",
+		HealthCheckPeriod: "1m",
 	}
 }
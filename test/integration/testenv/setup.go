@@ -1,7 +1,6 @@
 package testenv
 
 import (
-	"bank-api/internal/api/middleware"
 	"bank-api/internal/api/routes"
 	"bank-api/internal/config"
 	"bank-api/internal/infrastructure/database"
@@ -24,6 +23,16 @@ func (h *handlerContainer) GetEventPublisher() messaging.EventPublisher {
 	return h.publisher
 }
 
+// testCORSPolicies allows every origin on both route groups
+func testCORSPolicies() routes.CORSPolicies {
+	cors := config.CORSConfig{
+		AllowOrigins: []string{"*"},
+		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"*"},
+	}
+	return routes.CORSPolicies{Public: cors, Admin: cors}
+}
+
 // SetupTestRouter creates a new router for testing with all routes and middleware
 // Note: Database initialization is now handled per-test using testcontainers
 func SetupTestRouter() *gin.Engine {
@@ -33,18 +42,6 @@ func SetupTestRouter() *gin.Engine {
 	// Create a new router for each test
 	router := gin.Default()
 
-	// Create minimal config for CORS
-	cfg := &config.Config{
-		CORS: config.CORSConfig{
-			AllowOrigins: []string{"*"},
-			AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowHeaders: []string{"*"},
-		},
-	}
-
-	// Apply middleware
-	router.Use(middleware.CORS(cfg))
-
 	// Create test container with no-op event publisher
 	container := &handlerContainer{
 		db:        database.Repo,
@@ -52,7 +49,7 @@ func SetupTestRouter() *gin.Engine {
 	}
 
 	// Register routes with container
-	routes.RegisterRoutes(router, container)
+	routes.RegisterRoutes(router, container, testCORSPolicies())
 
 	return router
 }
@@ -65,18 +62,6 @@ func SetupTestRouterWithEventPublisher(publisher messaging.EventPublisher) *gin.
 	// Create a new router for each test
 	router := gin.Default()
 
-	// Create minimal config for CORS
-	cfg := &config.Config{
-		CORS: config.CORSConfig{
-			AllowOrigins: []string{"*"},
-			AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowHeaders: []string{"*"},
-		},
-	}
-
-	// Apply middleware
-	router.Use(middleware.CORS(cfg))
-
 	// Create test container with provided event publisher
 	container := &handlerContainer{
 		db:        database.Repo,
@@ -84,7 +69,7 @@ func SetupTestRouterWithEventPublisher(publisher messaging.EventPublisher) *gin.
 	}
 
 	// Register routes with container
-	routes.RegisterRoutes(router, container)
+	routes.RegisterRoutes(router, container, testCORSPolicies())
 
 	return router
 }
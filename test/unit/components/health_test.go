@@ -0,0 +1,44 @@
+package components_test
+
+import (
+	"bank-api/internal/api/routes"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/components"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthzWithoutDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	container := &components.Container{
+		EventPublisher: messaging.NewNoOpEventPublisher(),
+	}
+	router := gin.New()
+	routes.RegisterRoutes(router, container, routes.CORSPolicies{})
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var body struct {
+		Status     string `json:"status"`
+		Components map[string]struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "unhealthy", body.Status)
+	assert.Equal(t, "down", body.Components["database"].Status)
+	assert.NotEmpty(t, body.Components["database"].Error)
+	assert.Equal(t, "up", body.Components["kafka"].Status)
+}
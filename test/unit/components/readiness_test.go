@@ -0,0 +1,50 @@
+package components_test
+
+import (
+	"bank-api/internal/api/routes"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/components"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getReadyz(t *testing.T, router *gin.Engine) (int, map[string]interface{}) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	return resp.Code, body
+}
+
+func TestReadyzDuringShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	container := &components.Container{
+		EventPublisher: messaging.NewNoOpEventPublisher(),
+		Server:         &http.Server{},
+	}
+	router := gin.New()
+	routes.RegisterRoutes(router, container, routes.CORSPolicies{})
+
+	code, body := getReadyz(t, router)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, true, body["kafka_healthy"])
+	assert.Contains(t, body, "in_flight_requests")
+
+	require.NoError(t, container.Shutdown(context.Background()))
+	assert.True(t, container.IsShuttingDown())
+
+	code, body = getReadyz(t, router)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "shutting_down", body["status"])
+}
@@ -0,0 +1,191 @@
+package database_test
+
+import (
+	"bank-api/internal/infrastructure/database"
+	"bank-api/internal/infrastructure/database/memory"
+	"bank-api/internal/infrastructure/database/postgres"
+	"bank-api/test/integration/testenv"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time check that the in-memory repository can stand in for PostgreSQL
+var _ database.Repository = (*memory.InMemoryRepository)(nil)
+
+func TestInMemoryRepository_Idempotency(t *testing.T) {
+	testenv.RunIdempotencySuite(t, func(t *testing.T) database.Repository {
+		return memory.NewInMemoryRepository(nil)
+	})
+}
+
+func TestInMemoryRepository_Limits(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(&postgres.Config{
+		MaxAccountBalance:       5000,
+		SavingsWithdrawalLimit:  2,
+		SavingsWithdrawalPeriod: "720h",
+	})
+
+	savingsID := repo.CreateAccountWithType(ctx, "Alice", "BRL", "savings")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, savingsID, 5000, "deposit-1")
+	require.NoError(t, err)
+
+	_, err = repo.AtomicDepositWithIdempotency(ctx, savingsID, 1, "deposit-2")
+	assert.ErrorIs(t, err, postgres.ErrBalanceLimitExceeded)
+
+	for i := 0; i < 2; i++ {
+		_, err = repo.AtomicWithdraw(ctx, savingsID, 100)
+		require.NoError(t, err)
+	}
+	_, err = repo.AtomicWithdraw(ctx, savingsID, 100)
+	assert.ErrorIs(t, err, postgres.ErrWithdrawalLimitExceeded)
+}
+
+// The balance cap is given in hundredths of the major unit, so R$ 50.00 caps a JPY account at
+// ¥50 and a KWD account at KWD 50.000
+func TestInMemoryRepository_BalanceLimitPerCurrency(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(&postgres.Config{MaxAccountBalance: 5000})
+
+	yenID := repo.CreateAccountWithCurrency(ctx, "Akira", "JPY")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, yenID, 50, "deposit-jpy-1")
+	require.NoError(t, err)
+	_, err = repo.AtomicDepositWithIdempotency(ctx, yenID, 1, "deposit-jpy-2")
+	assert.ErrorIs(t, err, postgres.ErrBalanceLimitExceeded)
+
+	dinarID := repo.CreateAccountWithCurrency(ctx, "Fatima", "KWD")
+	_, err = repo.AtomicDepositWithIdempotency(ctx, dinarID, 50000, "deposit-kwd-1")
+	require.NoError(t, err)
+	_, err = repo.AtomicDepositWithIdempotency(ctx, dinarID, 1, "deposit-kwd-2")
+	assert.ErrorIs(t, err, postgres.ErrBalanceLimitExceeded)
+}
+
+func TestInMemoryRepository_BalanceLimitOnTransfer(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(&postgres.Config{MaxAccountBalance: 5000})
+
+	fromID := repo.CreateAccount(ctx, "Alice")
+	toID := repo.CreateAccount(ctx, "Bob")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, fromID, 3000, "deposit-1")
+	require.NoError(t, err)
+	_, err = repo.AtomicDepositWithIdempotency(ctx, toID, 4000, "deposit-2")
+	require.NoError(t, err)
+
+	_, _, err = repo.AtomicTransfer(ctx, fromID, toID, 1001)
+	assert.ErrorIs(t, err, postgres.ErrBalanceLimitExceeded)
+
+	_, err = repo.AtomicBatchTransfer(ctx, fromID, []postgres.Transfer{{ToID: toID, Amount: 1001}})
+	assert.ErrorIs(t, err, postgres.ErrBalanceLimitExceeded)
+
+	from, ok := repo.GetAccount(ctx, fromID)
+	require.True(t, ok)
+	assert.Equal(t, 3000, from.Balance, "a refused transfer must not debit the source")
+
+	_, _, err = repo.AtomicTransfer(ctx, fromID, toID, 1000)
+	require.NoError(t, err)
+}
+
+func TestInMemoryRepository_InterestSkipsFrozenAndClampsToLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(&postgres.Config{MaxAccountBalance: 10000})
+
+	frozenID := repo.CreateAccount(ctx, "Frozen")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, frozenID, 5000, "deposit-1")
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFrozen(ctx, frozenID, true))
+
+	nearCapID := repo.CreateAccount(ctx, "NearCap")
+	_, err = repo.AtomicDepositWithIdempotency(ctx, nearCapID, 9950, "deposit-2")
+	require.NoError(t, err)
+
+	atCapID := repo.CreateAccount(ctx, "AtCap")
+	_, err = repo.AtomicDepositWithIdempotency(ctx, atCapID, 10000, "deposit-3")
+	require.NoError(t, err)
+
+	credits, err := repo.ApplyInterestWithCredits(ctx, 0.1)
+	require.NoError(t, err)
+	require.Len(t, credits, 1, "frozen and capped accounts earn nothing")
+	assert.Equal(t, nearCapID, credits[0].AccountID)
+	assert.Equal(t, 50, credits[0].Amount, "995 of interest is clamped to the room under the cap")
+	assert.Equal(t, 10000, credits[0].BalanceAfter)
+
+	frozen, ok := repo.GetAccount(ctx, frozenID)
+	require.True(t, ok)
+	assert.Equal(t, 5000, frozen.Balance)
+}
+
+func TestInMemoryRepository_HoldsAndOverdraft(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(nil)
+
+	accountID := repo.CreateAccount(ctx, "Alice")
+	_, err := repo.AtomicDepositWithIdempotency(ctx, accountID, 1000, "deposit-1")
+	require.NoError(t, err)
+
+	holdID, err := repo.PlaceHold(ctx, accountID, 800)
+	require.NoError(t, err)
+
+	_, err = repo.AtomicWithdraw(ctx, accountID, 300)
+	assert.ErrorIs(t, err, postgres.ErrInsufficientFunds, "held funds are not available")
+
+	require.NoError(t, repo.SetOverdraftLimit(ctx, accountID, 500))
+	account, err := repo.AtomicWithdraw(ctx, accountID, 300)
+	require.NoError(t, err)
+	assert.Equal(t, 700, account.Balance)
+
+	hold, err := repo.CaptureHold(ctx, holdID)
+	require.NoError(t, err)
+	assert.Equal(t, "captured", hold.Status)
+
+	_, err = repo.ReleaseHold(ctx, holdID)
+	assert.ErrorIs(t, err, postgres.ErrHoldNotActive)
+
+	account, found := repo.GetAccount(ctx, accountID)
+	require.True(t, found)
+	assert.Equal(t, -100, account.Balance)
+	assert.ErrorIs(t, repo.SetOverdraftLimit(ctx, accountID, 50), postgres.ErrOverdraftInUse)
+}
+
+func TestInMemoryRepository_TransferHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryRepository(nil)
+
+	ids, err := repo.CreateAccountsBulk(ctx, "load", 2, 1000)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	for i := 0; i < 3; i++ {
+		_, _, err = repo.AtomicTransfer(ctx, ids[0], ids[1], 100)
+		require.NoError(t, err)
+	}
+
+	page, err := repo.GetTransactionHistoryFiltered(ctx, ids[0], postgres.HistoryFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 2)
+	require.NotNil(t, page.Next)
+	assert.Equal(t, "transfer_out", page.Transactions[0]["type"])
+	assert.Equal(t, -100, page.Transactions[0]["amount"])
+	assert.Equal(t, 700, page.Transactions[0]["balance_after"])
+
+	page, err = repo.GetTransactionHistoryFiltered(ctx, ids[0], postgres.HistoryFilter{
+		Before:   page.Next.Before,
+		BeforeID: page.Next.BeforeID,
+		Limit:    2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 2)
+	assert.Nil(t, page.Next)
+	assert.Equal(t, "deposit", page.Transactions[1]["type"], "bulk creation records the opening deposit")
+
+	records, err := repo.ListTransactions(ctx, 1, time.Time{}, 100)
+	require.NoError(t, err)
+	require.Len(t, records, 8)
+	assert.Equal(t, records[2].ReferenceID, records[3].ReferenceID, "both legs of a transfer share a reference")
+
+	_, _, err = repo.AtomicTransfer(ctx, ids[0], 999, 100)
+	assert.ErrorIs(t, err, postgres.ErrAccountNotFound)
+}
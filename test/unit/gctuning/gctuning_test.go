@@ -0,0 +1,44 @@
+package gctuning_test
+
+import (
+	"bank-api/internal/config"
+	"bank-api/internal/pkg/gctuning"
+	"bank-api/internal/pkg/telemetry"
+	"runtime/debug"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallastSizeFollowsEnv(t *testing.T) {
+	t.Setenv("GO_BALLAST_MB", "8")
+	t.Cleanup(func() { gctuning.Apply(0) })
+
+	cfg := config.Load()
+	assert.Equal(t, 8, cfg.Runtime.BallastMB)
+
+	settings := gctuning.Apply(cfg.Runtime.BallastMB)
+	assert.Equal(t, 8<<20, settings.BallastBytes)
+	assert.Equal(t, float64(8<<20), testutil.ToFloat64(metrics.GCMetrics.WithLabelValues("ballast_bytes")))
+}
+
+func TestNoBallastByDefault(t *testing.T) {
+	t.Setenv("GO_BALLAST_MB", "")
+
+	cfg := config.Load()
+	assert.Equal(t, 0, cfg.Runtime.BallastMB)
+
+	settings := gctuning.Apply(cfg.Runtime.BallastMB)
+	assert.Zero(t, settings.BallastBytes)
+	assert.Zero(t, testutil.ToFloat64(metrics.GCMetrics.WithLabelValues("ballast_bytes")))
+}
+
+func TestGCPercentReported(t *testing.T) {
+	previous := debug.SetGCPercent(250)
+	t.Cleanup(func() { debug.SetGCPercent(previous) })
+
+	settings := gctuning.Apply(0)
+	assert.Equal(t, 250, settings.GCPercent)
+	assert.Equal(t, 250.0, testutil.ToFloat64(metrics.GCMetrics.WithLabelValues("gc_percent")))
+}
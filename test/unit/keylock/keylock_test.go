@@ -0,0 +1,54 @@
+package keylock_test
+
+import (
+	"bank-api/internal/pkg/keylock"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStripedStaysBounded touches far more keys than there are shards and checks that no new
+// locks are created, while each key keeps getting the same lock
+func TestStripedStaysBounded(t *testing.T) {
+	locks := keylock.NewStriped(64)
+
+	seen := make(map[*sync.Mutex]bool)
+	for id := 1; id <= 1_000_000; id++ {
+		seen[locks.For(id)] = true
+	}
+
+	assert.Equal(t, 64, locks.Size())
+	assert.Len(t, seen, 64, "every key should map onto one of the fixed locks")
+	assert.Same(t, locks.For(42), locks.For(42))
+	assert.Same(t, locks.For(1), locks.For(65), "keys equal modulo the shard count share a lock")
+	assert.NotSame(t, locks.For(1), locks.For(2))
+	assert.NotNil(t, locks.For(-7), "negative keys must not index out of range")
+}
+
+func TestStripedSerializesSameKey(t *testing.T) {
+	locks := keylock.NewStriped(8)
+
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu := locks.For(3)
+			mu.Lock()
+			counter++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, counter)
+}
+
+func TestNewStripedMinimumOneShard(t *testing.T) {
+	locks := keylock.NewStriped(0)
+
+	assert.Equal(t, 1, locks.Size())
+	assert.Same(t, locks.For(1), locks.For(2))
+}
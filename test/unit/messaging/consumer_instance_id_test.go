@@ -0,0 +1,45 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// consumerOnlyConfig returns the environment config with the idempotent producer turned off,
+// so sarama's validation only reports on the consumer settings
+func consumerOnlyConfig() *kafka.Config {
+	config := kafka.NewConfigFromEnv()
+	config.EnableIdempotence = false
+	return config
+}
+
+func TestConsumerInstanceID_DynamicByDefault(t *testing.T) {
+	saramaConfig, err := kafka.NewConfigFromEnv().ToSaramaConfig()
+	require.NoError(t, err)
+
+	assert.Empty(t, saramaConfig.Consumer.Group.InstanceId)
+}
+
+func TestConsumerInstanceID_FromEnv(t *testing.T) {
+	t.Setenv("KAFKA_CONSUMER_INSTANCE_ID", "banking-consumer-0")
+
+	config := consumerOnlyConfig()
+	assert.Equal(t, "banking-consumer-0", config.ConsumerInstanceID)
+
+	saramaConfig, err := config.ToSaramaConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "banking-consumer-0", saramaConfig.Consumer.Group.InstanceId)
+	assert.NoError(t, saramaConfig.Validate(), "the configured broker version must support static membership")
+}
+
+func TestConsumerInstanceID_Invalid(t *testing.T) {
+	config := consumerOnlyConfig()
+	config.ConsumerInstanceID = "banking/consumer 0"
+
+	saramaConfig, err := config.ToSaramaConfig()
+	require.NoError(t, err)
+	assert.Error(t, saramaConfig.Validate(), "sarama rejects IDs outside [a-zA-Z0-9._-]")
+}
@@ -0,0 +1,130 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEventLog parses every line of the event log at path
+func readEventLog(t *testing.T, path string) []messaging.EventLogEntry {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []messaging.EventLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry messaging.EventLogEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line %q", scanner.Text())
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestEventLogPublisher_TeesEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	capture := messaging.NewEventCapture()
+	publisher, err := messaging.NewEventLogPublisher(capture, path)
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.PublishAccountCreated(messaging.AccountCreatedEvent{AccountID: 1, Owner: "Alice"}))
+	require.NoError(t, publisher.PublishDepositRequested(messaging.DepositRequestedEvent{OperationID: "op-1", AccountID: 1, Amount: 500}))
+	require.NoError(t, publisher.PublishTransferCompleted(messaging.TransferCompletedEvent{FromAccountID: 1, ToAccountID: 2, Amount: 200}))
+	require.NoError(t, publisher.Close())
+
+	// The wrapped publisher still sees every event
+	assert.Len(t, capture.GetAccountCreatedEvents(), 1)
+	assert.Len(t, capture.GetDepositRequestedEvents(), 1)
+	assert.Len(t, capture.GetTransferCompletedEvents(), 1)
+
+	entries := readEventLog(t, path)
+	require.Len(t, entries, 3)
+	assert.Equal(t, kafka.TopicAccountCreated, entries[0].Topic)
+	assert.Equal(t, kafka.TopicDepositRequests, entries[1].Topic)
+	assert.Equal(t, kafka.TopicTransactionTransfer, entries[2].Topic)
+
+	var deposit messaging.DepositRequestedEvent
+	require.NoError(t, json.Unmarshal(entries[1].Event, &deposit))
+	assert.Equal(t, "op-1", deposit.OperationID)
+	assert.Equal(t, 500, deposit.Amount)
+	assert.Equal(t, messaging.EventTypeDepositRequested, deposit.EventType)
+	for _, entry := range entries {
+		assert.False(t, entry.Timestamp.IsZero())
+		assert.Empty(t, entry.Error)
+	}
+}
+
+func TestEventLogPublisher_ConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher, err := messaging.NewEventLogPublisher(messaging.NewNoOpEventPublisher(), path)
+	require.NoError(t, err)
+
+	const writers, perWriter = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				assert.NoError(t, publisher.PublishDepositCompleted(messaging.DepositCompletedEvent{AccountID: w, Amount: i}))
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.NoError(t, publisher.Close())
+
+	// Every line must parse on its own, so writes can't have interleaved
+	assert.Len(t, readEventLog(t, path), writers*perWriter)
+}
+
+// failingPublisher rejects every withdrawal request
+type failingPublisher struct {
+	messaging.NoOpEventPublisher
+}
+
+func (*failingPublisher) PublishWithdrawalRequested(messaging.WithdrawalRequestedEvent) error {
+	return errors.New("broker unavailable")
+}
+
+func TestEventLogPublisher_RecordsPublishErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher, err := messaging.NewEventLogPublisher(&failingPublisher{}, path)
+	require.NoError(t, err)
+
+	err = publisher.PublishWithdrawalRequested(messaging.WithdrawalRequestedEvent{AccountID: 1, Amount: 100})
+	assert.EqualError(t, err, "broker unavailable", "the wrapped publisher's error is returned unchanged")
+	require.NoError(t, publisher.Close())
+
+	entries := readEventLog(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, kafka.TopicWithdrawalRequests, entries[0].Topic)
+	assert.Equal(t, "broker unavailable", entries[0].Error)
+}
+
+func TestEventLogPublisher_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for i := 0; i < 2; i++ {
+		publisher, err := messaging.NewEventLogPublisher(messaging.NewNoOpEventPublisher(), path)
+		require.NoError(t, err)
+		require.NoError(t, publisher.PublishAccountClosed(messaging.AccountClosedEvent{AccountID: i}))
+		require.NoError(t, publisher.Close())
+	}
+
+	assert.Len(t, readEventLog(t, path), 2)
+}
+
+func TestEventLogPublisher_InvalidPath(t *testing.T) {
+	_, err := messaging.NewEventLogPublisher(messaging.NewNoOpEventPublisher(), filepath.Join(t.TempDir(), "missing", "events.jsonl"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// expectMetadata returns a checker asserting the published payload carries the given event type
+// at the current schema version
+func expectMetadata(eventType string) mocks.ValueChecker {
+	return func(val []byte) error {
+		var metadata messaging.EventMetadata
+		if err := json.Unmarshal(val, &metadata); err != nil {
+			return err
+		}
+		if metadata.EventVersion != messaging.CurrentEventVersion {
+			return fmt.Errorf("event_version = %q, want %q", metadata.EventVersion, messaging.CurrentEventVersion)
+		}
+		if metadata.EventType != eventType {
+			return fmt.Errorf("event_type = %q, want %q", metadata.EventType, eventType)
+		}
+		return nil
+	}
+}
+
+func TestKafkaPublisherStampsEventMetadata(t *testing.T) {
+	mockProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	mockProducer.ExpectSendMessageWithCheckerFunctionAndSucceed(expectMetadata(messaging.EventTypeDepositRequested))
+	mockProducer.ExpectSendMessageWithCheckerFunctionAndSucceed(expectMetadata(messaging.EventTypeTransferCompleted))
+	mockProducer.ExpectSendMessageWithCheckerFunctionAndSucceed(expectMetadata(messaging.EventTypeDeadLetter))
+
+	publisher := messaging.NewKafkaEventPublisherWithProducer(kafka.NewProducerWithClient(mockProducer, kafka.NewConfigFromEnv()))
+	defer publisher.Close()
+
+	require.NoError(t, publisher.PublishDepositRequested(messaging.DepositRequestedEvent{
+		OperationID: "op-1",
+		AccountID:   1,
+		Amount:      100,
+		Timestamp:   time.Now(),
+	}))
+	require.NoError(t, publisher.PublishTransferCompleted(messaging.TransferCompletedEvent{
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        100,
+		Timestamp:     time.Now(),
+	}))
+	require.NoError(t, publisher.PublishDeadLetter(messaging.DeadLetterEvent{
+		OriginalTopic: kafka.TopicDepositRequests,
+		Payload:       "{}",
+		Timestamp:     time.Now(),
+	}))
+}
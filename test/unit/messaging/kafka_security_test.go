@@ -0,0 +1,153 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a throwaway certificate and key as PEM files and returns their paths
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestKafkaSecurity_PlaintextByDefault(t *testing.T) {
+	saramaConfig, err := kafka.NewConfigFromEnv().ToSaramaConfig()
+	require.NoError(t, err)
+
+	assert.False(t, saramaConfig.Net.TLS.Enable)
+	assert.False(t, saramaConfig.Net.SASL.Enable)
+}
+
+func TestKafkaSecurity_SASLMechanisms(t *testing.T) {
+	tests := []struct {
+		mechanism string
+		expected  sarama.SASLMechanism
+		scram     bool
+	}{
+		{mechanism: "PLAIN", expected: sarama.SASLTypePlaintext},
+		{mechanism: "SCRAM-SHA-256", expected: sarama.SASLTypeSCRAMSHA256, scram: true},
+		{mechanism: "scram-sha-512", expected: sarama.SASLTypeSCRAMSHA512, scram: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mechanism, func(t *testing.T) {
+			config := kafka.NewConfigFromEnv()
+			config.SASLMechanism = tt.mechanism
+			config.SASLUsername = "banking"
+			config.SASLPassword = "secret"
+
+			saramaConfig, err := config.ToSaramaConfig()
+			require.NoError(t, err)
+
+			sasl := saramaConfig.Net.SASL
+			assert.True(t, sasl.Enable)
+			assert.True(t, sasl.Handshake)
+			assert.Equal(t, tt.expected, sasl.Mechanism)
+			assert.Equal(t, "banking", sasl.User)
+			assert.Equal(t, "secret", sasl.Password)
+			assert.False(t, saramaConfig.Net.TLS.Enable, "SASL alone must not turn on TLS")
+
+			if !tt.scram {
+				assert.Nil(t, sasl.SCRAMClientGeneratorFunc)
+				return
+			}
+			require.NotNil(t, sasl.SCRAMClientGeneratorFunc)
+			client := sasl.SCRAMClientGeneratorFunc()
+			require.NoError(t, client.Begin("banking", "secret", ""))
+			first, err := client.Step("")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(first, "n,,n=banking,r="), first)
+			assert.False(t, client.Done())
+		})
+	}
+}
+
+func TestKafkaSecurity_InvalidSASL(t *testing.T) {
+	config := kafka.NewConfigFromEnv()
+	config.SASLMechanism = "GSSAPI-ish"
+	config.SASLUsername = "banking"
+	_, err := config.ToSaramaConfig()
+	assert.Error(t, err)
+
+	config = kafka.NewConfigFromEnv()
+	config.SASLMechanism = "PLAIN"
+	_, err = config.ToSaramaConfig()
+	assert.Error(t, err, "a mechanism without a username should be rejected")
+}
+
+func TestKafkaSecurity_TLS(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+
+	config := kafka.NewConfigFromEnv()
+	config.TLSEnable = true
+	config.TLSCAFile = certFile
+	config.TLSCertFile = certFile
+	config.TLSKeyFile = keyFile
+	config.SASLMechanism = "SCRAM-SHA-512"
+	config.SASLUsername = "banking"
+
+	saramaConfig, err := config.ToSaramaConfig()
+	require.NoError(t, err)
+
+	assert.True(t, saramaConfig.Net.TLS.Enable)
+	tlsConfig := saramaConfig.Net.TLS.Config
+	require.NotNil(t, tlsConfig)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.NotNil(t, tlsConfig.RootCAs, "the CA file should replace the system roots")
+	assert.Len(t, tlsConfig.Certificates, 1, "the client certificate should be presented")
+	assert.True(t, saramaConfig.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), saramaConfig.Net.SASL.Mechanism)
+}
+
+func TestKafkaSecurity_InvalidTLS(t *testing.T) {
+	certFile, _ := writeSelfSignedCert(t)
+
+	config := kafka.NewConfigFromEnv()
+	config.TLSEnable = true
+	config.TLSCertFile = certFile // Key missing
+	_, err := config.ToSaramaConfig()
+	assert.Error(t, err)
+
+	config = kafka.NewConfigFromEnv()
+	config.TLSEnable = true
+	config.TLSCAFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = config.ToSaramaConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectKey returns a checker asserting the published message carries the given key,
+// where "" means no key at all
+func expectKey(expected string) mocks.MessageChecker {
+	return func(msg *sarama.ProducerMessage) error {
+		if expected == "" {
+			if msg.Key != nil {
+				return fmt.Errorf("expected no key, got %v", msg.Key)
+			}
+			return nil
+		}
+		if msg.Key == nil {
+			return fmt.Errorf("expected key %q, got none", expected)
+		}
+		key, err := msg.Key.Encode()
+		if err != nil {
+			return err
+		}
+		if string(key) != expected {
+			return fmt.Errorf("key = %q, want %q", key, expected)
+		}
+		return nil
+	}
+}
+
+func TestDepositRequestPartitionKey(t *testing.T) {
+	tests := []struct {
+		strategy string
+		expected string
+	}{
+		{strategy: "", expected: "7"},
+		{strategy: kafka.PartitionKeyAccountID, expected: "7"},
+		{strategy: kafka.PartitionKeyOperationID, expected: "op-1"},
+		{strategy: kafka.PartitionKeyRoundRobin, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run("strategy "+tt.strategy, func(t *testing.T) {
+			config := kafka.NewConfigFromEnv()
+			config.PartitionKeyStrategy = tt.strategy
+
+			mockProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+			mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectKey(tt.expected))
+			// Withdrawal requests keep their account key whatever the strategy
+			mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectKey("7"))
+
+			publisher := messaging.NewKafkaEventPublisherWithProducer(kafka.NewProducerWithClient(mockProducer, config))
+			defer publisher.Close()
+
+			require.NoError(t, publisher.PublishDepositRequested(messaging.DepositRequestedEvent{
+				OperationID: "op-1",
+				AccountID:   7,
+				Amount:      100,
+				Timestamp:   time.Now(),
+			}))
+			require.NoError(t, publisher.PublishWithdrawalRequested(messaging.WithdrawalRequestedEvent{
+				OperationID: "op-2",
+				AccountID:   7,
+				Amount:      100,
+				Timestamp:   time.Now(),
+			}))
+		})
+	}
+}
+
+func TestPartitioner_RoundRobinForUnkeyedMessages(t *testing.T) {
+	config := kafka.NewConfigFromEnv()
+	config.PartitionKeyStrategy = kafka.PartitionKeyRoundRobin
+
+	saramaConfig, err := config.ToSaramaConfig()
+	require.NoError(t, err)
+	partitioner := saramaConfig.Producer.Partitioner(kafka.TopicDepositRequests)
+
+	var unkeyed []int32
+	for i := 0; i < 6; i++ {
+		partition, err := partitioner.Partition(&sarama.ProducerMessage{Topic: kafka.TopicDepositRequests}, 3)
+		require.NoError(t, err)
+		unkeyed = append(unkeyed, partition)
+	}
+	assert.Equal(t, []int32{0, 1, 2, 0, 1, 2}, unkeyed)
+
+	// Keyed messages still land on the same partition every time
+	keyed := &sarama.ProducerMessage{Topic: kafka.TopicDepositRequests, Key: sarama.StringEncoder("7")}
+	first, err := partitioner.Partition(keyed, 3)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		partition, err := partitioner.Partition(keyed, 3)
+		require.NoError(t, err)
+		assert.Equal(t, first, partition)
+	}
+}
+
+func TestInvalidPartitionKeyStrategy(t *testing.T) {
+	config := kafka.NewConfigFromEnv()
+	config.PartitionKeyStrategy = "by-owner"
+
+	_, err := config.ToSaramaConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"bank-api/internal/pkg/telemetry"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	ID int `json:"id"`
+}
+
+func TestProducerMetrics(t *testing.T) {
+	const topic = "test.producer-metrics"
+
+	mockProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	mockProducer.ExpectSendMessageAndSucceed()
+	mockProducer.ExpectSendMessageAndSucceed()
+	mockProducer.ExpectSendMessageAndFail(errors.New("broker unavailable"))
+
+	producer := kafka.NewProducerWithClient(mockProducer, kafka.NewConfigFromEnv())
+
+	require.NoError(t, producer.PublishEvent(topic, "1", testEvent{ID: 1}))
+	require.NoError(t, producer.PublishEvent(topic, "2", testEvent{ID: 2}))
+	require.Error(t, producer.PublishEvent(topic, "3", testEvent{ID: 3}))
+
+	// Unserializable payload is dropped before reaching the broker
+	require.Error(t, producer.PublishEvent(topic, "4", make(chan int)))
+
+	// Publishing after close is dropped
+	require.NoError(t, producer.Close())
+	require.Error(t, producer.PublishEvent(topic, "5", testEvent{ID: 5}))
+
+	snapshot := producer.GetMetrics()
+	assert.Equal(t, int64(2), snapshot.SuccessCount)
+	assert.Equal(t, int64(1), snapshot.ErrorCount)
+	assert.Equal(t, int64(2), snapshot.DroppedCount)
+	assert.InDelta(t, 0.6, snapshot.ErrorRate, 0.0001)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.KafkaProducerMessagesTotal.WithLabelValues(topic)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.KafkaProducerErrorsTotal.WithLabelValues(topic)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.KafkaProducerDroppedTotal.WithLabelValues(topic)))
+	assert.InDelta(t, 0.6, testutil.ToFloat64(metrics.KafkaProducerErrorRate), 0.0001)
+}
@@ -0,0 +1,109 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectBackoff_GrowsWithJitterUpToMax(t *testing.T) {
+	backoff := &messaging.ReconnectBackoff{Initial: 100 * time.Millisecond, Max: 800 * time.Millisecond}
+
+	// Ceilings double per consecutive failure and stop at Max
+	ceilings := []time.Duration{100, 200, 400, 800, 800, 800}
+	for i, ceiling := range ceilings {
+		ceiling *= time.Millisecond
+		delay := backoff.Failure()
+
+		assert.GreaterOrEqual(t, delay, ceiling/2, "failure %d", i+1)
+		assert.LessOrEqual(t, delay, ceiling, "failure %d", i+1)
+	}
+	assert.Equal(t, len(ceilings), backoff.Failures())
+
+	backoff.Reset()
+	assert.Equal(t, 0, backoff.Failures())
+
+	delay := backoff.Failure()
+	assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+	assert.LessOrEqual(t, delay, 100*time.Millisecond, "Backoff should start over after a reset")
+}
+
+// TestConsumeWithBackoff_ResetsAfterSuccessfulSession drives the loop with a broker that is
+// unavailable for three sessions, accepts one, and then drops again
+func TestConsumeWithBackoff_ResetsAfterSuccessfulSession(t *testing.T) {
+	outcomes := []error{
+		sarama.ErrOutOfBrokers,
+		sarama.ErrOutOfBrokers,
+		sarama.ErrOutOfBrokers,
+		nil, // session ran and ended with a rebalance
+		sarama.ErrOutOfBrokers,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backoff := &messaging.ReconnectBackoff{Initial: 10 * time.Millisecond, Max: time.Second}
+	var failuresSeen []int
+	var calledAt []time.Time
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		messaging.ConsumeWithBackoff(ctx, "test-group", backoff, func(ctx context.Context) error {
+			failuresSeen = append(failuresSeen, backoff.Failures())
+			calledAt = append(calledAt, time.Now())
+
+			call := len(failuresSeen)
+			if call > len(outcomes) {
+				cancel()
+				return ctx.Err()
+			}
+			return outcomes[call-1]
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ConsumeWithBackoff did not stop after the context was cancelled")
+	}
+
+	// The backoff grows across the outage and starts over after the successful session
+	require.Equal(t, []int{0, 1, 2, 3, 0, 1}, failuresSeen)
+
+	// Waits before calls 2-4 are at least half of 10ms, 20ms and 40ms
+	minimumWaits := []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}
+	for i, minimum := range minimumWaits {
+		assert.GreaterOrEqual(t, calledAt[i+1].Sub(calledAt[i]), minimum, "wait before call %d", i+2)
+	}
+}
+
+func TestConsumeWithBackoff_StopsDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	backoff := &messaging.ReconnectBackoff{Initial: time.Hour, Max: time.Hour}
+	attempted := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		messaging.ConsumeWithBackoff(ctx, "test-group", backoff, func(context.Context) error {
+			attempted <- struct{}{}
+			return errors.New("broker unavailable")
+		})
+	}()
+
+	<-attempted
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Cancelling the context should interrupt the backoff wait")
+	}
+}
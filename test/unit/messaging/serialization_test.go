@@ -0,0 +1,185 @@
+package messaging_test
+
+import (
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/infrastructure/messaging/kafka"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleDepositRequest() messaging.DepositRequestedEvent {
+	return messaging.DepositRequestedEvent{
+		EventMetadata: messaging.EventMetadata{
+			EventVersion: messaging.CurrentEventVersion,
+			EventType:    messaging.EventTypeDepositRequested,
+		},
+		OperationID:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		IdempotencyKey: "5d41402abc4b2a76b9719d911017c5925d41402abc4b2a76b9719d911017c592",
+		AccountID:      42,
+		Amount:         1050,
+		TraceID:        "trace-123",
+		CallbackURL:    "https://example.com/hooks/deposits",
+		Timestamp:      time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC),
+	}
+}
+
+// publishAndCapture publishes a deposit request through a producer using format and returns
+// the message as a consumer would receive it
+func publishAndCapture(t *testing.T, format string, event messaging.DepositRequestedEvent) *sarama.ConsumerMessage {
+	var sent *sarama.ProducerMessage
+	mockProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		sent = msg
+		return nil
+	})
+
+	serializer, err := messaging.NewEventSerializer(format)
+	require.NoError(t, err)
+	producer := kafka.NewProducerWithClient(mockProducer, kafka.NewConfigFromEnv())
+	producer.SetSerializer(serializer)
+	publisher := messaging.NewKafkaEventPublisherWithProducer(producer)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.PublishDepositRequested(event))
+	require.NotNil(t, sent)
+
+	value, err := sent.Value.Encode()
+	require.NoError(t, err)
+	message := &sarama.ConsumerMessage{Topic: sent.Topic, Value: value}
+	for _, header := range sent.Headers {
+		message.Headers = append(message.Headers, &sarama.RecordHeader{Key: header.Key, Value: header.Value})
+	}
+	return message
+}
+
+func TestDepositRequestedRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		format      string
+		contentType string
+	}{
+		{format: kafka.SerializationJSON, contentType: kafka.ContentTypeJSON},
+		{format: kafka.SerializationProtobuf, contentType: kafka.ContentTypeProtobuf},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			event := sampleDepositRequest()
+			message := publishAndCapture(t, tt.format, event)
+
+			assert.Equal(t, tt.contentType, kafka.MessageContentType(message))
+
+			decoded, err := messaging.DecodeDepositRequested(message)
+			require.NoError(t, err)
+			assert.Equal(t, event.OperationID, decoded.OperationID)
+			assert.Equal(t, event.IdempotencyKey, decoded.IdempotencyKey)
+			assert.Equal(t, event.AccountID, decoded.AccountID)
+			assert.Equal(t, event.Amount, decoded.Amount)
+			assert.Equal(t, event.TraceID, decoded.TraceID)
+			assert.Equal(t, event.CallbackURL, decoded.CallbackURL)
+			assert.True(t, event.Timestamp.Equal(decoded.Timestamp), "timestamp %v != %v", decoded.Timestamp, event.Timestamp)
+			assert.Equal(t, messaging.CurrentEventVersion, decoded.EventVersion)
+			assert.Equal(t, messaging.EventTypeDepositRequested, decoded.EventType)
+		})
+	}
+}
+
+func TestProtobufPayloadIsSmaller(t *testing.T) {
+	event := sampleDepositRequest()
+	jsonMessage := publishAndCapture(t, kafka.SerializationJSON, event)
+	protoMessage := publishAndCapture(t, kafka.SerializationProtobuf, event)
+
+	assert.Less(t, len(protoMessage.Value), len(jsonMessage.Value))
+}
+
+// TestDecodeDepositRequestedWithoutHeader keeps messages published before the content-type
+// header existed readable
+func TestDecodeDepositRequestedWithoutHeader(t *testing.T) {
+	payload, err := json.Marshal(sampleDepositRequest())
+	require.NoError(t, err)
+
+	decoded, err := messaging.DecodeDepositRequested(&sarama.ConsumerMessage{Value: payload})
+	require.NoError(t, err)
+	assert.Equal(t, 42, decoded.AccountID)
+}
+
+func TestDecodeDepositRequestedRejectsGarbage(t *testing.T) {
+	for _, message := range []*sarama.ConsumerMessage{
+		{Value: []byte{0xff, 0x01}, Headers: []*sarama.RecordHeader{{Key: []byte("content-type"), Value: []byte(kafka.ContentTypeProtobuf)}}},
+		{Value: []byte("{}"), Headers: []*sarama.RecordHeader{{Key: []byte("content-type"), Value: []byte("application/avro")}}},
+		{Value: []byte("not json")},
+	} {
+		_, err := messaging.DecodeDepositRequested(message)
+		assert.Error(t, err)
+	}
+}
+
+func TestProtobufSerializerFallsBackToJSON(t *testing.T) {
+	payload, contentType, err := messaging.ProtobufSerializer{}.Serialize(messaging.TransferCompletedEvent{FromAccountID: 1, ToAccountID: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, kafka.ContentTypeJSON, contentType, "events without a protobuf schema stay JSON")
+	assert.True(t, json.Valid(payload))
+}
+
+func TestNewEventSerializerRejectsUnknownFormat(t *testing.T) {
+	_, err := messaging.NewEventSerializer("avro")
+	assert.Error(t, err)
+
+	config := kafka.NewConfigFromEnv()
+	config.EventSerialization = "avro"
+	_, err = config.ToSaramaConfig()
+	assert.Error(t, err)
+}
+
+// BenchmarkSerializeDepositRequested compares the cost of encoding the hot-path event
+func BenchmarkSerializeDepositRequested(b *testing.B) {
+	event := sampleDepositRequest()
+	for _, format := range []string{kafka.SerializationJSON, kafka.SerializationProtobuf} {
+		serializer, err := messaging.NewEventSerializer(format)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				payload, _, err := serializer.Serialize(event)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.SetBytes(int64(len(payload)))
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeDepositRequested compares the consumer-side decoding cost
+func BenchmarkDecodeDepositRequested(b *testing.B) {
+	event := sampleDepositRequest()
+	for _, format := range []string{kafka.SerializationJSON, kafka.SerializationProtobuf} {
+		serializer, err := messaging.NewEventSerializer(format)
+		if err != nil {
+			b.Fatal(err)
+		}
+		payload, contentType, err := serializer.Serialize(event)
+		if err != nil {
+			b.Fatal(err)
+		}
+		message := &sarama.ConsumerMessage{
+			Value:   payload,
+			Headers: []*sarama.RecordHeader{{Key: []byte("content-type"), Value: []byte(contentType)}},
+		}
+
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := messaging.DecodeDepositRequested(message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"bank-api/internal/api/middleware"
+	"bank-api/internal/config"
+	"bank-api/internal/pkg/logging"
+	"bank-api/internal/pkg/telemetry"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func histogramSampleCount(t *testing.T, method, endpoint, status string) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	observer := metrics.HTTPDuration.WithLabelValues(method, endpoint, status)
+	require.NoError(t, observer.(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logging.Init(&config.Config{Logging: config.LoggingConfig{Level: "info", Format: "json"}})
+	var logs bytes.Buffer
+	logging.SetOutput(&logs)
+	defer logging.SetOutput(os.Stdout)
+
+	router := gin.New()
+	router.Use(middleware.RequestContextMiddleware())
+	router.Use(middleware.AccessLog())
+	router.POST("/accounts/:id/deposit", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "accepted")
+	})
+
+	before := histogramSampleCount(t, "POST", "/accounts/:id/deposit", "202")
+
+	req := httptest.NewRequest("POST", "/accounts/42/deposit", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	// Find the access log line among the request context start/finish lines
+	var entry logging.LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var candidate logging.LogEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &candidate))
+		if candidate.Message == "HTTP request" {
+			entry = candidate
+		}
+	}
+	require.Equal(t, "HTTP request", entry.Message, "access log entry not found in: %s", logs.String())
+
+	assert.Equal(t, "POST", entry.Fields["method"])
+	assert.Equal(t, "/accounts/42/deposit", entry.Fields["path"])
+	assert.Equal(t, "/accounts/:id/deposit", entry.Fields["endpoint"])
+	assert.Equal(t, float64(http.StatusAccepted), entry.Fields["status"])
+	assert.Equal(t, float64(len("accepted")), entry.Fields["bytes"])
+	assert.NotEmpty(t, entry.Fields["request_id"])
+	assert.Contains(t, entry.Fields, "duration_ms")
+	assert.Contains(t, entry.Fields, "latency_bucket")
+
+	// Labelled by route pattern, not the raw path
+	assert.Equal(t, before+1, histogramSampleCount(t, "POST", "/accounts/:id/deposit", "202"))
+	assert.Zero(t, histogramSampleCount(t, "POST", "/accounts/42/deposit", "202"))
+}
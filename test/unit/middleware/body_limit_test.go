@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"bank-api/internal/api/middleware"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyLimitRouter echoes the size of the body its handler managed to read
+func bodyLimitRouter(limit int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.MaxBodySize(limit))
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"read": len(body)})
+	})
+	return router
+}
+
+func TestMaxBodySize(t *testing.T) {
+	const limit = 64
+
+	tests := []struct {
+		name    string
+		size    int
+		chunked bool
+		status  int
+	}{
+		{name: "under limit", size: limit - 1, status: http.StatusOK},
+		{name: "at limit", size: limit, status: http.StatusOK},
+		{name: "over limit", size: limit + 1, status: http.StatusRequestEntityTooLarge},
+		{name: "chunked under limit", size: limit, chunked: true, status: http.StatusOK},
+		{name: "chunked over limit", size: 10 * limit, chunked: true, status: http.StatusRequestEntityTooLarge},
+	}
+
+	router := bodyLimitRouter(limit)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", tt.size)))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.status, resp.Code)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			if tt.status == http.StatusOK {
+				assert.Equal(t, float64(tt.size), body["read"], "the handler must still see the whole body")
+			} else {
+				assert.Equal(t, "REQUEST_TOO_LARGE", body["code"])
+			}
+		})
+	}
+}
+
+func TestMaxBodySize_Disabled(t *testing.T) {
+	router := bodyLimitRouter(0)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 1<<16)))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
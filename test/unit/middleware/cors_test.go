@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"bank-api/internal/api/routes"
+	"bank-api/internal/config"
+	"bank-api/internal/infrastructure/messaging"
+	"bank-api/internal/pkg/components"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dashboardOrigin = "http://localhost:5173"
+	opsOrigin       = "https://ops.internal"
+	unknownOrigin   = "https://evil.example"
+)
+
+// setupCORSRouter registers the real routes with a public policy that allows the dashboard
+// and an admin policy that only allows the internal ops origin
+func setupCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	public := config.CORSConfig{
+		AllowOrigins: []string{dashboardOrigin},
+		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Content-Type"},
+	}
+	admin := public
+	admin.AllowOrigins = []string{opsOrigin}
+	admin.RejectDisallowedOrigins = true
+
+	container := &components.Container{
+		EventPublisher: messaging.NewNoOpEventPublisher(),
+	}
+	router := gin.New()
+	routes.RegisterRoutes(router, container, routes.CORSPolicies{Public: public, Admin: admin})
+	return router
+}
+
+func preflight(router *gin.Engine, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestCORS_AdminGroup(t *testing.T) {
+	router := setupCORSRouter()
+
+	t.Run("allowed origin passes preflight", func(t *testing.T) {
+		resp := preflight(router, "/accounts/1/freeze", opsOrigin)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Equal(t, opsOrigin, resp.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed origin is rejected at preflight", func(t *testing.T) {
+		resp := preflight(router, "/accounts/1/freeze", unknownOrigin)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("public origin is rejected on admin routes", func(t *testing.T) {
+		resp := preflight(router, "/accounts/1/overdraft", dashboardOrigin)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("disallowed origin is rejected on simple requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Origin", unknownOrigin)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("requests without an origin are unaffected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestCORS_PublicGroup(t *testing.T) {
+	router := setupCORSRouter()
+
+	t.Run("allowed origin passes preflight", func(t *testing.T) {
+		resp := preflight(router, "/accounts/1/deposit", dashboardOrigin)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Equal(t, dashboardOrigin, resp.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed origin gets the fallback origin instead of a rejection", func(t *testing.T) {
+		resp := preflight(router, "/accounts/1/deposit", unknownOrigin)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Equal(t, dashboardOrigin, resp.Header().Get("Access-Control-Allow-Origin"),
+			"The browser blocks the response since the origin doesn't match")
+	})
+
+	t.Run("admin origin is not granted public routes", func(t *testing.T) {
+		resp := preflight(router, "/accounts", opsOrigin)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.NotEqual(t, opsOrigin, resp.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"bank-api/internal/api/middleware"
+	"bank-api/internal/pkg/telemetry"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestsShed(t *testing.T) float64 {
+	var m dto.Metric
+	require.NoError(t, metrics.RequestsShedTotal.Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestMaxInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const limit = 2
+	entered := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.Use(middleware.MaxInFlight(limit, "/healthz"))
+	router.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+	router.GET("/healthz", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	before := requestsShed(t)
+
+	// Occupy every slot
+	var wg sync.WaitGroup
+	slow := make([]*httptest.ResponseRecorder, limit)
+	for i := range slow {
+		slow[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(resp *httptest.ResponseRecorder) {
+			defer wg.Done()
+			router.ServeHTTP(resp, httptest.NewRequest("GET", "/slow", nil))
+		}(slow[i])
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+
+	// Excess requests are shed without reaching the handler
+	const excess = 3
+	for i := 0; i < excess; i++ {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest("GET", "/slow", nil))
+
+		require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, "SERVICE_OVERLOADED", body["code"])
+	}
+	assert.Equal(t, float64(excess), requestsShed(t)-before)
+
+	// Exempt paths are served even at the limit
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	close(release)
+	wg.Wait()
+	for _, resp := range slow {
+		assert.Equal(t, http.StatusOK, resp.Code)
+	}
+
+	// Freed slots accept requests again
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/slow", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, entered, 1)
+}
+
+func TestMaxInFlightDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.MaxInFlight(0))
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/ping", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"bank-api/internal/api/middleware"
+	"bank-api/internal/pkg/tracing"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContextMiddleware_TraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string // "" means a generated ID is expected
+	}{
+		{name: "client ID is kept", header: "checkout-7f3a9c", expected: "checkout-7f3a9c"},
+		{name: "surrounding whitespace is trimmed", header: "  abc-123  ", expected: "abc-123"},
+		{name: "missing header", header: ""},
+		{name: "oversized header", header: strings.Repeat("a", 129)},
+		{name: "non-printable header", header: "abc\x01def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			router := gin.New()
+			router.Use(middleware.RequestContextMiddleware())
+			router.GET("/ping", func(c *gin.Context) {
+				fromContext = tracing.FromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/ping", nil)
+			if tt.header != "" {
+				req.Header.Set(tracing.Header, tt.header)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			echoed := resp.Header().Get(tracing.Header)
+			assert.NotEmpty(t, echoed)
+			assert.Equal(t, echoed, fromContext, "Handlers should see the ID returned to the client")
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, echoed)
+			} else {
+				assert.NotEqual(t, strings.TrimSpace(tt.header), echoed)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package money_test
+
+import (
+	"bank-api/internal/pkg/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinorUnits(t *testing.T) {
+	cases := map[string]int{
+		"BRL": 2,
+		"USD": 2,
+		"EUR": 2,
+		"JPY": 0,
+		"KWD": 3,
+		"":    money.DefaultMinorUnits,
+		"XYZ": money.DefaultMinorUnits,
+	}
+	for currency, expected := range cases {
+		assert.Equal(t, expected, money.MinorUnits(currency), currency)
+	}
+
+	assert.Equal(t, 100, money.Factor("BRL"))
+	assert.Equal(t, 1, money.Factor("JPY"))
+	assert.Equal(t, 1000, money.Factor("KWD"))
+}
+
+func TestConversions(t *testing.T) {
+	cases := []struct {
+		currency string
+		amount   int
+		decimal  float64
+	}{
+		{"BRL", 1050, 10.50},
+		{"BRL", -250, -2.50},
+		{"JPY", 1050, 1050},
+		{"KWD", 1050, 1.050},
+		{"KWD", 1, 0.001},
+	}
+	for _, c := range cases {
+		assert.InDelta(t, c.decimal, money.ToDecimal(c.amount, c.currency), 1e-9, c.currency)
+		assert.Equal(t, c.amount, money.FromDecimal(c.decimal, c.currency), c.currency)
+	}
+}
+
+// TestFromDecimalRounds guards against truncation: 0.29*100 is 28.999... in floating point
+func TestFromDecimalRounds(t *testing.T) {
+	assert.Equal(t, 29, money.FromDecimal(0.29, "BRL"))
+	assert.Equal(t, 4567, money.FromDecimal(4.567, "KWD"))
+	assert.Equal(t, -29, money.FromDecimal(-0.29, "BRL"))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "R$ 0.05", money.Format(5, "BRL"))
+	assert.Equal(t, "R$ 999.99", money.Format(99999, "BRL"))
+	assert.Equal(t, "R$ 1,000.00", money.Format(100000, "BRL"))
+	assert.Equal(t, "R$ 1,234,567.89", money.Format(123456789, "BRL"))
+	assert.Equal(t, "R$ -2.50", money.Format(-250, "BRL"))
+	assert.Equal(t, "US$ 10.00", money.Format(1000, "USD"))
+	assert.Equal(t, "¥ 10,000", money.Format(10000, "JPY"))
+	assert.Equal(t, "KWD 1.500", money.Format(1500, "KWD"))
+}
@@ -0,0 +1,57 @@
+package telemetry_test
+
+import (
+	"bank-api/internal/pkg/telemetry"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cpuUsagePercent(t *testing.T) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, metrics.CPUMetrics.WithLabelValues("usage_percent").(prometheus.Metric).Write(m))
+	return m.GetGauge().GetValue()
+}
+
+// spin keeps every scheduler thread busy for d
+func spin(d time.Duration) {
+	deadline := time.Now().Add(d)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := 0; time.Now().Before(deadline); x++ {
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestCPUUsageRisesUnderLoad checks that usage_percent comes from real process CPU time:
+// an interval spent spinning must report more usage than one spent sleeping
+func TestCPUUsageRisesUnderLoad(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("process CPU accounting requires /proc")
+	}
+
+	metrics.UpdateSystemMetrics() // Baseline sample
+
+	time.Sleep(300 * time.Millisecond)
+	metrics.UpdateSystemMetrics()
+	idle := cpuUsagePercent(t)
+
+	spin(300 * time.Millisecond)
+	metrics.UpdateSystemMetrics()
+	busy := cpuUsagePercent(t)
+
+	assert.Greater(t, busy, idle, "usage should rise while the CPU is busy")
+	assert.LessOrEqual(t, busy, 100.0)
+}
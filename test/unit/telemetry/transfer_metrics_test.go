@@ -0,0 +1,45 @@
+package telemetry_test
+
+import (
+	"bank-api/internal/pkg/telemetry"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferAmountBucket(t *testing.T) {
+	cases := map[int]string{
+		1:                                   "small",
+		5000:                                "small",
+		metrics.TransferAmountSmallMax:      "small",
+		metrics.TransferAmountSmallMax + 1:  "medium",
+		50000:                               "medium",
+		metrics.TransferAmountMediumMax:     "medium",
+		metrics.TransferAmountMediumMax + 1: "large",
+		1000000:                             "large",
+	}
+
+	for amount, want := range cases {
+		assert.Equal(t, want, metrics.TransferAmountBucket(amount), "amount %d", amount)
+	}
+}
+
+// sampleCount returns how many observations the transfer histogram holds for a bucket label
+func sampleCount(t *testing.T, bucket string) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, metrics.TransferDurationHistogram.WithLabelValues(bucket).(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRecordTransferDurationUsesAmountBucket(t *testing.T) {
+	small, large := sampleCount(t, "small"), sampleCount(t, "large")
+
+	metrics.RecordTransferDuration(750000, 20*time.Millisecond)
+
+	assert.Equal(t, large+1, sampleCount(t, "large"))
+	assert.Equal(t, small, sampleCount(t, "small"))
+}
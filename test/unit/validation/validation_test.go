@@ -0,0 +1,165 @@
+package validation_test
+
+import (
+	"bank-api/internal/pkg/validation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAmountDefaultLimits(t *testing.T) {
+	assert.NoError(t, validation.ValidateAmount(1, "BRL"))
+	assert.NoError(t, validation.ValidateAmount(validation.DefaultMaxAmount, "BRL"))
+
+	assert.EqualError(t, validation.ValidateAmount(0, "BRL"), "amount must be greater than zero")
+	assert.EqualError(t, validation.ValidateAmount(-5, "BRL"), "amount must be greater than zero")
+	assert.EqualError(t, validation.ValidateAmount(validation.DefaultMaxAmount+1, "BRL"),
+		"amount exceeds maximum limit of R$ 10,000.00")
+}
+
+// TestValidateAmountPerCurrency checks the limits apply at the same face value in every currency
+func TestValidateAmountPerCurrency(t *testing.T) {
+	min, max := validation.AmountLimits("JPY")
+	assert.Equal(t, 1, min, "A currency without cents can't go below one unit")
+	assert.Equal(t, 10000, max)
+	assert.NoError(t, validation.ValidateAmount(10000, "JPY"))
+	assert.EqualError(t, validation.ValidateAmount(10001, "JPY"), "amount exceeds maximum limit of ¥ 10,000")
+
+	min, max = validation.AmountLimits("KWD")
+	assert.Equal(t, 10, min)
+	assert.Equal(t, 10000000, max)
+	assert.EqualError(t, validation.ValidateAmount(9, "KWD"), "amount is below minimum limit of KWD 0.010")
+	assert.NoError(t, validation.ValidateAmount(10000000, "KWD"))
+	assert.EqualError(t, validation.ValidateAmount(10000001, "KWD"), "amount exceeds maximum limit of KWD 10,000.000")
+}
+
+func TestValidateOverdraftLimitPerCurrency(t *testing.T) {
+	assert.NoError(t, validation.ValidateOverdraftLimit(0, "BRL"))
+	assert.NoError(t, validation.ValidateOverdraftLimit(validation.MaxOverdraftLimit, "BRL"))
+	assert.EqualError(t, validation.ValidateOverdraftLimit(-1, "BRL"), "overdraft limit cannot be negative")
+	assert.EqualError(t, validation.ValidateOverdraftLimit(validation.MaxOverdraftLimit+1, "BRL"),
+		"overdraft limit exceeds maximum of R$ 10,000.00")
+
+	assert.NoError(t, validation.ValidateOverdraftLimit(10000, "JPY"))
+	assert.EqualError(t, validation.ValidateOverdraftLimit(10001, "JPY"), "overdraft limit exceeds maximum of ¥ 10,000")
+
+	assert.NoError(t, validation.ValidateOverdraftLimit(10000000, "KWD"))
+	assert.EqualError(t, validation.ValidateOverdraftLimit(10000001, "KWD"),
+		"overdraft limit exceeds maximum of KWD 10,000.000")
+}
+
+func TestValidateAmountConfiguredLimits(t *testing.T) {
+	require.NoError(t, validation.SetAmountLimits(500, 250000))
+	t.Cleanup(func() {
+		require.NoError(t, validation.SetAmountLimits(validation.DefaultMinAmount, validation.DefaultMaxAmount))
+	})
+
+	assert.NoError(t, validation.ValidateAmount(500, "BRL"))
+	assert.NoError(t, validation.ValidateAmount(250000, "BRL"))
+	assert.EqualError(t, validation.ValidateAmount(499, "BRL"), "amount is below minimum limit of R$ 5.00")
+	assert.EqualError(t, validation.ValidateAmount(250001, "BRL"), "amount exceeds maximum limit of R$ 2,500.00")
+	assert.EqualError(t, validation.ValidateAmount(4, "JPY"), "amount is below minimum limit of ¥ 5")
+}
+
+func TestSetAmountLimitsRejectsInvalidRange(t *testing.T) {
+	assert.Error(t, validation.SetAmountLimits(0, 1000))
+	assert.Error(t, validation.SetAmountLimits(1000, 999))
+
+	// Rejected limits leave the current ones in place
+	assert.NoError(t, validation.ValidateAmount(validation.DefaultMaxAmount, "BRL"))
+}
+
+func TestParseMoney(t *testing.T) {
+	valid := map[string]int{
+		"10.50":    1050,
+		"0.01":     1,
+		"10.5":     1050,
+		"10":       1000,
+		"0":        0,
+		"10000.00": 1000000,
+	}
+	for input, expected := range valid {
+		cents, err := validation.ParseMoney(input, 2)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, cents, input)
+	}
+
+	_, err := validation.ParseMoney("10.505", 2)
+	assert.ErrorContains(t, err, "at most 2 decimal places")
+
+	for _, input := range []string{"", ".50", "10.", "-1.00", "+1", "1e3", "1,000.00", " 10", "ten", "99999999999999999999"} {
+		_, err := validation.ParseMoney(input, 2)
+		assert.Error(t, err, input)
+	}
+}
+
+// TestParseMoneyMinorUnits scales by the currency's decimal places instead of always by 100
+func TestParseMoneyMinorUnits(t *testing.T) {
+	yen, err := validation.ParseMoney("1050", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1050, yen)
+
+	_, err = validation.ParseMoney("10.5", 0)
+	assert.ErrorContains(t, err, "at most 0 decimal places")
+
+	fils, err := validation.ParseMoney("10.505", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 10505, fils)
+
+	fils, err = validation.ParseMoney("1.5", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1500, fils)
+
+	_, err = validation.ParseMoney("1.5055", 3)
+	assert.ErrorContains(t, err, "at most 3 decimal places")
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	assert.NoError(t, validation.ValidateCallbackURL("https://example.com/hooks/deposits"))
+	assert.NoError(t, validation.ValidateCallbackURL("http://203.0.113.10:8080/hook"))
+
+	for _, callbackURL := range []string{"not a url", "ftp://example.com/hook", "/relative/path"} {
+		assert.ErrorContains(t, validation.ValidateCallbackURL(callbackURL), "absolute http or https URL", callbackURL)
+	}
+
+	private := []string{
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.20/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	}
+	for _, callbackURL := range private {
+		assert.ErrorContains(t, validation.ValidateCallbackURL(callbackURL), "private or loopback host", callbackURL)
+	}
+
+	validation.SetAllowPrivateCallbackHosts(true)
+	t.Cleanup(func() { validation.SetAllowPrivateCallbackHosts(false) })
+	for _, callbackURL := range private {
+		assert.NoError(t, validation.ValidateCallbackURL(callbackURL), callbackURL)
+	}
+}
+
+func TestValidateCurrency(t *testing.T) {
+	for _, code := range []string{"BRL", "USD", "EUR", "JPY", "KWD"} {
+		assert.NoError(t, validation.ValidateCurrency(code))
+	}
+	for _, code := range []string{"", "brl", "GBP", "REAL"} {
+		assert.Error(t, validation.ValidateCurrency(code), code)
+	}
+}
+
+func TestValidateAccountType(t *testing.T) {
+	for _, accountType := range []string{"checking", "savings"} {
+		assert.NoError(t, validation.ValidateAccountType(accountType))
+	}
+	for _, accountType := range []string{"", "Savings", "brokerage"} {
+		assert.Error(t, validation.ValidateAccountType(accountType), accountType)
+	}
+}